@@ -0,0 +1,172 @@
+// cmd/auditLog.go
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/export"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const allEntities = "All"
+
+// auditPageSize is how many audit entries are listed at a time
+const auditPageSize = 100
+
+// showAuditLogDialog lists the audit log filtered by entity, user and date, a
+// page at a time. Selecting an entry shows the record before and after the
+// change.
+func showAuditLogDialog(window fyne.Window, db *sql.DB) {
+	entitySelect := widget.NewSelect(append([]string{allEntities}, internal.AuditEntities...), nil)
+	entitySelect.SetSelected(allEntities)
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("From (YYYY-MM-DD)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("To (YYYY-MM-DD)")
+	userEntry := widget.NewEntry()
+	userEntry.SetPlaceHolder("User")
+
+	var entries []internal.AuditEntry
+	var offset int
+	countLabel := widget.NewLabel("")
+	newerBtn := widget.NewButton("Newer", nil)
+	olderBtn := widget.NewButton("Older", nil)
+
+	table := widget.NewTable(
+		func() (int, int) { return len(entries) + 1, 6 },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			if id.Row == 0 {
+				label.SetText([]string{"Time", "User", "Entity", "ID", "Action", "Changed"}[id.Col])
+				return
+			}
+
+			e := entries[id.Row-1]
+			label.SetText([]string{
+				e.CreatedAt.Format("2006-01-02 15:04:05"),
+				e.User,
+				e.Entity,
+				strconv.FormatInt(e.EntityID, 10),
+				e.Action,
+				strings.Join(e.ChangedFields(), ", "),
+			}[id.Col])
+		},
+	)
+	table.SetColumnWidth(0, 160)
+	table.SetColumnWidth(1, 120)
+	table.SetColumnWidth(2, 120)
+	table.SetColumnWidth(3, 60)
+	table.SetColumnWidth(4, 90)
+	table.SetColumnWidth(5, 320)
+
+	table.OnSelected = func(id widget.TableCellID) {
+		table.UnselectAll()
+		if id.Row > 0 {
+			showAuditEntryDialog(window, entries[id.Row-1])
+		}
+	}
+
+	load := func() {
+		filter, err := export.ParseFilter(fromEntry.Text, toEntry.Text, "", loadBusinessHours(db).Location())
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		// One more entry than shown tells whether there is an older page
+		auditFilter := internal.AuditFilter{
+			User:   strings.TrimSpace(userEntry.Text),
+			From:   filter.From,
+			To:     filter.To,
+			Limit:  auditPageSize + 1,
+			Offset: offset,
+		}
+		if entitySelect.Selected != allEntities {
+			auditFilter.Entity = entitySelect.Selected
+		}
+
+		entries, err = internal.LoadAuditLog(db, auditFilter)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		older := len(entries) > auditPageSize
+		if older {
+			entries = entries[:auditPageSize]
+		}
+		if len(entries) == 0 {
+			countLabel.SetText("No changes")
+		} else {
+			countLabel.SetText(fmt.Sprintf("Changes %d to %d", offset+1, offset+len(entries)))
+		}
+		setEnabled(newerBtn, offset > 0)
+		setEnabled(olderBtn, older)
+		table.Refresh()
+	}
+	newerBtn.OnTapped = func() {
+		offset -= auditPageSize
+		load()
+	}
+	olderBtn.OnTapped = func() {
+		offset += auditPageSize
+		load()
+	}
+
+	controls := container.NewVBox(
+		container.NewGridWithColumns(4, entitySelect, userEntry, fromEntry, toEntry),
+		container.NewHBox(widget.NewButton("Apply Filter", func() {
+			offset = 0
+			load()
+		}), countLabel, newerBtn, olderBtn),
+	)
+
+	load()
+
+	auditDialog := dialog.NewCustom("Audit Log", "Close",
+		container.NewBorder(controls, nil, nil, nil, table), window)
+	auditDialog.Resize(fyne.NewSize(900, 600))
+	auditDialog.Show()
+}
+
+// showAuditEntryDialog shows the JSON of a record before and after a change
+func showAuditEntryDialog(window fyne.Window, e internal.AuditEntry) {
+	side := func(title, data string) fyne.CanvasObject {
+		text := widget.NewMultiLineEntry()
+		text.SetText(indentJSON(data))
+		text.Wrapping = fyne.TextWrapWord
+		return container.NewBorder(widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			nil, nil, nil, text)
+	}
+
+	title := e.Action + " " + e.Entity + " " + strconv.FormatInt(e.EntityID, 10) +
+		" by " + e.User + " at " + e.CreatedAt.Format("2006-01-02 15:04:05")
+	entryDialog := dialog.NewCustom(title, "Close",
+		container.NewGridWithColumns(2, side("Before", e.Before), side("After", e.After)), window)
+	entryDialog.Resize(fyne.NewSize(800, 500))
+	entryDialog.Show()
+}
+
+// indentJSON formats JSON for reading, leaving other text as it is
+func indentJSON(data string) string {
+	if data == "" {
+		return "(none)"
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(data), "", "  "); err != nil {
+		return data
+	}
+	return out.String()
+}
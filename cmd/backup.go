@@ -0,0 +1,194 @@
+// cmd/backup.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/backup"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Preference keys of the automatic backups, which are kept on this computer
+const (
+	prefBackupEnabled  = "backup.enabled"
+	prefBackupDir      = "backup.dir"
+	prefBackupInterval = "backup.interval_hours"
+	prefBackupKeep     = "backup.keep"
+)
+
+// backupSettings are the automatic backup preferences
+type backupSettings struct {
+	Enabled       bool
+	Dir           string
+	IntervalHours int
+	Keep          int
+}
+
+func loadBackupSettings() backupSettings {
+	prefs := fyne.CurrentApp().Preferences()
+	return backupSettings{
+		Enabled:       prefs.Bool(prefBackupEnabled),
+		Dir:           prefs.String(prefBackupDir),
+		IntervalHours: prefs.IntWithFallback(prefBackupInterval, 24),
+		Keep:          prefs.IntWithFallback(prefBackupKeep, 14),
+	}
+}
+
+// startBackupScheduler makes automatic backups of the profile's database until
+// ctx is cancelled. Every profile gets its own directory, so that keeping the
+// newest backups of one never deletes those of another.
+func startBackupScheduler(ctx context.Context, database *sql.DB, profile string) {
+	settings := loadBackupSettings()
+	if !settings.Enabled || settings.Dir == "" {
+		return
+	}
+	dir := filepath.Join(settings.Dir, profile)
+	interval := time.Duration(settings.IntervalHours) * time.Hour
+	go backup.NewScheduler(database, dir, interval, settings.Keep).Run(ctx)
+}
+
+// showBackupNowDialog saves a backup of the connected database to a file
+func showBackupNowDialog(window fyne.Window, database *sql.DB) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+
+		archive, err := backup.Create(database)
+		if err == nil {
+			err = backup.Write(writer, archive)
+		}
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Backup",
+			fmt.Sprintf("Backed up %d rows from %d tables", archive.Rows(), len(archive.Tables)), window)
+	}, window)
+	saveDialog.SetFileName(backup.FileName(time.Now()))
+	saveDialog.Show()
+}
+
+// showAutomaticBackupsDialog edits the automatic backup preferences. They take
+// effect the next time a database is opened.
+func showAutomaticBackupsDialog(window fyne.Window) {
+	settings := loadBackupSettings()
+
+	enabledCheck := widget.NewCheck("Back up automatically", nil)
+	enabledCheck.SetChecked(settings.Enabled)
+
+	dirEntry := widget.NewEntry()
+	dirEntry.SetPlaceHolder("Folder for the backups")
+	dirEntry.SetText(settings.Dir)
+	browseBtn := widget.NewButton("Browse...", func() {
+		dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+			if err == nil && dir != nil {
+				dirEntry.SetText(dir.Path())
+			}
+		}, window)
+	})
+
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText(strconv.Itoa(settings.IntervalHours))
+	keepEntry := widget.NewEntry()
+	keepEntry.SetText(strconv.Itoa(settings.Keep))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("", enabledCheck),
+		widget.NewFormItem("Folder", dirEntry),
+		widget.NewFormItem("", browseBtn),
+		widget.NewFormItem("Every (hours)", intervalEntry),
+		widget.NewFormItem("Keep (backups)", keepEntry),
+	}
+
+	formDialog := dialog.NewForm("Automatic Backups", "Save", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+
+		interval, err := strconv.Atoi(intervalEntry.Text)
+		if err != nil || interval < 1 {
+			dialog.ShowError(fmt.Errorf("Invalid interval"), window)
+			return
+		}
+		keep, err := strconv.Atoi(keepEntry.Text)
+		if err != nil || keep < 0 {
+			dialog.ShowError(fmt.Errorf("Invalid number of backups to keep"), window)
+			return
+		}
+		if enabledCheck.Checked && dirEntry.Text == "" {
+			dialog.ShowError(fmt.Errorf("Choose a folder for the backups"), window)
+			return
+		}
+
+		prefs := fyne.CurrentApp().Preferences()
+		prefs.SetBool(prefBackupEnabled, enabledCheck.Checked)
+		prefs.SetString(prefBackupDir, dirEntry.Text)
+		prefs.SetInt(prefBackupInterval, interval)
+		prefs.SetInt(prefBackupKeep, keep)
+		dialog.ShowInformation("Automatic Backups", "Saved. The change takes effect the next time a database is opened.", window)
+	}, window)
+	formDialog.Resize(fyne.NewSize(500, 300))
+	formDialog.Show()
+}
+
+// showRestoreBackupDialog restores a backup file into the database of a
+// profile, which must be empty
+func showRestoreBackupDialog(window fyne.Window, profile string) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+
+		archive, err := backup.Read(reader)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		message := fmt.Sprintf("Restore %d rows from the backup of %s into %s?",
+			archive.Rows(), archive.CreatedAt.Format("2006-01-02 15:04"), profile)
+		dialog.ShowConfirm("Restore Backup", message, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			config, err := db.LoadProfile(profile)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			database, err := db.Connect(config)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			defer database.Close()
+
+			if err := backup.Restore(database, archive); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			dialog.ShowInformation("Restore Backup", "The backup has been restored into "+profile, window)
+		}, window)
+	}, window)
+}
@@ -0,0 +1,188 @@
+// cmd/bulkOrders.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/export"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxListedOrders is the number of orders named in a confirmation before the
+// rest are summarised
+const maxListedOrders = 10
+
+// describeOrders names the orders a bulk action applies to
+func describeOrders(orders []internal.Order, hours internal.BusinessHours) string {
+	var lines []string
+	for i, order := range orders {
+		if i == maxListedOrders {
+			lines = append(lines, fmt.Sprintf("and %d more", len(orders)-maxListedOrders))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s, due %s", order.ClientName, hours.FormatDue(order.DueDate)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func countOrders(n int) string {
+	if n == 1 {
+		return "1 order"
+	}
+	return fmt.Sprintf("%d orders", n)
+}
+
+// orderIDs returns the IDs of orders
+func orderIDs(orders []internal.Order) []int64 {
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	return ids
+}
+
+// confirmBulkAction asks once before running action on all orders. The
+// action runs in a single transaction, so either every order changes or none.
+func confirmBulkAction(window fyne.Window, hours internal.BusinessHours, title, question string, orders []internal.Order,
+	action func([]int64) error, onDone func()) {
+	message := fmt.Sprintf("%s %s?\n\n%s", question, countOrders(len(orders)), describeOrders(orders, hours))
+	dialog.ShowConfirm(title, message, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := action(orderIDs(orders)); err != nil {
+			dialog.ShowError(fmt.Errorf("no orders were changed: %w", err), window)
+			return
+		}
+		onDone()
+	}, window)
+}
+
+func showBulkCompleteDialog(window fyne.Window, db *sql.DB, orders []internal.Order, onDone func()) {
+	confirmBulkAction(window, loadBusinessHours(db), "Mark Complete", "Mark as completed", orders, func(ids []int64) error {
+		return internal.CompleteOrders(db, ids)
+	}, onDone)
+}
+
+func showBulkCancelDialog(window fyne.Window, db *sql.DB, orders []internal.Order, onDone func()) {
+	confirmBulkAction(window, loadBusinessHours(db), "Cancel Orders", "Cancel", orders, func(ids []int64) error {
+		return internal.CancelOrders(db, ids)
+	}, onDone)
+}
+
+// showBulkReassignDialog assigns the orders to another representative. The
+// form is the only confirmation.
+func showBulkReassignDialog(window fyne.Window, db *sql.DB, orders []internal.Order, onDone func()) {
+	representatives, err := internal.LoadRepresentatives(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	var names []string
+	for _, r := range representatives {
+		names = append(names, r.Name)
+	}
+	repSelect := widget.NewSelect(names, nil)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Orders", widget.NewLabel(describeOrders(orders, loadBusinessHours(db)))),
+		widget.NewFormItem("Representative", repSelect),
+	}
+	title := "Reassign " + countOrders(len(orders))
+	dialog.ShowForm(title, "Reassign", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		rep := repSelect.SelectedIndex()
+		if rep < 0 {
+			dialog.ShowError(fmt.Errorf("please select a representative"), window)
+			return
+		}
+		if err := internal.AssignRepresentative(db, orderIDs(orders), representatives[rep].ID); err != nil {
+			dialog.ShowError(fmt.Errorf("no orders were changed: %w", err), window)
+			return
+		}
+		onDone()
+	}, window)
+}
+
+// showBulkDueDateDialog moves the orders to another due date. The form is the
+// only confirmation.
+func showBulkDueDateDialog(window fyne.Window, db *sql.DB, orders []internal.Order, onDone func()) {
+	hours := loadBusinessHours(db)
+	duePicker := newDueDatePicker(hours, time.Time{})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Orders", widget.NewLabel(describeOrders(orders, hours))),
+		widget.NewFormItem("New Due Date", duePicker.content),
+	}
+	title := "Change Due Date of " + countOrders(len(orders))
+	dialog.ShowForm(title, "Change", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		due, err := duePicker.Value()
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		// The orders are checked together, as they all land on one day
+		check, err := internal.CheckCapacityOf(db, orders, due)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		confirmCapacity(window, check, func() {
+			if err := internal.SetDueDate(db, orderIDs(orders), due); err != nil {
+				dialog.ShowError(fmt.Errorf("no orders were changed: %w", err), window)
+				return
+			}
+			onDone()
+		})
+	}, window)
+}
+
+// showExportSelectedDialog exports only the given orders, whatever their
+// status or date
+func showExportSelectedDialog(window fyne.Window, db *sql.DB, orders []internal.Order) {
+	formatSelect := widget.NewSelect(export.Extensions(), nil)
+	formatSelect.SetSelected(export.Exporters[0].Extension())
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Orders", widget.NewLabel(describeOrders(orders, loadBusinessHours(db)))),
+		widget.NewFormItem("Format", formatSelect),
+	}
+	title := "Export " + countOrders(len(orders))
+	dialog.ShowForm(title, "Next", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		showSaveOrdersDialog(window, db, export.Filter{OrderIDs: orderIDs(orders)}, formatSelect.Selected)
+	}, window)
+}
+
+// pendingOrders returns the orders that are neither completed nor cancelled
+func pendingOrders(orders []internal.Order) []internal.Order {
+	var pending []internal.Order
+	for _, order := range orders {
+		if order.Status() == internal.StatusPending {
+			pending = append(pending, order)
+		}
+	}
+	return pending
+}
+
+func setEnabled(w fyne.Disableable, enabled bool) {
+	if enabled {
+		w.Enable()
+	} else {
+		w.Disable()
+	}
+}
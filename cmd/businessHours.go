@@ -0,0 +1,125 @@
+// cmd/businessHours.go
+package main
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// loadBusinessHours returns the business hours, or the defaults when they
+// cannot be loaded
+func loadBusinessHours(db *sql.DB) internal.BusinessHours {
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		log.Printf("Error loading business hours: %v", err)
+	}
+	return hours
+}
+
+// showBusinessHoursDialog edits the hours and closed days due dates are
+// checked against. They are shared by everyone using the database.
+func showBusinessHoursDialog(window fyne.Window, db *sql.DB) {
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	zoneEntry := widget.NewEntry()
+	zoneEntry.SetText(hours.TimeZone)
+	zoneEntry.SetPlaceHolder("e.g. Africa/Johannesburg, empty for this computer's")
+
+	slotEntry := widget.NewEntry()
+	slotEntry.SetText(strconv.Itoa(hours.SlotMinutes))
+
+	type dayEntries struct {
+		open      *widget.Check
+		openTime  *widget.Entry
+		closeTime *widget.Entry
+	}
+	var days [7]dayEntries
+	grid := container.NewGridWithColumns(4,
+		widget.NewLabelWithStyle("Day", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Open", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("From", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Until", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	// Weeks start on Monday
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		d := dayEntries{openTime: widget.NewEntry(), closeTime: widget.NewEntry()}
+		d.openTime.SetPlaceHolder("HH:MM")
+		d.closeTime.SetPlaceHolder("HH:MM")
+		d.openTime.SetText(hours.Days[day].Open)
+		d.closeTime.SetText(hours.Days[day].Close)
+		d.open = widget.NewCheck("", func(open bool) {
+			setEnabled(d.openTime, open)
+			setEnabled(d.closeTime, open)
+		})
+		d.open.SetChecked(!hours.Days[day].Closed())
+		d.open.OnChanged(d.open.Checked)
+		days[day] = d
+		grid.Add(widget.NewLabel(day.String()))
+		grid.Add(d.open)
+		grid.Add(d.openTime)
+		grid.Add(d.closeTime)
+	}
+
+	closedEntry := widget.NewMultiLineEntry()
+	closedEntry.SetText(strings.Join(hours.ClosedDates, "\n"))
+	closedEntry.SetPlaceHolder("One date per line, YYYY-MM-DD")
+	closedEntry.SetMinRowsVisible(4)
+
+	content := container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("Time Zone", zoneEntry),
+			widget.NewFormItem("Time Slots (minutes)", slotEntry),
+		),
+		grid,
+		widget.NewLabel("Closed Dates"),
+		closedEntry,
+	)
+
+	formDialog := dialog.NewCustomConfirm("Business Hours", "Save", "Cancel", content, func(save bool) {
+		if !save {
+			return
+		}
+		slotMinutes, err := strconv.Atoi(strings.TrimSpace(slotEntry.Text))
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		updated := internal.BusinessHours{
+			TimeZone:    strings.TrimSpace(zoneEntry.Text),
+			SlotMinutes: slotMinutes,
+		}
+		for day, d := range days {
+			if d.open.Checked {
+				updated.Days[day] = internal.DayHours{
+					Open:  strings.TrimSpace(d.openTime.Text),
+					Close: strings.TrimSpace(d.closeTime.Text),
+				}
+			}
+		}
+		for _, line := range strings.Split(closedEntry.Text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				updated.ClosedDates = append(updated.ClosedDates, line)
+			}
+		}
+		if err := internal.SaveBusinessHours(db, updated); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+	formDialog.Resize(fyne.NewSize(600, 600))
+	formDialog.Show()
+}
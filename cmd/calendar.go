@@ -0,0 +1,319 @@
+// cmd/calendar.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/ical"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	calendarMonth = "Month"
+	calendarWeek  = "Week"
+)
+
+// calendarOrder is an order on the calendar. Tapping it opens the order and
+// dragging it to another day reschedules it.
+type calendarOrder struct {
+	widget.Button
+	onDrag func(fyne.Position)
+	onDrop func(fyne.Position)
+	last   fyne.Position
+}
+
+// newCalendarOrder makes an order that cannot be dragged when onDrag and
+// onDrop are nil
+func newCalendarOrder(text string, onTap func(), onDrag, onDrop func(fyne.Position)) *calendarOrder {
+	c := &calendarOrder{onDrag: onDrag, onDrop: onDrop}
+	c.Text = text
+	c.OnTapped = onTap
+	c.Alignment = widget.ButtonAlignLeading
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *calendarOrder) Dragged(ev *fyne.DragEvent) {
+	c.last = ev.AbsolutePosition
+	if c.onDrag != nil {
+		c.onDrag(c.last)
+	}
+}
+
+func (c *calendarOrder) DragEnd() {
+	if c.onDrop != nil {
+		c.onDrop(c.last)
+	}
+}
+
+// calendarCell is a day on the calendar
+type calendarCell struct {
+	date       time.Time
+	object     fyne.CanvasObject
+	background *canvas.Rectangle
+}
+
+// orderCalendar shows the open orders on their due dates, a month or a week
+// at a time, with how many items are due each day
+type orderCalendar struct {
+	window fyne.Window
+	db     *sql.DB
+	user   internal.User
+	onOpen func(internal.Order)
+
+	mode  string
+	shown time.Time // a date in the month or week shown, at midnight UTC
+	cells []calendarCell
+
+	title   *widget.Label
+	grid    *fyne.Container
+	content fyne.CanvasObject
+}
+
+// newOrderCalendar builds the calendar and returns it with a function that
+// reloads it. onOpen is called with an order that was tapped.
+func newOrderCalendar(window fyne.Window, db *sql.DB, user internal.User, onOpen func(internal.Order)) (fyne.CanvasObject, func()) {
+	now := time.Now()
+	c := &orderCalendar{
+		window: window,
+		db:     db,
+		user:   user,
+		onOpen: onOpen,
+		mode:   calendarMonth,
+		shown:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		title:  widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		grid:   container.NewGridWithColumns(7),
+	}
+
+	modeSelect := widget.NewRadioGroup([]string{calendarMonth, calendarWeek}, func(mode string) {
+		if mode != "" && mode != c.mode {
+			c.mode = mode
+			c.Refresh()
+		}
+	})
+	modeSelect.Horizontal = true
+	modeSelect.Required = true
+	modeSelect.SetSelected(c.mode)
+
+	prev := widget.NewButton("<", func() { c.move(-1) })
+	next := widget.NewButton(">", func() { c.move(1) })
+	today := widget.NewButton("Today", func() {
+		now := time.Now()
+		c.shown = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		c.Refresh()
+	})
+	hint := widget.NewLabel("Drag an order to another day to reschedule it")
+	hint.Importance = widget.LowImportance
+	exportBtn := widget.NewButton("Export .ics...", func() {
+		showExportCalendarDialog(window, db)
+	})
+	if !user.Can(internal.PermExportOrders) {
+		exportBtn.Disable()
+	}
+
+	header := container.NewBorder(nil, nil,
+		container.NewHBox(prev, today, next),
+		container.NewHBox(hint, modeSelect, exportBtn),
+		c.title,
+	)
+	c.content = container.NewBorder(header, nil, nil, nil, c.grid)
+	return c.content, c.Refresh
+}
+
+// move shows the next or previous month or week
+func (c *orderCalendar) move(step int) {
+	if c.mode == calendarWeek {
+		c.shown = c.shown.AddDate(0, 0, 7*step)
+	} else {
+		c.shown = time.Date(c.shown.Year(), c.shown.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+	}
+	c.Refresh()
+}
+
+// span returns the first day shown, always a Monday, and the number of days
+func (c *orderCalendar) span() (time.Time, int) {
+	if c.mode == calendarWeek {
+		return startOfWeek(c.shown), 7
+	}
+	first := time.Date(c.shown.Year(), c.shown.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return startOfWeek(first), 42
+}
+
+func startOfWeek(day time.Time) time.Time {
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// Refresh reloads the orders, business hours and capacity and redraws the
+// calendar
+func (c *orderCalendar) Refresh() {
+	orders, err := internal.LoadOrders(c.db)
+	if err != nil {
+		log.Printf("Error loading orders: %v", err)
+		return
+	}
+	capacity, err := internal.LoadCapacity(c.db)
+	if err != nil {
+		log.Printf("Error loading capacity: %v", err)
+	}
+	hours := loadBusinessHours(c.db)
+
+	first, days := c.span()
+	if c.mode == calendarWeek {
+		c.title.SetText(fmt.Sprintf("%s to %s", first.Format("2 Jan"), first.AddDate(0, 0, 6).Format("2 Jan 2006")))
+	} else {
+		c.title.SetText(c.shown.Format("January 2006"))
+	}
+
+	objects := []fyne.CanvasObject{}
+	for _, name := range []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"} {
+		objects = append(objects, widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+	c.cells = nil
+	for _, day := range internal.OrderCalendar(orders, hours, first, days) {
+		cell := c.newCell(day, hours, capacity)
+		c.cells = append(c.cells, cell)
+		objects = append(objects, cell.object)
+	}
+	c.grid.Objects = objects
+	c.grid.Refresh()
+}
+
+func (c *orderCalendar) newCell(day internal.CalendarDay, hours internal.BusinessHours, capacity internal.Capacity) calendarCell {
+	dayLabel := widget.NewLabelWithStyle(strconv.Itoa(day.Date.Day()), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	if c.mode == calendarMonth && day.Date.Month() != c.shown.Month() {
+		dayLabel.Importance = widget.LowImportance
+	}
+
+	load := widget.NewLabel("")
+	load.Alignment = fyne.TextAlignTrailing
+	switch {
+	case hours.ClosedReason(day.Date) != "":
+		load.SetText("Closed")
+		load.Importance = widget.LowImportance
+	case capacity.ItemsPerDay > 0:
+		load.SetText(fmt.Sprintf("%d/%d items", day.Items(), capacity.ItemsPerDay))
+	case len(day.Orders) > 0:
+		load.SetText(fmt.Sprintf("%d items", day.Items()))
+	}
+	if len(day.Over(capacity)) > 0 {
+		load.Importance = widget.DangerImportance
+	}
+
+	list := container.NewVBox()
+	for _, order := range day.Orders {
+		list.Add(c.newOrder(order, hours))
+	}
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameBackground))
+	background.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	background.StrokeWidth = 1
+	object := container.NewStack(background, container.NewBorder(
+		container.NewBorder(nil, nil, dayLabel, nil, load), nil, nil, nil,
+		container.NewVScroll(list),
+	))
+	return calendarCell{date: day.Date, object: object, background: background}
+}
+
+func (c *orderCalendar) newOrder(order internal.Order, hours internal.BusinessHours) fyne.CanvasObject {
+	var items int
+	for _, item := range order.Items {
+		items += item.Quantity
+	}
+	text := fmt.Sprintf("%s (%d)", order.ClientName, items)
+	if internal.HasDueTime(order.DueDate) {
+		text = order.DueDate.In(hours.Location()).Format("15:04") + " " + text
+	}
+
+	open := func() { c.onOpen(order) }
+	if !c.user.CanEditOrder(order) {
+		return newCalendarOrder(text, open, nil, nil)
+	}
+	return newCalendarOrder(text, open, c.highlight, func(pos fyne.Position) {
+		c.highlight(fyne.Position{X: -1, Y: -1})
+		if cell, ok := c.cellAt(pos); ok && !cell.date.Equal(hours.DueDay(order.DueDate)) {
+			c.reschedule(order, cell.date, hours)
+		}
+	})
+}
+
+// cellAt returns the day at an absolute position
+func (c *orderCalendar) cellAt(pos fyne.Position) (calendarCell, bool) {
+	driver := fyne.CurrentApp().Driver()
+	for _, cell := range c.cells {
+		topLeft := driver.AbsolutePositionForObject(cell.object)
+		size := cell.object.Size()
+		if pos.X >= topLeft.X && pos.X < topLeft.X+size.Width &&
+			pos.Y >= topLeft.Y && pos.Y < topLeft.Y+size.Height {
+			return cell, true
+		}
+	}
+	return calendarCell{}, false
+}
+
+// highlight marks the day an order is dragged over
+func (c *orderCalendar) highlight(pos fyne.Position) {
+	target, ok := c.cellAt(pos)
+	for _, cell := range c.cells {
+		color := theme.Color(theme.ColorNameBackground)
+		if ok && cell.date.Equal(target.date) {
+			color = theme.Color(theme.ColorNameHover)
+		}
+		if cell.background.FillColor != color {
+			cell.background.FillColor = color
+			cell.background.Refresh()
+		}
+	}
+}
+
+// reschedule moves an order to another day, keeping its due time. The edit
+// is saved like any other, so a change made by someone else in the meantime
+// is never overwritten.
+func (c *orderCalendar) reschedule(order internal.Order, day time.Time, hours internal.BusinessHours) {
+	due := hours.MoveToDay(order.DueDate, day)
+	if err := hours.Check(due); err != nil {
+		dialog.ShowError(err, c.window)
+		return
+	}
+	order.DueDate = due
+	checkCapacity(c.window, c.db, order, func() {
+		saveEditedOrder(c.window, c.db, order, c.user)
+	})
+}
+
+// showExportCalendarDialog saves the open orders as an iCalendar file that
+// calendar apps can import
+func showExportCalendarDialog(window fyne.Window, db *sql.DB) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if writer == nil {
+			return // user cancelled
+		}
+		defer writer.Close()
+
+		if err := ical.WriteFeed(db, writer); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Success",
+			"Open orders have been exported to:\n"+writer.URI().Path(),
+			window)
+	}, window)
+	saveDialog.SetFileName("orders" + ical.Extension)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{ical.Extension}))
+	saveDialog.Show()
+}
@@ -0,0 +1,220 @@
+// cmd/capacity.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// checkCapacity runs save when order fits the capacity of its due day.
+// Otherwise it shows the limits the order exceeds and the next days with
+// room, and saves only when the user accepts the warning. Orders are never
+// saved when overbooking is blocked.
+func checkCapacity(window fyne.Window, db *sql.DB, order internal.Order, save func()) {
+	check, err := internal.CheckCapacity(db, order)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	confirmCapacity(window, check, save)
+}
+
+// confirmCapacity runs save when check passed, or asks first, see
+// checkCapacity
+func confirmCapacity(window fyne.Window, check internal.CapacityCheck, save func()) {
+	if check.OK() {
+		save()
+		return
+	}
+
+	lines := []string{"The due day is over capacity:", ""}
+	for _, o := range check.Overbooked {
+		lines = append(lines, o.String())
+	}
+	lines = append(lines, "")
+	if len(check.Suggestions) == 0 {
+		lines = append(lines, "No day in the next two months has room for it.")
+	} else {
+		var days []string
+		for _, day := range check.Suggestions {
+			days = append(days, day.Format("Mon 2 Jan 2006"))
+		}
+		lines = append(lines, "Days with room: "+strings.Join(days, ", "))
+	}
+
+	if check.Blocked {
+		dialog.ShowInformation("Over Capacity", strings.Join(lines, "\n"), window)
+		return
+	}
+	lines = append(lines, "", "Save it anyway?")
+	dialog.ShowConfirm("Over Capacity", strings.Join(lines, "\n"), func(ok bool) {
+		if ok {
+			save()
+		}
+	}, window)
+}
+
+// parseLimit reads a limit in items, empty meaning no limit
+func parseLimit(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(text)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("%q is not a number of items", text)
+	}
+	return limit, nil
+}
+
+func formatLimit(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return strconv.Itoa(limit)
+}
+
+// capacityCategoryRow edits a category of the capacity dialog
+type capacityCategoryRow struct {
+	name     *widget.Entry
+	limit    *widget.Entry
+	products *widget.CheckGroup
+}
+
+// showCapacityDialog edits how many items can be made per day, in total, per
+// product and per category of products
+func showCapacityDialog(window fyne.Window, db *sql.DB) {
+	capacity, err := internal.LoadCapacity(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	products, err := internal.LoadProducts(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	var productNames []string
+	productIDs := make(map[string]int64)
+	for _, p := range products {
+		productNames = append(productNames, p.Name)
+		productIDs[p.Name] = p.ID
+	}
+
+	itemsEntry := widget.NewEntry()
+	itemsEntry.SetPlaceHolder("No limit")
+	itemsEntry.SetText(formatLimit(capacity.ItemsPerDay))
+
+	blockCheck := widget.NewCheck("Refuse orders over capacity instead of warning", nil)
+	blockCheck.SetChecked(capacity.Block)
+
+	productEntries := make(map[int64]*widget.Entry)
+	productForm := widget.NewForm()
+	for _, p := range products {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder("No limit")
+		entry.SetText(formatLimit(capacity.Products[p.ID]))
+		productEntries[p.ID] = entry
+		productForm.Append(p.Name, entry)
+	}
+
+	var rows []*capacityCategoryRow
+	categoriesBox := container.NewVBox()
+	addCategory := func(category internal.CapacityCategory) {
+		row := &capacityCategoryRow{
+			name:     widget.NewEntry(),
+			limit:    widget.NewEntry(),
+			products: widget.NewCheckGroup(productNames, nil),
+		}
+		row.name.SetPlaceHolder("Category, e.g. Cakes")
+		row.name.SetText(category.Name)
+		row.limit.SetPlaceHolder("Items per day")
+		row.limit.SetText(formatLimit(category.ItemsPerDay))
+		row.products.Horizontal = true
+		for _, id := range category.ProductIDs {
+			for _, p := range products {
+				if p.ID == id {
+					row.products.Selected = append(row.products.Selected, p.Name)
+				}
+			}
+		}
+		rows = append(rows, row)
+
+		var card *widget.Card
+		removeBtn := widget.NewButton("Remove", func() {
+			for i, r := range rows {
+				if r == row {
+					rows = append(rows[:i], rows[i+1:]...)
+					break
+				}
+			}
+			categoriesBox.Remove(card)
+		})
+		card = widget.NewCard("", "", container.NewVBox(
+			container.NewBorder(nil, nil, nil, removeBtn, container.NewGridWithColumns(2, row.name, row.limit)),
+			row.products,
+		))
+		categoriesBox.Add(card)
+	}
+	for _, category := range capacity.Categories {
+		addCategory(category)
+	}
+	addCategoryBtn := widget.NewButton("Add Category", func() {
+		addCategory(internal.CapacityCategory{})
+	})
+
+	content := container.NewVScroll(container.NewVBox(
+		widget.NewForm(widget.NewFormItem("All products, items per day", itemsEntry)),
+		blockCheck,
+		widget.NewCard("Products", "Items per day", productForm),
+		widget.NewCard("Categories", "Products limited together, items per day",
+			container.NewVBox(categoriesBox, addCategoryBtn)),
+	))
+
+	formDialog := dialog.NewCustomConfirm("Daily Capacity", "Save", "Cancel", content, func(save bool) {
+		if !save {
+			return
+		}
+		updated := internal.Capacity{Block: blockCheck.Checked, Products: make(map[int64]int)}
+		var err error
+		if updated.ItemsPerDay, err = parseLimit(itemsEntry.Text); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		for id, entry := range productEntries {
+			limit, err := parseLimit(entry.Text)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if limit > 0 {
+				updated.Products[id] = limit
+			}
+		}
+		for _, row := range rows {
+			category := internal.CapacityCategory{Name: strings.TrimSpace(row.name.Text)}
+			if category.ItemsPerDay, err = parseLimit(row.limit.Text); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			for _, name := range row.products.Selected {
+				category.ProductIDs = append(category.ProductIDs, productIDs[name])
+			}
+			updated.Categories = append(updated.Categories, category)
+		}
+		if err := internal.SaveCapacity(db, updated); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+	formDialog.Resize(fyne.NewSize(600, 600))
+	formDialog.Show()
+}
@@ -0,0 +1,241 @@
+// cmd/charts.go
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// chartValue is a single labelled data point
+type chartValue struct {
+	Label string
+	Value float64
+}
+
+// barChart draws horizontal bars, one per value, using canvas primitives
+type barChart struct {
+	widget.BaseWidget
+	values []chartValue
+	format func(float64) string
+}
+
+func newBarChart(format func(float64) string) *barChart {
+	c := &barChart{format: format}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetValues replaces the chart data and redraws it
+func (c *barChart) SetValues(values []chartValue) {
+	c.values = values
+	c.Refresh()
+}
+
+func (c *barChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &barChartRenderer{chart: c}
+	r.rebuild()
+	return r
+}
+
+type barChartRenderer struct {
+	chart   *barChart
+	labels  []*canvas.Text
+	bars    []*canvas.Rectangle
+	amounts []*canvas.Text
+	objects []fyne.CanvasObject
+}
+
+func (r *barChartRenderer) rebuild() {
+	r.labels, r.bars, r.amounts, r.objects = nil, nil, nil, nil
+	for _, v := range r.chart.values {
+		label := canvas.NewText(v.Label, theme.ForegroundColor())
+		bar := canvas.NewRectangle(theme.PrimaryColor())
+		amount := canvas.NewText(r.chart.format(v.Value), theme.ForegroundColor())
+		r.labels = append(r.labels, label)
+		r.bars = append(r.bars, bar)
+		r.amounts = append(r.amounts, amount)
+		r.objects = append(r.objects, label, bar, amount)
+	}
+	if len(r.chart.values) == 0 {
+		r.objects = append(r.objects, canvas.NewText("No data", theme.DisabledColor()))
+	}
+}
+
+func (r *barChartRenderer) Layout(size fyne.Size) {
+	if len(r.chart.values) == 0 {
+		r.objects[0].Move(fyne.NewPos(theme.Padding(), theme.Padding()))
+		return
+	}
+
+	maxValue := maxChartValue(r.chart.values)
+	labelWidth := size.Width * 0.3
+	amountWidth := size.Width * 0.2
+	barSpace := size.Width - labelWidth - amountWidth - 2*theme.Padding()
+	rowHeight := size.Height / float32(len(r.chart.values))
+	barHeight := rowHeight * 0.6
+
+	for i, v := range r.chart.values {
+		y := float32(i) * rowHeight
+		textY := y + (rowHeight-r.labels[i].MinSize().Height)/2
+
+		r.labels[i].Move(fyne.NewPos(0, textY))
+
+		width := float32(0)
+		if maxValue > 0 {
+			width = barSpace * float32(v.Value/maxValue)
+		}
+		r.bars[i].Move(fyne.NewPos(labelWidth+theme.Padding(), y+(rowHeight-barHeight)/2))
+		r.bars[i].Resize(fyne.NewSize(width, barHeight))
+
+		r.amounts[i].Move(fyne.NewPos(labelWidth+width+2*theme.Padding(), textY))
+	}
+}
+
+func (r *barChartRenderer) MinSize() fyne.Size {
+	rows := len(r.chart.values)
+	if rows == 0 {
+		rows = 1
+	}
+	return fyne.NewSize(300, float32(rows)*(theme.TextSize()+2*theme.Padding()))
+}
+
+func (r *barChartRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+func (r *barChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *barChartRenderer) Destroy() {}
+
+// lineChart draws a series of values as connected line segments
+type lineChart struct {
+	widget.BaseWidget
+	values []chartValue
+	format func(float64) string
+}
+
+func newLineChart(format func(float64) string) *lineChart {
+	c := &lineChart{format: format}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetValues replaces the chart data and redraws it
+func (c *lineChart) SetValues(values []chartValue) {
+	c.values = values
+	c.Refresh()
+}
+
+func (c *lineChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &lineChartRenderer{chart: c}
+	r.rebuild()
+	return r
+}
+
+type lineChartRenderer struct {
+	chart      *lineChart
+	axis       *canvas.Line
+	segments   []*canvas.Line
+	maxLabel   *canvas.Text
+	firstLabel *canvas.Text
+	lastLabel  *canvas.Text
+	objects    []fyne.CanvasObject
+}
+
+func (r *lineChartRenderer) rebuild() {
+	r.axis = canvas.NewLine(theme.DisabledColor())
+	r.maxLabel = canvas.NewText("", theme.ForegroundColor())
+	r.firstLabel = canvas.NewText("", theme.ForegroundColor())
+	r.lastLabel = canvas.NewText("", theme.ForegroundColor())
+	r.objects = []fyne.CanvasObject{r.axis, r.maxLabel, r.firstLabel, r.lastLabel}
+
+	r.segments = nil
+	for i := 1; i < len(r.chart.values); i++ {
+		segment := canvas.NewLine(theme.PrimaryColor())
+		segment.StrokeWidth = 2
+		r.segments = append(r.segments, segment)
+		r.objects = append(r.objects, segment)
+	}
+
+	values := r.chart.values
+	if len(values) > 0 {
+		r.maxLabel.Text = r.chart.format(maxChartValue(values))
+		r.firstLabel.Text = values[0].Label
+		r.lastLabel.Text = values[len(values)-1].Label
+	}
+	for _, text := range []*canvas.Text{r.maxLabel, r.firstLabel, r.lastLabel} {
+		text.TextSize = theme.CaptionTextSize()
+	}
+}
+
+func (r *lineChartRenderer) Layout(size fyne.Size) {
+	labelHeight := r.firstLabel.MinSize().Height
+	top := r.maxLabel.MinSize().Height
+	bottom := size.Height - labelHeight
+	plotHeight := bottom - top
+
+	r.maxLabel.Move(fyne.NewPos(0, 0))
+	r.axis.Position1 = fyne.NewPos(0, bottom)
+	r.axis.Position2 = fyne.NewPos(size.Width, bottom)
+	r.firstLabel.Move(fyne.NewPos(0, bottom))
+	r.lastLabel.Move(fyne.NewPos(size.Width-r.lastLabel.MinSize().Width, bottom))
+
+	values := r.chart.values
+	if len(values) < 2 {
+		return
+	}
+
+	maxValue := maxChartValue(values)
+	step := size.Width / float32(len(values)-1)
+	point := func(i int) fyne.Position {
+		y := bottom
+		if maxValue > 0 {
+			y = bottom - plotHeight*float32(values[i].Value/maxValue)
+		}
+		return fyne.NewPos(float32(i)*step, y)
+	}
+
+	for i, segment := range r.segments {
+		segment.Position1 = point(i)
+		segment.Position2 = point(i + 1)
+	}
+}
+
+func (r *lineChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(300, 150)
+}
+
+func (r *lineChartRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+func (r *lineChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *lineChartRenderer) Destroy() {}
+
+func maxChartValue(values []chartValue) float64 {
+	var maxValue float64
+	for _, v := range values {
+		if v.Value > maxValue {
+			maxValue = v.Value
+		}
+	}
+	return maxValue
+}
+
+// newKPICard shows a single headline figure with a caption
+func newKPICard(caption string, value *canvas.Text) fyne.CanvasObject {
+	value.TextSize = theme.TextHeadingSize()
+	value.TextStyle = fyne.TextStyle{Bold: true}
+	return widget.NewCard("", caption, value)
+}
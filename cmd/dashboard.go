@@ -0,0 +1,118 @@
+// cmd/dashboard.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/reports"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func formatRand(value float64) string {
+	return fmt.Sprintf("R%.2f", value)
+}
+
+func formatCount(value float64) string {
+	return fmt.Sprintf("%.0f", value)
+}
+
+// newDashboard builds the home screen and returns it with a function that reloads its figures
+func newDashboard(db *sql.DB) (fyne.CanvasObject, func()) {
+	dueTodayValue := canvas.NewText("0", theme.ForegroundColor())
+	dueWeekValue := canvas.NewText("0", theme.ForegroundColor())
+	overdueValue := canvas.NewText("0", theme.ErrorColor())
+	revenueValue := canvas.NewText("R0.00", theme.ForegroundColor())
+	revenueCompare := widget.NewLabel("")
+
+	kpis := container.NewGridWithColumns(4,
+		newKPICard("Due Today", dueTodayValue),
+		newKPICard("Due This Week", dueWeekValue),
+		newKPICard("Overdue", overdueValue),
+		container.NewVBox(newKPICard("Revenue This Month", revenueValue), revenueCompare),
+	)
+
+	dueTodayList := widget.NewLabel("")
+	dueTodayList.Wrapping = fyne.TextWrapWord
+
+	revenueChart := newLineChart(formatRand)
+	productsChart := newBarChart(formatCount)
+	repsChart := newBarChart(formatRand)
+
+	charts := container.NewGridWithColumns(2,
+		widget.NewCard("Top Products This Month", "Quantity sold", productsChart),
+		widget.NewCard("Top Representatives This Month", "Revenue", repsChart),
+		widget.NewCard("Revenue", "Last 30 days", revenueChart),
+		widget.NewCard("Due Today", "", container.NewVScroll(dueTodayList)),
+	)
+
+	refresh := func() {
+		d, err := reports.BuildDashboard(db, time.Now().In(loadBusinessHours(db).Location()))
+		if err != nil {
+			log.Printf("Error loading dashboard: %v", err)
+			return
+		}
+
+		dueTodayValue.Text = fmt.Sprintf("%d", len(d.DueToday))
+		dueWeekValue.Text = fmt.Sprintf("%d", len(d.DueThisWeek))
+		overdueValue.Text = fmt.Sprintf("%d", len(d.Overdue))
+		revenueValue.Text = formatRand(d.RevenueThisMonth)
+		for _, text := range []*canvas.Text{dueTodayValue, dueWeekValue, overdueValue, revenueValue} {
+			text.Refresh()
+		}
+
+		comparison := reports.Comparison{
+			Current:  reports.Line{Revenue: d.RevenueThisMonth},
+			Previous: reports.Line{Revenue: d.RevenueLastMonth},
+		}
+		if pct, ok := comparison.ChangePercent(); ok {
+			revenueCompare.SetText(fmt.Sprintf("Last month %s (%+.1f%%)", formatRand(d.RevenueLastMonth), pct))
+		} else {
+			revenueCompare.SetText(fmt.Sprintf("Last month %s", formatRand(d.RevenueLastMonth)))
+		}
+
+		dueTodayList.SetText(describeDueOrders(d.DueToday))
+
+		var products, reps, revenue []chartValue
+		for _, l := range d.TopProducts {
+			products = append(products, chartValue{Label: l.Label, Value: float64(l.Quantity)})
+		}
+		for _, l := range d.TopRepresentatives {
+			reps = append(reps, chartValue{Label: l.Label, Value: l.Revenue})
+		}
+		for _, l := range d.DailyRevenue {
+			revenue = append(revenue, chartValue{Label: l.Label, Value: l.Revenue})
+		}
+		productsChart.SetValues(products)
+		repsChart.SetValues(reps)
+		revenueChart.SetValues(revenue)
+	}
+
+	content := container.NewBorder(kpis, nil, nil, nil, charts)
+	return container.NewPadded(content), refresh
+}
+
+func describeDueOrders(orders []internal.Order) string {
+	if len(orders) == 0 {
+		return "No orders due today"
+	}
+
+	var lines []string
+	for _, o := range orders {
+		var items []string
+		for _, item := range o.Items {
+			items = append(items, fmt.Sprintf("%d x %s", item.Quantity, item.ProductName))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", o.ClientName, strings.Join(items, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,168 @@
+// cmd/datePicker.go
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// anyTime is the slot of due dates without a time of day
+const anyTime = "Any time"
+
+// dueDatePicker chooses a due date on a calendar and a due time from the
+// slots the business is open that day. Closed days cannot be chosen.
+type dueDatePicker struct {
+	hours   internal.BusinessHours
+	initial time.Time
+
+	month time.Time // first day of the month shown
+	day   time.Time // date chosen, at midnight UTC; zero when none
+	slots []time.Time
+
+	monthLabel *widget.Label
+	days       *fyne.Container
+	slotSelect *widget.Select
+	info       *widget.Label
+	content    fyne.CanvasObject
+}
+
+// newDueDatePicker shows due, if not zero, as chosen
+func newDueDatePicker(hours internal.BusinessHours, due time.Time) *dueDatePicker {
+	p := &dueDatePicker{
+		hours:      hours,
+		initial:    due,
+		monthLabel: widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		days:       container.NewGridWithColumns(7),
+		info:       widget.NewLabel(""),
+	}
+	p.slotSelect = widget.NewSelect(nil, func(string) { p.updateInfo() })
+	p.slotSelect.PlaceHolder = "Choose a day first"
+
+	prev := widget.NewButton("<", func() { p.showMonth(p.month.AddDate(0, -1, 0)) })
+	next := widget.NewButton(">", func() { p.showMonth(p.month.AddDate(0, 1, 0)) })
+	p.content = container.NewVBox(
+		container.NewBorder(nil, nil, prev, next, p.monthLabel),
+		p.days,
+		container.NewBorder(nil, nil, widget.NewLabel("Time"), nil, p.slotSelect),
+		p.info,
+	)
+
+	if due.IsZero() {
+		now := time.Now().In(hours.Location())
+		p.showMonth(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+		p.updateInfo()
+		return p
+	}
+	p.setDue(due)
+	return p
+}
+
+// setDue chooses the day and slot of due
+func (p *dueDatePicker) setDue(due time.Time) {
+	slot := anyTime
+	if internal.HasDueTime(due) {
+		due = due.In(p.hours.Location())
+		slot = due.Format("15:04")
+	}
+	p.chooseDay(time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC))
+
+	// Keep a time that is no longer a slot, so that it is not lost
+	// unnoticed; Value rejects it unless it is unchanged
+	found := false
+	for _, option := range p.slotSelect.Options {
+		found = found || option == slot
+	}
+	if !found {
+		p.slots = append(p.slots, due)
+		p.slotSelect.Options = append(p.slotSelect.Options, slot)
+	}
+	p.slotSelect.SetSelected(slot)
+}
+
+// showMonth shows the calendar of the month starting on first
+func (p *dueDatePicker) showMonth(first time.Time) {
+	p.month = first
+	p.monthLabel.SetText(first.Format("January 2006"))
+
+	var cells []fyne.CanvasObject
+	for _, name := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
+		cells = append(cells, widget.NewLabelWithStyle(name, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+	// Weeks start on Monday
+	for i := 0; i < (int(first.Weekday())+6)%7; i++ {
+		cells = append(cells, widget.NewLabel(""))
+	}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		day := day
+		button := widget.NewButton(strconv.Itoa(day.Day()), func() { p.chooseDay(day) })
+		if day.Equal(p.day) {
+			button.Importance = widget.HighImportance
+		}
+		if p.hours.ClosedReason(day) != "" {
+			button.Disable()
+		}
+		cells = append(cells, button)
+	}
+	p.days.Objects = cells
+	p.days.Refresh()
+}
+
+// chooseDay chooses day and offers its slots
+func (p *dueDatePicker) chooseDay(day time.Time) {
+	p.day = day
+	p.slots = p.hours.Slots(day)
+	options := []string{anyTime}
+	for _, slot := range p.slots {
+		options = append(options, slot.Format("15:04"))
+	}
+	p.slotSelect.Options = options
+	p.slotSelect.PlaceHolder = "Choose a time"
+	p.slotSelect.ClearSelected()
+	p.slotSelect.SetSelected(anyTime)
+	p.showMonth(time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC))
+}
+
+func (p *dueDatePicker) updateInfo() {
+	due, err := p.due()
+	if err != nil {
+		p.info.SetText(err.Error())
+		return
+	}
+	p.info.SetText("Due " + due.Format("Monday") + " " + p.hours.FormatDue(due))
+}
+
+// due returns the due date chosen, without checking it
+func (p *dueDatePicker) due() (time.Time, error) {
+	if p.day.IsZero() {
+		return time.Time{}, fmt.Errorf("please choose a due date")
+	}
+	for _, slot := range p.slots {
+		if slot.Format("15:04") == p.slotSelect.Selected {
+			return slot, nil
+		}
+	}
+	return p.day, nil
+}
+
+// Value returns the due date chosen. It must be within business hours, unless
+// it is the due date the picker was opened with.
+func (p *dueDatePicker) Value() (time.Time, error) {
+	due, err := p.due()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !p.initial.IsZero() && due.Equal(p.initial) {
+		return p.initial, nil
+	}
+	if err := p.hours.Check(due); err != nil {
+		return time.Time{}, err
+	}
+	return due, nil
+}
@@ -0,0 +1,88 @@
+// cmd/downloadOrders.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/export"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const allStatuses = "All"
+
+// showDownloadOrdersDialog asks for the filters and then for the file to save.
+// The format follows the extension of the chosen file name.
+func showDownloadOrdersDialog(window fyne.Window, db *sql.DB) {
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("YYYY-MM-DD (optional)")
+
+	statusSelect := widget.NewSelect(append([]string{allStatuses}, internal.Statuses...), nil)
+	statusSelect.SetSelected(allStatuses)
+
+	formatSelect := widget.NewSelect(export.Extensions(), nil)
+	formatSelect.SetSelected(export.Exporters[0].Extension())
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Order Date From", fromEntry),
+		widget.NewFormItem("Order Date To", toEntry),
+		widget.NewFormItem("Status", statusSelect),
+		widget.NewFormItem("Format", formatSelect),
+	}
+
+	dialog.ShowForm("Download Orders", "Next", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+
+		filter, err := export.ParseFilter(fromEntry.Text, toEntry.Text, statusSelect.Selected,
+			loadBusinessHours(db).Location())
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		showSaveOrdersDialog(window, db, filter, formatSelect.Selected)
+	}, window)
+}
+
+func showSaveOrdersDialog(window fyne.Window, db *sql.DB, filter export.Filter, extension string) {
+	saveDialog := dialog.NewFileSave(
+		func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if writer == nil {
+				return // user cancelled
+			}
+			defer writer.Close()
+
+			// Keep a known extension, otherwise use the selected format
+			path := writer.URI().Path()
+			if _, err := export.ForPath(path); err != nil {
+				path += extension
+			}
+
+			if err := export.ExportFile(db, path, filter); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+
+			dialog.ShowInformation("Success",
+				"Orders have been exported successfully to:\n"+path,
+				window)
+		},
+		window)
+
+	saveDialog.SetFileName(fmt.Sprintf("orders_%s%s", time.Now().Format("2006-01-02"), extension))
+	saveDialog.SetFilter(storage.NewExtensionFileFilter(export.Extensions()))
+	saveDialog.Show()
+}
@@ -0,0 +1,217 @@
+// cmd/importWizard.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/importer"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const notImported = "(not imported)"
+
+// showImportWizard walks through choosing a file, mapping its columns and
+// previewing validation errors before committing the import
+func showImportWizard(window fyne.Window, db *sql.DB) {
+	var (
+		table   importer.Table
+		path    string
+		kind    = importer.Products
+		selects map[string]*widget.Select
+		plan    *importer.Plan
+	)
+
+	body := container.NewStack()
+	var wizard dialog.Dialog
+
+	var showFileStep, showMappingStep, showPreviewStep func()
+
+	showFileStep = func() {
+		fileLabel := widget.NewLabel("No file selected")
+		if path != "" {
+			fileLabel.SetText(path)
+		}
+
+		kindSelect := widget.NewSelect(importKindNames(), func(selected string) {
+			kind = importer.Kind(selected)
+		})
+		kindSelect.SetSelected(string(kind))
+
+		nextBtn := widget.NewButton("Next", func() {
+			showMappingStep()
+		})
+		if path == "" {
+			nextBtn.Disable()
+		}
+
+		chooseBtn := widget.NewButton("Choose File...", func() {
+			openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				if reader == nil {
+					return // user cancelled
+				}
+				reader.Close()
+
+				t, err := importer.ReadFile(reader.URI().Path())
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				table = t
+				path = reader.URI().Path()
+				fileLabel.SetText(fmt.Sprintf("%s (%d rows)", path, len(table.Rows)))
+				nextBtn.Enable()
+			}, window)
+			openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".xlsx", ".csv"}))
+			openDialog.Show()
+		})
+
+		body.Objects = []fyne.CanvasObject{container.NewVBox(
+			widget.NewLabelWithStyle("Step 1 of 3: Choose a file", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			widget.NewLabel("Excel (.xlsx) and CSV files are supported. The first row must contain column headers."),
+			container.NewHBox(chooseBtn, fileLabel),
+			widget.NewLabel("Import as:"),
+			kindSelect,
+			container.NewHBox(nextBtn),
+		)}
+		body.Refresh()
+	}
+
+	showMappingStep = func() {
+		mapping := importer.GuessMapping(kind, table.Headers)
+		options := append([]string{notImported}, table.Headers...)
+
+		form := widget.NewForm()
+		selects = make(map[string]*widget.Select)
+		for _, field := range importer.Fields(kind) {
+			sel := widget.NewSelect(options, nil)
+			sel.SetSelected(notImported)
+			if col, ok := mapping[field.Name]; ok {
+				sel.SetSelectedIndex(col + 1)
+			}
+			selects[field.Name] = sel
+
+			label := field.Name
+			if field.Required {
+				label += " *"
+			}
+			form.Append(label, sel)
+		}
+
+		body.Objects = []fyne.CanvasObject{container.NewBorder(
+			container.NewVBox(
+				widget.NewLabelWithStyle("Step 2 of 3: Map columns", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabel("Choose which column holds each field. Fields marked * are required."),
+			),
+			container.NewHBox(
+				widget.NewButton("Back", showFileStep),
+				widget.NewButton("Preview", showPreviewStep),
+			),
+			nil, nil,
+			container.NewVScroll(form),
+		)}
+		body.Refresh()
+	}
+
+	showPreviewStep = func() {
+		mapping := make(importer.Mapping)
+		for field, sel := range selects {
+			if sel.SelectedIndex() > 0 {
+				mapping[field] = sel.SelectedIndex() - 1
+			}
+		}
+
+		var err error
+		plan, err = importer.Validate(db, kind, table, mapping)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		summary := fmt.Sprintf("%d %s ready to import.", plan.Count(), kind)
+		if !plan.Valid() {
+			summary = fmt.Sprintf("%d errors found. Fix the file or the column mapping and try again.", len(plan.Errors))
+		}
+
+		errorList := widget.NewList(
+			func() int { return len(plan.Errors) },
+			func() fyne.CanvasObject { return widget.NewLabel("") },
+			func(id widget.ListItemID, item fyne.CanvasObject) {
+				item.(*widget.Label).SetText(plan.Errors[id].Error())
+			},
+		)
+
+		preview := newReportTable(previewRows(table, 20))
+
+		importBtn := widget.NewButton("Import", func() {
+			if err := importer.Commit(db, plan); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			wizard.Hide()
+			dialog.ShowInformation("Success",
+				fmt.Sprintf("Imported %d %s", plan.Count(), kind), window)
+		})
+		importBtn.Importance = widget.HighImportance
+		if !plan.Valid() {
+			importBtn.Disable()
+		}
+
+		var details fyne.CanvasObject = preview
+		if !plan.Valid() {
+			details = container.NewVSplit(errorList, preview)
+		}
+
+		body.Objects = []fyne.CanvasObject{container.NewBorder(
+			container.NewVBox(
+				widget.NewLabelWithStyle("Step 3 of 3: Preview", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabel(summary),
+			),
+			container.NewHBox(
+				widget.NewButton("Back", showMappingStep),
+				importBtn,
+			),
+			nil, nil,
+			details,
+		)}
+		body.Refresh()
+	}
+
+	showFileStep()
+
+	wizard = dialog.NewCustom("Import Data", "Close", body, window)
+	wizard.Resize(fyne.NewSize(800, 600))
+	wizard.Show()
+}
+
+func importKindNames() []string {
+	var names []string
+	for _, k := range importer.Kinds {
+		names = append(names, string(k))
+	}
+	return names
+}
+
+// previewRows returns the header and up to limit rows of the table as text
+func previewRows(table importer.Table, limit int) [][]string {
+	width := len(table.Headers)
+	rows := [][]string{table.Headers}
+	for i, row := range table.Rows {
+		if i >= limit {
+			break
+		}
+		padded := make([]string, width)
+		copy(padded, row)
+		rows = append(rows, padded)
+	}
+	return rows
+}
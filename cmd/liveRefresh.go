@@ -0,0 +1,56 @@
+// cmd/liveRefresh.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefRefreshInterval is the number of seconds between checks for changes
+// made on other machines, 0 to turn the checks off
+const prefRefreshInterval = "refresh.interval_seconds"
+
+func refreshInterval() time.Duration {
+	seconds := fyne.CurrentApp().Preferences().IntWithFallback(prefRefreshInterval,
+		int(internal.DefaultChangeInterval/time.Second))
+	return time.Duration(seconds) * time.Second
+}
+
+// startChangeWatcher publishes internal.DataChanged whenever another machine
+// changes the database, until ctx is cancelled
+func startChangeWatcher(ctx context.Context, database *sql.DB) {
+	go internal.NewChangeWatcher(database, refreshInterval()).Run(ctx)
+}
+
+func showLiveRefreshDialog(window fyne.Window) {
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText(strconv.Itoa(int(refreshInterval() / time.Second)))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Check every (seconds)", intervalEntry),
+		widget.NewFormItem("", widget.NewLabel("Use 0 to only refresh after changes made here")),
+	}
+
+	dialog.ShowForm("Live Refresh", "Save", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+
+		seconds, err := strconv.Atoi(intervalEntry.Text)
+		if err != nil || seconds < 0 {
+			dialog.ShowError(fmt.Errorf("Invalid interval"), window)
+			return
+		}
+		fyne.CurrentApp().Preferences().SetInt(prefRefreshInterval, seconds)
+		dialog.ShowInformation("Live Refresh", "Saved. The change takes effect the next time a database is opened.", window)
+	}, window)
+}
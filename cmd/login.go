@@ -0,0 +1,119 @@
+// cmd/login.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showLogin asks for a username and password before the main app is shown.
+// Without any accounts, the first admin account is created instead.
+func showLogin(window fyne.Window, db *sql.DB, onLogin func(internal.User)) {
+	hasUsers, err := internal.HasUsers(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	usernameEntry := widget.NewEntry()
+	passwordEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	errorLabel := widget.NewLabel("")
+	errorLabel.Importance = widget.DangerImportance
+
+	title := "Log in to OrderFlow Manager"
+	items := []*widget.FormItem{
+		widget.NewFormItem("Username", usernameEntry),
+		widget.NewFormItem("Password", passwordEntry),
+	}
+	if !hasUsers {
+		title = "Create the administrator account"
+		items = append(items, widget.NewFormItem("Confirm Password", confirmEntry))
+	}
+
+	login := func() {
+		if !hasUsers {
+			if passwordEntry.Text != confirmEntry.Text {
+				errorLabel.SetText("Passwords do not match")
+				return
+			}
+			_, err := internal.CreateUser(db, internal.User{
+				Username: usernameEntry.Text,
+				Role:     internal.RoleAdmin,
+			}, passwordEntry.Text)
+			if err != nil {
+				errorLabel.SetText(err.Error())
+				return
+			}
+		}
+
+		user, err := internal.Authenticate(db, usernameEntry.Text, passwordEntry.Text)
+		if err != nil {
+			errorLabel.SetText(err.Error())
+			passwordEntry.SetText("")
+			return
+		}
+
+		// Changes are audited as the logged in user from now on
+		internal.SetAuditUser(user.Username)
+		onLogin(user)
+	}
+
+	form := widget.NewForm(items...)
+	form.SubmitText = "Log In"
+	if !hasUsers {
+		form.SubmitText = "Create Account"
+	}
+	form.OnSubmit = login
+	passwordEntry.OnSubmitted = func(string) { login() }
+	confirmEntry.OnSubmitted = func(string) { login() }
+
+	box := container.NewVBox(
+		widget.NewLabelWithStyle(title, fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		form,
+		errorLabel,
+	)
+	window.SetContent(container.NewCenter(container.NewGridWrap(fyne.NewSize(400, 260), box)))
+	window.Resize(fyne.NewSize(1024, 768))
+	window.Canvas().Focus(usernameEntry)
+}
+
+// showChangePasswordDialog lets a user change their own password
+func showChangePasswordDialog(window fyne.Window, db *sql.DB, user internal.User) {
+	currentEntry := widget.NewPasswordEntry()
+	newEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Current Password", currentEntry),
+		widget.NewFormItem("New Password", newEntry),
+		widget.NewFormItem("Confirm Password", confirmEntry),
+	}
+
+	dialog.ShowForm("Change Password", "Save", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		if _, err := internal.Authenticate(db, user.Username, currentEntry.Text); err != nil {
+			dialog.ShowError(fmt.Errorf("Current password is incorrect"), window)
+			return
+		}
+		if newEntry.Text != confirmEntry.Text {
+			dialog.ShowError(fmt.Errorf("Passwords do not match"), window)
+			return
+		}
+		if err := internal.SetPassword(db, user.ID, newEntry.Text); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Success", "Password changed", window)
+	}, window)
+}
@@ -2,7 +2,9 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
 	"strconv"
@@ -10,55 +12,47 @@ import (
 	"time"
 
 	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/export"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/webhooks"
 	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
-	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-
-	"github.com/xuri/excelize/v2"
 )
 
 func main() {
 	myApp := app.NewWithID("com.orderflow.manager")
 	myWindow := myApp.NewWindow("OrderFlow Manager")
 
-	// Update environment variables from config file
-	if err := db.UpdateEnvForDbConfig(); err != nil {
-		log.Printf("Error updating database config: %v", err)
-	}
+	configPath := flag.String("config", "", "config file with the database profiles")
+	profile := flag.String("profile", "", "database profile to open")
+	flag.Parse()
+	overrides := db.Overrides{ConfigPath: *configPath, Profile: *profile}
 
-	// Validate database configuration
-	var database *sql.DB
-	var dbErr error
+	s := newSession(myWindow)
 
-	if err := db.ValidateDbConfig(); err != nil {
-		log.Printf("Database configuration validation failed: %v", err)
+	// Ask for the connection details on first launch. The environment can
+	// provide them instead, as it does for the command line.
+	config, err := db.LoadConfig(overrides)
+	if err == nil {
+		err = config.Validate()
+	}
+	if err != nil {
+		log.Printf("Error loading database config: %v", err)
 
-		// Show database configuration dialog
-		showDatabaseConfigDialog(myWindow, func() {
-			// Attempt to initialize DB after configuration
-			database, dbErr = db.InitDB()
-			if dbErr != nil {
-				dialog.ShowError(dbErr, myWindow)
-				return
-			}
-			// Continue with app initialization
-			initializeMainApp(myWindow, database)
+		showDatabaseConfigDialog(myWindow, config, func() {
+			s.open("")
 		})
+	} else if config.Sources.Profile.Kind >= db.SourceEnv {
+		s.open(overrides.Profile)
+	} else if names, _, err := db.ListProfiles(); err == nil && len(names) > 1 {
+		showProfilePicker(s)
 	} else {
-		// Configuration is valid, proceed normally
-		database, dbErr = db.InitDB()
-		if dbErr != nil {
-			dialog.ShowError(dbErr, myWindow)
-			return
-		}
-
-		// Continue with app initialization
-		initializeMainApp(myWindow, database)
+		s.open(overrides.Profile)
 	}
 
 	myWindow.ShowAndRun()
@@ -92,8 +86,7 @@ func showAddProductDialog(window fyne.Window, db *sql.DB) {
 				return
 			}
 
-			_, err = db.Exec("INSERT INTO products (name, price, active) VALUES (?, ?, true)",
-				nameEntry.Text, price)
+			_, err = internal.AddProduct(db, nameEntry.Text, price)
 			if err != nil {
 				dialog.ShowError(err, window)
 				return
@@ -143,12 +136,9 @@ func showManageProductsDialog(window fyne.Window, db *sql.DB) {
 					"Are you sure you want to deactivate this product? It will no longer be available for new orders.",
 					func(confirm bool) {
 						if confirm {
-							_, err := db.Exec("UPDATE products SET active = false WHERE id = ?", product.ID)
-							if err != nil {
+							if err := internal.DeactivateProduct(db, product.ID); err != nil {
 								dialog.ShowError(err, window)
-								return
 							}
-							showManageProductsDialog(window, db)
 						}
 					},
 					window,
@@ -164,6 +154,18 @@ func showManageProductsDialog(window fyne.Window, db *sql.DB) {
 
 	dialog := dialog.NewCustom("Manage Products", "Close", content, window)
 	dialog.Resize(fyne.NewSize(600, 400))
+
+	// Keep the list current while the dialog is open
+	unsubscribe := internal.DefaultBus.Subscribe(func(internal.Event) {
+		reloaded, err := internal.LoadProducts(db)
+		if err != nil {
+			log.Printf("Error reloading products: %v", err)
+			return
+		}
+		products = reloaded
+		list.Refresh()
+	})
+	dialog.SetOnClosed(unsubscribe)
 	dialog.Show()
 }
 
@@ -195,9 +197,9 @@ func showEditProductDialog(window fyne.Window, db *sql.DB, product internal.Prod
 				return
 			}
 
-			_, err = db.Exec("UPDATE products SET name = ?, price = ? WHERE id = ?",
-				nameEntry.Text, price, product.ID)
-			if err != nil {
+			product.Name = nameEntry.Text
+			product.Price = price
+			if err := internal.UpdateProduct(db, product); err != nil {
 				dialog.ShowError(err, window)
 				return
 			}
@@ -210,26 +212,17 @@ func showEditProductDialog(window fyne.Window, db *sql.DB, product internal.Prod
 	dialog.Show()
 }
 
-func showAddOrderDialog(window fyne.Window, db *sql.DB, refreshTable func()) {
+func showAddOrderDialog(window fyne.Window, db *sql.DB, user internal.User) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Client Name")
 
 	contactEntry := widget.NewEntry()
 	contactEntry.SetPlaceHolder("Contact")
 
-	dueDatePicker := widget.NewEntry()
-	dueDatePicker.SetPlaceHolder("Due Date (YYYY-MM-DD)")
+	duePicker := newDueDatePicker(loadBusinessHours(db), time.Time{})
 
 	var orderItems []internal.OrderItem
 
-	updateTotalPrice := func() float64 {
-		var total float64
-		for _, item := range orderItems {
-			total += item.Price
-		}
-		return total
-	}
-
 	itemsButton := widget.NewButton("Manage Items", func() {
 		products, err := internal.LoadProducts(db)
 		if err != nil {
@@ -244,7 +237,7 @@ func showAddOrderDialog(window fyne.Window, db *sql.DB, refreshTable func()) {
 	commentEntry := widget.NewMultiLineEntry()
 	commentEntry.SetPlaceHolder("Comment")
 
-	representatives, err := internal.LoadRepresentatives(db)
+	representatives, err := orderRepresentatives(db, user)
 	if err != nil {
 		dialog.ShowError(err, window)
 		return
@@ -257,12 +250,16 @@ func showAddOrderDialog(window fyne.Window, db *sql.DB, refreshTable func()) {
 
 	repSelect := widget.NewSelect(repNames, nil)
 	repSelect.PlaceHolder = "Select rep"
+	if !user.Can(internal.PermEditAllOrders) {
+		repSelect.SetSelected(representatives[0].Name)
+		repSelect.Disable()
+	}
 
 	content := container.NewVBox(
 		repSelect,
 		nameEntry,
 		contactEntry,
-		dueDatePicker,
+		widget.NewCard("", "Due", duePicker.content),
 		itemsButton,
 		commentEntry,
 	)
@@ -277,9 +274,9 @@ func showAddOrderDialog(window fyne.Window, db *sql.DB, refreshTable func()) {
 				return
 			}
 
-			dueDate, err := time.Parse("2006-01-02", dueDatePicker.Text)
+			dueDate, err := duePicker.Value()
 			if err != nil {
-				dialog.ShowError(fmt.Errorf("Invalid due date format. Please use YYYY-MM-DD"), window)
+				dialog.ShowError(err, window)
 				return
 			}
 
@@ -291,59 +288,24 @@ func showAddOrderDialog(window fyne.Window, db *sql.DB, refreshTable func()) {
 				}
 			}
 
-			// Begin transaction
-			tx, err := db.Begin()
-			if err != nil {
-				dialog.ShowError(err, window)
-				return
-			}
-			defer tx.Rollback()
-
-			// Insert main order
-			result, err := tx.Exec(`
-                INSERT INTO orders (
-                    created_at, due_date, client_name, contact,
-                    representative_id, comment, completed, total_price
-                ) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-				time.Now(), dueDate, nameEntry.Text, contactEntry.Text,
-				repID, commentEntry.Text, false, updateTotalPrice(),
-			)
-			if err != nil {
-				dialog.ShowError(err, window)
-				return
-			}
-
-			orderID, err := result.LastInsertId()
-			if err != nil {
-				dialog.ShowError(err, window)
-				return
+			order := internal.Order{
+				DueDate:          dueDate,
+				ClientName:       nameEntry.Text,
+				Contact:          contactEntry.Text,
+				RepresentativeID: repID,
+				Comment:          commentEntry.Text,
+				Items:            orderItems,
 			}
-
-			// Insert order items
-			for _, item := range orderItems {
-				_, err = tx.Exec(`
-                    INSERT INTO order_items (
-                        order_id, product_id, quantity, price
-                    ) VALUES (?, ?, ?, ?)`,
-					orderID, item.ProductID, item.Quantity, item.Price,
-				)
-				if err != nil {
+			checkCapacity(window, db, order, func() {
+				if _, err := internal.CreateOrder(db, order); err != nil {
 					dialog.ShowError(err, window)
-					return
 				}
-			}
-
-			if err := tx.Commit(); err != nil {
-				dialog.ShowError(err, window)
-				return
-			}
-
-			refreshTable()
+			})
 		},
 		window,
 	)
 
-	dialog.Resize(fyne.NewSize(600, 500))
+	dialog.Resize(fyne.NewSize(600, 700))
 	dialog.Show()
 }
 
@@ -363,8 +325,7 @@ func showAddRepresentativeDialog(window fyne.Window, db *sql.DB) {
 				return
 			}
 
-			_, err := db.Exec("INSERT INTO representatives (name, active) VALUES (?, true)",
-				nameEntry.Text)
+			_, err := internal.AddRepresentative(db, nameEntry.Text)
 			if err != nil {
 				dialog.ShowError(err, window)
 				return
@@ -408,12 +369,9 @@ func showManageRepresentativesDialog(window fyne.Window, db *sql.DB) {
 					"Are you sure you want to deactivate this representative?",
 					func(confirm bool) {
 						if confirm {
-							_, err := db.Exec("UPDATE representatives SET active = false WHERE id = ?", rep.ID)
-							if err != nil {
+							if err := internal.DeactivateRepresentative(db, rep.ID); err != nil {
 								dialog.ShowError(err, window)
-								return
 							}
-							showManageRepresentativesDialog(window, db)
 						}
 					},
 					window,
@@ -429,22 +387,37 @@ func showManageRepresentativesDialog(window fyne.Window, db *sql.DB) {
 
 	dialog := dialog.NewCustom("Manage Representatives", "Close", content, window)
 	dialog.Resize(fyne.NewSize(500, 400))
+
+	// Keep the list current while the dialog is open
+	unsubscribe := internal.DefaultBus.Subscribe(func(internal.Event) {
+		reloaded, err := internal.LoadRepresentatives(db)
+		if err != nil {
+			log.Printf("Error reloading representatives: %v", err)
+			return
+		}
+		representatives = reloaded
+		list.Refresh()
+	})
+	dialog.SetOnClosed(unsubscribe)
 	dialog.Show()
 }
 
-// Implement showDatabaseConfigDialog using the new db package methods
-func showDatabaseConfigDialog(window fyne.Window, onSaveCallback func()) {
-	// Load existing config
-	existingConfig, _ := db.LoadDbConfig()
-
+// showDatabaseConfigDialog asks for the connection of the active profile,
+// starting from the resolved config. The saved token is never shown, an
+// empty entry keeps it.
+func showDatabaseConfigDialog(window fyne.Window, existingConfig db.Config, onSaveCallback func()) {
 	// Create entries for database URL and auth token
 	urlEntry := widget.NewEntry()
 	urlEntry.SetPlaceHolder("Turso Database URL")
 	urlEntry.SetText(existingConfig.DatabaseURL)
 
-	tokenEntry := widget.NewEntry()
+	tokenEntry := widget.NewPasswordEntry()
 	tokenEntry.SetPlaceHolder("Turso Auth Token")
-	tokenEntry.SetText(existingConfig.AuthToken)
+	savedToken := ""
+	if existingConfig.Sources.AuthToken.Kind == db.SourceFile {
+		savedToken = existingConfig.AuthToken
+		tokenEntry.SetPlaceHolder("Leave empty to keep the saved token")
+	}
 
 	content := container.NewVBox(
 		widget.NewLabel("Configure Turso Database Connection"),
@@ -471,6 +444,9 @@ func showDatabaseConfigDialog(window fyne.Window, onSaveCallback func()) {
 				DatabaseURL: urlEntry.Text,
 				AuthToken:   tokenEntry.Text,
 			}
+			if newConfig.AuthToken == "" {
+				newConfig.AuthToken = savedToken
+			}
 
 			err := db.SaveDbConfig(newConfig)
 			if err != nil {
@@ -492,36 +468,116 @@ func showDatabaseConfigDialog(window fyne.Window, onSaveCallback func()) {
 	dialog.Show()
 }
 
-// New function to initialize main app components
-func initializeMainApp(myWindow fyne.Window, db *sql.DB) {
-
-	// Create menu items
-	mainMenu := fyne.NewMainMenu(
-		fyne.NewMenu("Products",
+// initializeMainApp builds the main window for the logged in user. The
+// returned function stops the background work started for db.
+func initializeMainApp(myWindow fyne.Window, db *sql.DB, user internal.User, s *session) (stop func()) {
+	// Create menu items, leaving out those the user's role does not allow
+	var menus []*fyne.Menu
+	if user.Can(internal.PermManageProducts) {
+		menus = append(menus, fyne.NewMenu("Products",
 			fyne.NewMenuItem("Add New Product", func() {
 				showAddProductDialog(myWindow, db)
 			}),
 			fyne.NewMenuItem("Manage Products", func() {
 				showManageProductsDialog(myWindow, db)
 			}),
-		),
-		fyne.NewMenu("Representatives",
+		))
+	}
+	if user.Can(internal.PermManageRepresentatives) {
+		menus = append(menus, fyne.NewMenu("Representatives",
 			fyne.NewMenuItem("Add New Representative", func() {
 				showAddRepresentativeDialog(myWindow, db)
 			}),
 			fyne.NewMenuItem("Manage Representatives", func() {
 				showManageRepresentativesDialog(myWindow, db)
 			}),
-		),
-		fyne.NewMenu("Settings",
-			fyne.NewMenuItem("Database Connection", func() {
-				showDatabaseConfigDialog(myWindow, nil)
+		))
+	}
+	if user.Can(internal.PermImportData) {
+		menus = append(menus, fyne.NewMenu("Data",
+			fyne.NewMenuItem("Import...", func() {
+				showImportWizard(myWindow, db)
 			}),
-		),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Export Workbook...", func() {
+				showExportWorkbookDialog(myWindow, db)
+			}),
+			fyne.NewMenuItem("Apply Workbook Changes...", func() {
+				showApplyWorkbookDialog(myWindow, db)
+			}),
+		))
+	}
+
+	reportsMenu := fyne.NewMenu("Reports")
+	if user.Can(internal.PermViewReports) {
+		reportsMenu.Items = append(reportsMenu.Items, fyne.NewMenuItem("Sales Report", func() {
+			showSalesReportDialog(myWindow, db)
+		}))
+	}
+	if user.Can(internal.PermViewAuditLog) {
+		reportsMenu.Items = append(reportsMenu.Items, fyne.NewMenuItem("Audit Log", func() {
+			showAuditLogDialog(myWindow, db)
+		}))
+	}
+	if len(reportsMenu.Items) > 0 {
+		menus = append(menus, reportsMenu)
+	}
+
+	settingsMenu := fyne.NewMenu("Settings")
+	if user.Can(internal.PermManageSettings) {
+		settingsMenu.Items = append(settingsMenu.Items,
+			fyne.NewMenuItem("Database Profiles", func() {
+				showDatabaseProfilesDialog(myWindow, s)
+			}),
+			fyne.NewMenuItem("Webhooks", func() {
+				showWebhooksDialog(myWindow, db)
+			}),
+			fyne.NewMenuItem("Business Hours", func() {
+				showBusinessHoursDialog(myWindow, db)
+			}),
+			fyne.NewMenuItem("Daily Capacity", func() {
+				showCapacityDialog(myWindow, db)
+			}),
+			fyne.NewMenuItemSeparator(),
+			fyne.NewMenuItem("Back Up Now...", func() {
+				showBackupNowDialog(myWindow, db)
+			}),
+			fyne.NewMenuItem("Automatic Backups", func() {
+				showAutomaticBackupsDialog(myWindow)
+			}),
+		)
+	}
+	if user.Can(internal.PermManageUsers) {
+		settingsMenu.Items = append(settingsMenu.Items, fyne.NewMenuItem("Users", func() {
+			showManageUsersDialog(myWindow, db)
+		}))
+	}
+	if len(settingsMenu.Items) > 0 {
+		settingsMenu.Items = append(settingsMenu.Items, fyne.NewMenuItemSeparator())
+	}
+	settingsMenu.Items = append(settingsMenu.Items,
+		fyne.NewMenuItem("Live Refresh", func() {
+			showLiveRefreshDialog(myWindow)
+		}),
+		fyne.NewMenuItem("Switch Database...", func() {
+			showSwitchDatabaseDialog(s)
+		}),
+		fyne.NewMenuItem("Change Password", func() {
+			showChangePasswordDialog(myWindow, db, user)
+		}),
 	)
+	menus = append(menus, settingsMenu)
 
 	// Set the main menu
-	myWindow.SetMainMenu(mainMenu)
+	myWindow.SetMainMenu(fyne.NewMainMenu(menus...))
+
+	// Send queued webhook deliveries, make automatic backups and watch for
+	// changes made on other machines in the background
+	ctx, cancel := context.WithCancel(context.Background())
+	unsubscribeWebhooks := webhooks.Subscribe(internal.DefaultBus, db)
+	go webhooks.NewDispatcher(db).Run(ctx, 30*time.Second)
+	startBackupScheduler(ctx, db, s.profile)
+	startChangeWatcher(ctx, db)
 
 	// Initialize order table
 	orderTable := widget.NewTable(
@@ -532,16 +588,49 @@ func initializeMainApp(myWindow fyne.Window, db *sql.DB) {
 		func(id widget.TableCellID, cell fyne.CanvasObject) {},
 	)
 
+	// Dashboard is refreshed together with the order table
+	dashboard, refreshDashboard := newDashboard(db)
+
+	// Search, filters and sort are remembered between sessions
+	filter := loadOrderFilter(s.profile)
+	representatives, err := internal.LoadRepresentatives(db)
+	if err != nil {
+		log.Printf("Error loading representatives: %v", err)
+	}
+
+	// Due dates are shown and filtered in the time zone of the business
+	hours := loadBusinessHours(db)
+
+	// view keeps the orders shown and the selection by order ID, so that a
+	// reload never moves the selection to another order
+	view := internal.NewOrderView(filter.query(representatives, hours.Location()))
+
+	// Enables the actions that apply to the selected orders; set below
+	var updateActions func()
+
+	// The last clicked order is shown in full beside the table
+	detail := newOrderDetailPanel(myWindow, db, user)
+
+	// Orders tapped on the calendar are shown beside the order table
+	var tabs *container.AppTabs
+	calendar, refreshCalendar := newOrderCalendar(myWindow, db, user, func(order internal.Order) {
+		tabs.SelectIndex(1)
+		detail.Show(order.ID)
+	})
+
 	// Refresh function for the order table
 	refreshTable := func() {
-		orders, err := internal.LoadOrders(db)
+		loaded, err := internal.LoadOrdersByStatus(db, filter.Status)
 		if err != nil {
 			log.Printf("Error loading orders: %v", err)
 			return
 		}
+		hours = loadBusinessHours(db)
+		view.SetQuery(filter.query(representatives, hours.Location()))
+		view.SetOrders(loaded)
 
 		orderTable.Length = func() (int, int) {
-			return len(orders) + 1, 8 // +1 for header row
+			return view.Len() + 1, 8 // +1 for header row
 		}
 
 		orderTable.UpdateCell = func(id widget.TableCellID, cell fyne.CanvasObject) {
@@ -550,36 +639,50 @@ func initializeMainApp(myWindow fyne.Window, db *sql.DB) {
 			orderTable.SetColumnWidth(2, 300) // Products
 			orderTable.SetColumnWidth(3, 100) // Total Price
 			orderTable.SetColumnWidth(4, 150) // Representative
-			orderTable.SetColumnWidth(5, 90)  // Due Date
+			orderTable.SetColumnWidth(5, 140) // Due Date
 			orderTable.SetColumnWidth(6, 80)  // Status
 			orderTable.SetColumnWidth(7, 300) // Comment
 
 			label := cell.(*widget.Label)
 			label.Wrapping = fyne.TextWrapWord
+			label.TextStyle.Bold = id.Row == 0
+			label.Importance = widget.MediumImportance
 
 			if id.Row == 0 {
 				// Header row
+				// Click a header to sort by its column
 				switch id.Col {
 				case 0:
-					label.SetText("Date")
+					label.SetText(filter.header(id.Col, "Date"))
 				case 1:
-					label.SetText("Client")
+					label.SetText(filter.header(id.Col, "Client"))
 				case 2:
-					label.SetText("Products")
+					label.SetText(filter.header(id.Col, "Products"))
 				case 3:
-					label.SetText("Total")
+					label.SetText(filter.header(id.Col, "Total"))
 				case 4:
-					label.SetText("Representative")
+					label.SetText(filter.header(id.Col, "Representative"))
 				case 5:
-					label.SetText("Due Date")
+					label.SetText(filter.header(id.Col, "Due Date"))
 				case 6:
-					label.SetText("Status")
+					label.SetText(filter.header(id.Col, "Status"))
 				case 7:
-					label.SetText("Comment")
+					label.SetText(filter.header(id.Col, "Comment"))
 				}
 				return
 			} else {
-				order := orders[id.Row-1]
+				order, ok := view.Order(id.Row - 1)
+				if !ok {
+					label.SetText("")
+					return
+				}
+				// Selected rows are highlighted, as are rows changed
+				// since they were last seen
+				if view.IsSelected(id.Row - 1) {
+					label.Importance = widget.HighImportance
+				} else if view.IsChanged(id.Row - 1) {
+					label.Importance = widget.WarningImportance
+				}
 				switch id.Col {
 				case 0:
 					label.SetText(order.CreatedAt.Format("2006-01-02 15:04"))
@@ -603,74 +706,161 @@ func initializeMainApp(myWindow fyne.Window, db *sql.DB) {
 				case 4:
 					label.SetText(order.RepresentativeName)
 				case 5:
-					label.SetText(order.DueDate.Format("2006-01-02"))
+					label.SetText(hours.FormatDue(order.DueDate))
 				case 6:
-					if order.Completed {
-						label.SetText("Completed")
-					} else {
-						label.SetText("Pending")
-					}
+					label.SetText(order.Status())
 				case 7:
 					label.SetText(order.Comment)
 				}
 			}
 		}
 		orderTable.Refresh()
+		updateActions()
+		detail.Refresh()
+		refreshDashboard()
+		refreshCalendar()
 	}
 
+	filterBar, setFilterRepresentatives := newOrderFilterBar(&filter, representatives, func() {
+		saveOrderFilter(s.profile, filter)
+		refreshTable()
+	})
+
+	// Every committed change, made here or on another machine, refreshes the
+	// representatives, order table and dashboard
+	unsubscribeRefresh := internal.DefaultBus.Subscribe(func(internal.Event) {
+		reps, err := internal.LoadRepresentatives(db)
+		if err != nil {
+			log.Printf("Error loading representatives: %v", err)
+		} else {
+			representatives = reps
+			setFilterRepresentatives(reps)
+		}
+		refreshTable()
+	})
+
 	// Add new order button
 	addOrderBtn := widget.NewButton("+", func() {
-		showAddOrderDialog(myWindow, db, refreshTable)
+		showAddOrderDialog(myWindow, db, user)
 	})
+	if !user.Can(internal.PermCreateOrders) {
+		addOrderBtn.Disable()
+	}
 
 	downloadOrdersBtn := widget.NewButton("Download Orders", func() {
-		// Create dialog with file save picker
-		dialog := dialog.NewFileSave(
-			func(writer fyne.URIWriteCloser, err error) {
-				if err != nil {
-					dialog.ShowError(err, myWindow)
-					return
-				}
-				if writer == nil {
-					return // user cancelled
-				}
-				defer writer.Close()
-
-				// Get the selected path and ensure it ends with .xlsx
-				path := writer.URI().Path()
-				if !strings.HasSuffix(strings.ToLower(path), ".xlsx") {
-					path += ".xlsx"
-				}
-
-				// Export the orders
-				if err := exportOrdersToExcel(db, path); err != nil {
-					dialog.ShowError(err, myWindow)
-					return
-				}
+		showDownloadOrdersDialog(myWindow, db)
+	})
+	if !user.Can(internal.PermExportOrders) {
+		downloadOrdersBtn.Hide()
+	}
 
-				dialog.ShowInformation("Success",
-					"Orders have been exported successfully to:\n"+path,
-					myWindow)
-			},
-			myWindow)
+	// Click rows to select them; the actions apply to the selected orders
+	selectionLabel := widget.NewLabel("")
+	editBtn := widget.NewButton("Edit", func() {
+		selected := view.Selected()
+		if len(selected) != 1 {
+			return
+		}
+		// Edit the current version, not the one loaded with the table
+		order, err := internal.LoadOrder(db, selected[0].ID)
+		if err != nil {
+			dialog.ShowError(err, myWindow)
+			return
+		}
+		showEditOrderDialog(myWindow, db, order, user)
+	})
+	// Bulk actions ask once and change all selected orders in one transaction
+	clearSelection := func() {
+		view.ClearSelection()
+		orderTable.Refresh()
+		updateActions()
+	}
+	completeBtn := widget.NewButton("Mark Complete", func() {
+		showBulkCompleteDialog(myWindow, db, pendingOrders(view.Selected()), clearSelection)
+	})
+	reassignBtn := widget.NewButton("Reassign...", func() {
+		showBulkReassignDialog(myWindow, db, view.Selected(), clearSelection)
+	})
+	dueDateBtn := widget.NewButton("Change Due Date...", func() {
+		showBulkDueDateDialog(myWindow, db, view.Selected(), clearSelection)
+	})
+	cancelOrdersBtn := widget.NewButton("Cancel Orders", func() {
+		showBulkCancelDialog(myWindow, db, pendingOrders(view.Selected()), clearSelection)
+	})
+	exportSelectedBtn := widget.NewButton("Export Selected...", func() {
+		showExportSelectedDialog(myWindow, db, view.Selected())
+	})
+	if !user.Can(internal.PermExportOrders) {
+		exportSelectedBtn.Hide()
+	}
+	selectAllBtn := widget.NewButton("Select All", func() {
+		view.SelectAll()
+		orderTable.Refresh()
+		updateActions()
+	})
+	clearSelectionBtn := widget.NewButton("Clear Selection", clearSelection)
+	markSeenBtn := widget.NewButton("Mark All Seen", func() {
+		view.MarkAllSeen()
+		orderTable.Refresh()
+		updateActions()
+	})
 
-		// Set default filename
-		dialog.SetFileName(fmt.Sprintf("orders_%s.xlsx",
-			time.Now().Format("2006-01-02")))
+	updateActions = func() {
+		selected := view.Selected()
+		switch len(selected) {
+		case 0:
+			selectionLabel.SetText("")
+		case 1:
+			selectionLabel.SetText("1 order selected")
+		default:
+			selectionLabel.SetText(fmt.Sprintf("%d orders selected", len(selected)))
+		}
 
-		// Set filter for Excel files
-		dialog.SetFilter(storage.NewExtensionFileFilter([]string{".xlsx"}))
+		if len(selected) == 1 && user.CanEditOrder(selected[0]) {
+			editBtn.Enable()
+		} else {
+			editBtn.Disable()
+		}
 
-		dialog.Show()
-	})
+		pending := len(pendingOrders(selected)) > 0
+		if pending && user.Can(internal.PermCompleteOrders) {
+			completeBtn.Enable()
+		} else {
+			completeBtn.Disable()
+		}
 
-	completeBtn := widget.NewButton("Mark Complete", func() {})
-	editBtn := widget.NewButton("Edit", func() {})
+		// Changing orders requires being allowed to edit every one of them
+		editable := len(selected) > 0
+		for _, order := range selected {
+			editable = editable && user.CanEditOrder(order)
+		}
+		setEnabled(reassignBtn, editable && user.Can(internal.PermEditAllOrders))
+		setEnabled(dueDateBtn, editable)
+		setEnabled(cancelOrdersBtn, editable && pending)
+		setEnabled(exportSelectedBtn, len(selected) > 0)
+		setEnabled(clearSelectionBtn, len(selected) > 0)
+
+		if changed := view.Changed(); changed > 0 {
+			markSeenBtn.SetText(fmt.Sprintf("Mark All Seen (%d changed)", changed))
+			markSeenBtn.Show()
+		} else {
+			markSeenBtn.Hide()
+		}
+	}
 
 	actions := container.NewHBox(
 		editBtn,
 		completeBtn,
+		reassignBtn,
+		dueDateBtn,
+		cancelOrdersBtn,
+		exportSelectedBtn,
 		downloadOrdersBtn,
+		widget.NewSeparator(),
+		selectAllBtn,
+		clearSelectionBtn,
+		selectionLabel,
+		markSeenBtn,
 	)
 
 	// Create the layout
@@ -679,40 +869,55 @@ func initializeMainApp(myWindow fyne.Window, db *sql.DB) {
 		addOrderBtn,
 	)
 
-	content := container.NewHSplit(
-		form,
+	tableSplit := container.NewHSplit(
 		container.NewBorder(
-			nil,
+			filterBar,
 			actions,
 			nil,
 			nil,
 			orderTable,
 		),
+		detail.content,
 	)
+	tableSplit.SetOffset(0.7)
 
-	content.SetOffset(0.03)
-	myWindow.SetContent(content)
+	content := container.NewHSplit(
+		form,
+		tableSplit,
+	)
 
-	orderTable.OnSelected = func(id widget.TableCellID) {
-		if id.Row > 0 {
-			orders, _ := internal.LoadOrders(db)
-			order := orders[id.Row-1]
+	content.SetOffset(0.03)
 
-			// Update button actions instead of creating new buttons
-			editBtn.OnTapped = func() {
-				showEditOrderDialog(myWindow, db, order, refreshTable)
-			}
+	// Open on the dashboard, with the order table and calendar in their own
+	// tabs
+	tabs = container.NewAppTabs(
+		container.NewTabItemWithIcon("Dashboard", theme.HomeIcon(), dashboard),
+		container.NewTabItemWithIcon("Orders", theme.ListIcon(), content),
+		container.NewTabItemWithIcon("Calendar", theme.GridIcon(), calendar),
+	)
+	tabs.SelectIndex(0)
+	myWindow.SetContent(tabs)
 
-			completeBtn.OnTapped = func() {
-				_, err := db.Exec("UPDATE orders SET completed = true WHERE id = ?", order.ID)
-				if err != nil {
-					dialog.ShowError(err, myWindow)
-					return
-				}
+	orderTable.OnSelected = func(id widget.TableCellID) {
+		if id.Row == 0 {
+			if filter.toggleSort(id.Col) {
+				saveOrderFilter(s.profile, filter)
 				refreshTable()
 			}
+			orderTable.UnselectAll()
+			return
+		}
 
+		// Selecting is done by the view, not the table, so that several
+		// orders can be selected. Clicking a changed order marks it as seen.
+		view.Toggle(id.Row - 1)
+		view.MarkSeen(id.Row - 1)
+		if order, ok := view.Order(id.Row - 1); ok {
+			detail.Show(order.ID)
 		}
+		orderTable.UnselectAll()
+		orderTable.Refresh()
+		updateActions()
 	}
 
 	myWindow.Resize(fyne.NewSize(1024, 768))
@@ -720,168 +925,31 @@ func initializeMainApp(myWindow fyne.Window, db *sql.DB) {
 	// Initial table load
 	refreshTable()
 
-	// Add close handler
-	myWindow.SetOnClosed(func() {
-		db.Close()
-	})
+	return func() {
+		unsubscribeRefresh()
+		unsubscribeWebhooks()
+		cancel()
+	}
 }
 
 func exportOrdersToExcel(db *sql.DB, filePath string) error {
-	// Query orders with joined product and representative information
-	query := `
-        SELECT
-            o.id,
-            r.name as representative_name,
-            o.completed,
-            o.created_at,
-            o.client_name,
-            o.contact,
-            o.due_date,
-            p.name as product_name,
-            oi.quantity,
-            p.price as product_price,
-            oi.price as item_price,
-            o.total_price,
-            o.comment
-        FROM orders o
-        LEFT JOIN representatives r ON o.representative_id = r.id
-        LEFT JOIN order_items oi ON o.id = oi.order_id
-        LEFT JOIN products p ON oi.product_id = p.id
-        ORDER BY o.created_at DESC, o.id, p.name
-    `
-
-	rows, err := db.Query(query)
-	if err != nil {
-		return fmt.Errorf("error querying orders: %w", err)
-	}
-	defer rows.Close()
-
-	// Create a new Excel file
-	f := excelize.NewFile()
-	sheetName := "Orders"
-	f.SetSheetName("Sheet1", sheetName)
-
-	// Define headers
-	headers := []string{
-		"Order ID",
-		"Representative",
-		"Status",
-		"Date",
-		"Client Name",
-		"Contact",
-		"Due Date",
-		"Product Name",
-		"Product Quantity",
-		"Product Unit Price",
-		"Product Total",
-		"Total Order Price",
-		"Comment",
-	}
-
-	// Write headers
-	for i, header := range headers {
-		col, _ := excelize.ColumnNumberToName(i + 1)
-		f.SetCellValue(sheetName, col+"1", header)
+	return export.ExportFileWith(db, filePath, export.ExcelExporter{}, export.Filter{})
+}
 
-		// Set column width based on content
-		f.SetColWidth(sheetName, col, col, 13)
+// orderRepresentatives returns the representatives the user may assign orders
+// to: all of them, or only their own for representatives
+func orderRepresentatives(db *sql.DB, user internal.User) ([]internal.Representative, error) {
+	representatives, err := internal.LoadRepresentatives(db)
+	if err != nil || user.Can(internal.PermEditAllOrders) {
+		return representatives, err
 	}
 
-	// Write data rows
-	rowIndex := 2
-	for rows.Next() {
-		var (
-			id           int64
-			repName      sql.NullString
-			completed    bool
-			createdAt    time.Time
-			clientName   string
-			contact      string
-			dueDate      time.Time
-			productName  sql.NullString
-			quantity     sql.NullInt64
-			itemPrice    sql.NullFloat64
-			productPrice sql.NullFloat64
-			totalPrice   float64
-			comment      sql.NullString
-		)
-
-		err := rows.Scan(
-			&id,
-			&repName,
-			&completed,
-			&createdAt,
-			&clientName,
-			&contact,
-			&dueDate,
-			&productName,
-			&quantity,
-			&itemPrice,
-			&productPrice,
-			&totalPrice,
-			&comment,
-		)
-		if err != nil {
-			return fmt.Errorf("error scanning row: %w", err)
-		}
-
-		// Format status
-		status := "Pending"
-		if completed {
-			status = "Completed"
-		}
-
-		// Write row data
-		rowData := []interface{}{
-			id,
-			repName.String,
-			status,
-			createdAt.Format("2006-01-02 15:04"),
-			clientName,
-			contact,
-			dueDate.Format("2006-01-02"),
-			productName.String,
-			quantity.Int64,
-			fmt.Sprintf("R%.2f", itemPrice.Float64),
-			fmt.Sprintf("R%.2f", productPrice.Float64),
-			fmt.Sprintf("R%.2f", totalPrice),
-			comment.String,
-		}
-
-		for i, value := range rowData {
-			col, _ := excelize.ColumnNumberToName(i + 1)
-			f.SetCellValue(sheetName, fmt.Sprintf("%s%d", col, rowIndex), value)
+	for _, r := range representatives {
+		if r.ID == user.RepresentativeID {
+			return []internal.Representative{r}, nil
 		}
-		rowIndex++
 	}
-
-	// Apply styling
-	style, err := f.NewStyle(&excelize.Style{
-		Font: &excelize.Font{
-			Bold: true,
-		},
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Color:   []string{"#E0E0E0"},
-			Pattern: 1,
-		},
-	})
-	if err == nil {
-		// Apply style to header row
-		f.SetRowStyle(sheetName, 1, 1, style)
-	}
-
-	// Auto-filter for all columns
-	lastCol, _ := excelize.ColumnNumberToName(len(headers))
-	ref := fmt.Sprintf("A1:%s%d", lastCol, rowIndex-1)
-	f.AutoFilter(sheetName, ref, []excelize.AutoFilterOptions{})
-
-	// Save the file
-	if err := f.SaveAs(filePath); err != nil {
-		return fmt.Errorf("error saving Excel file: %w", err)
-	}
-
-	return nil
+	return nil, fmt.Errorf("your account is not linked to an active representative")
 }
 
 type OrderItemEntry struct {
@@ -891,20 +959,19 @@ type OrderItemEntry struct {
 	DeleteButton  *widget.Button
 }
 
-func showEditOrderDialog(window fyne.Window, db *sql.DB, order internal.Order, refreshTable func()) {
+func showEditOrderDialog(window fyne.Window, db *sql.DB, order internal.Order, user internal.User) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetText(order.ClientName)
 
 	contactEntry := widget.NewEntry()
 	contactEntry.SetText(order.Contact)
 
-	dueDatePicker := widget.NewEntry()
-	dueDatePicker.SetText(order.DueDate.Format("2006-01-02"))
+	duePicker := newDueDatePicker(loadBusinessHours(db), order.DueDate)
 
 	commentEntry := widget.NewMultiLineEntry()
 	commentEntry.SetText(order.Comment)
 
-	representatives, err := internal.LoadRepresentatives(db)
+	representatives, err := orderRepresentatives(db, user)
 	if err != nil {
 		dialog.ShowError(err, window)
 		return
@@ -922,6 +989,9 @@ func showEditOrderDialog(window fyne.Window, db *sql.DB, order internal.Order, r
 			break
 		}
 	}
+	if !user.Can(internal.PermEditAllOrders) {
+		repSelect.Disable()
+	}
 
 	var orderItems []internal.OrderItem = order.Items
 	itemsButton := widget.NewButton("Manage Items", func() {
@@ -939,7 +1009,7 @@ func showEditOrderDialog(window fyne.Window, db *sql.DB, order internal.Order, r
 		repSelect,
 		nameEntry,
 		contactEntry,
-		dueDatePicker,
+		widget.NewCard("", "Due", duePicker.content),
 		itemsButton,
 		commentEntry,
 	)
@@ -954,9 +1024,9 @@ func showEditOrderDialog(window fyne.Window, db *sql.DB, order internal.Order, r
 				return
 			}
 
-			dueDate, err := time.Parse("2006-01-02", dueDatePicker.Text)
+			dueDate, err := duePicker.Value()
 			if err != nil {
-				dialog.ShowError(fmt.Errorf("Invalid due date format. Please use YYYY-MM-DD"), window)
+				dialog.ShowError(err, window)
 				return
 			}
 
@@ -974,29 +1044,31 @@ func showEditOrderDialog(window fyne.Window, db *sql.DB, order internal.Order, r
 				totalPrice += item.Price
 			}
 
-			// Update order
+			// Update order; the version tells whether someone else saved it
+			// while this dialog was open
 			updatedOrder := internal.Order{
-				ID:               order.ID,
-				DueDate:          dueDate,
-				ClientName:       nameEntry.Text,
-				Contact:          contactEntry.Text,
-				RepresentativeID: repID,
-				Comment:          commentEntry.Text,
-				TotalPrice:       totalPrice,
-				Items:            orderItems,
-			}
-
-			if err := internal.EditOrder(db, updatedOrder); err != nil {
-				dialog.ShowError(err, window)
-				return
+				ID:                 order.ID,
+				DueDate:            dueDate,
+				ClientName:         nameEntry.Text,
+				Contact:            contactEntry.Text,
+				RepresentativeID:   repID,
+				RepresentativeName: repSelect.Selected,
+				NeedsDelivery:      order.NeedsDelivery,
+				DeliveryAddress:    order.DeliveryAddress,
+				Comment:            commentEntry.Text,
+				TotalPrice:         totalPrice,
+				Items:              orderItems,
+				Version:            order.Version,
 			}
 
-			refreshTable()
+			checkCapacity(window, db, updatedOrder, func() {
+				saveEditedOrder(window, db, updatedOrder, user)
+			})
 		},
 		window,
 	)
 
-	dialog.Resize(fyne.NewSize(600, 500))
+	dialog.Resize(fyne.NewSize(600, 700))
 	dialog.Show()
 }
 
@@ -1117,9 +1189,10 @@ func showOrderItemsDialog(window fyne.Window, products []internal.Product,
 					}
 				}
 				items = append(items, internal.OrderItem{
-					ProductID: productID,
-					Quantity:  quantity,
-					Price:     price,
+					ProductID:   productID,
+					ProductName: entry.ProductSelect.Selected,
+					Quantity:    quantity,
+					Price:       price,
 				})
 			}
 		}
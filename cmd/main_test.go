@@ -22,13 +22,14 @@ func TestExportOrdersToExcel(t *testing.T) {
 
 	// Mock data
 	rows := sqlmock.NewRows([]string{
-		"id", "representative_name", "completed", "created_at", "client_name",
+		"id", "representative_name", "completed", "cancelled", "created_at", "client_name",
 		"contact", "due_date", "product_name", "quantity", "item_price",
 		"product_price", "total_price", "comment",
 	}).AddRow(
 		1,
 		"John Doe",
 		true,
+		false,
 		time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
 		"Client A",
 		"client@example.com",
@@ -135,7 +136,7 @@ func TestExportOrdersToExcel_EmptyData(t *testing.T) {
 	defer db.Close()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "representative_name", "completed", "created_at", "client_name",
+		"id", "representative_name", "completed", "cancelled", "created_at", "client_name",
 		"contact", "due_date", "product_name", "quantity", "item_price",
 		"product_price", "total_price", "comment",
 	})
@@ -0,0 +1,94 @@
+// cmd/orderConflict.go
+package main
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	keepYours  = "Yours"
+	keepTheirs = "Theirs"
+)
+
+// saveEditedOrder saves an edited order, and lets the user merge or reload
+// when someone else changed it in the meantime
+func saveEditedOrder(window fyne.Window, db *sql.DB, order internal.Order, user internal.User) {
+	err := internal.EditOrder(db, order)
+	var conflict *internal.ConflictError
+	if errors.As(err, &conflict) {
+		showOrderConflictDialog(window, db, conflict, user)
+	} else if err != nil {
+		dialog.ShowError(err, window)
+	}
+}
+
+// showOrderConflictDialog shows both versions of the fields that differ and
+// saves the chosen ones on top of the other person's changes. Reload drops
+// the unsaved changes and edits the current order instead.
+func showOrderConflictDialog(window fyne.Window, db *sql.DB, conflict *internal.ConflictError, user internal.User) {
+	differences := conflict.Differences()
+	keepMine := make(map[internal.OrderField]bool)
+
+	grid := container.NewGridWithColumns(4,
+		widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Your changes", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Saved by someone else", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle("Keep", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	for _, field := range differences {
+		field := field
+		keepMine[field] = true
+		choice := widget.NewRadioGroup([]string{keepYours, keepTheirs}, func(selected string) {
+			keepMine[field] = selected == keepYours
+		})
+		choice.Required = true
+		choice.SetSelected(keepYours)
+
+		mine := widget.NewLabel(internal.FieldValue(conflict.Mine, field))
+		mine.Wrapping = fyne.TextWrapWord
+		theirs := widget.NewLabel(internal.FieldValue(conflict.Theirs, field))
+		theirs.Wrapping = fyne.TextWrapWord
+		grid.Add(widget.NewLabel(string(field)))
+		grid.Add(mine)
+		grid.Add(theirs)
+		grid.Add(choice)
+	}
+
+	message := "Someone else saved this order while you were editing it. " +
+		"Choose which version of each field to keep, or reload the order and start again."
+	if len(differences) == 0 {
+		message = "Someone else saved this order while you were editing it, " +
+			"with the same values as your changes."
+	}
+	intro := widget.NewLabel(message)
+	intro.Wrapping = fyne.TextWrapWord
+
+	conflictDialog := dialog.NewCustomWithoutButtons("Order Changed",
+		container.NewBorder(intro, nil, nil, nil, container.NewVScroll(grid)), window)
+
+	saveBtn := widget.NewButton("Save Merged", func() {
+		conflictDialog.Hide()
+		saveEditedOrder(window, db, conflict.Merge(keepMine), user)
+	})
+	saveBtn.Importance = widget.HighImportance
+	if len(differences) == 0 {
+		saveBtn.Disable()
+	}
+	reloadBtn := widget.NewButton("Reload", func() {
+		conflictDialog.Hide()
+		showEditOrderDialog(window, db, conflict.Theirs, user)
+	})
+	cancelBtn := widget.NewButton("Cancel", conflictDialog.Hide)
+
+	conflictDialog.SetButtons([]fyne.CanvasObject{cancelBtn, reloadBtn, saveBtn})
+	conflictDialog.Resize(fyne.NewSize(800, 500))
+	conflictDialog.Show()
+}
@@ -0,0 +1,281 @@
+// cmd/orderDetail.go
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// paymentMethods are offered when recording a payment; others can be typed
+var paymentMethods = []string{"Cash", "Card", "EFT"}
+
+// orderDetailPanel shows everything about one order, read-only, beside the
+// order table
+type orderDetailPanel struct {
+	window  fyne.Window
+	db      *sql.DB
+	user    internal.User
+	orderID int64
+	box     *fyne.Container
+	content fyne.CanvasObject
+}
+
+func newOrderDetailPanel(window fyne.Window, db *sql.DB, user internal.User) *orderDetailPanel {
+	p := &orderDetailPanel{window: window, db: db, user: user, box: container.NewVBox()}
+	p.content = container.NewVScroll(p.box)
+	p.Clear()
+	return p
+}
+
+// Show loads and shows an order
+func (p *orderDetailPanel) Show(id int64) {
+	p.orderID = id
+	p.Refresh()
+}
+
+// Clear shows a hint instead of an order
+func (p *orderDetailPanel) Clear() {
+	p.orderID = 0
+	hint := widget.NewLabel("Click an order to see its details")
+	hint.Importance = widget.LowImportance
+	p.box.Objects = []fyne.CanvasObject{hint}
+	p.box.Refresh()
+}
+
+// Refresh reloads the order shown, for example after it was changed
+func (p *orderDetailPanel) Refresh() {
+	if p.orderID == 0 {
+		return
+	}
+	details, err := internal.LoadOrderDetails(p.db, p.orderID)
+	if errors.Is(err, internal.ErrNotFound) {
+		p.Clear()
+		return
+	}
+	if err != nil {
+		p.box.Objects = []fyne.CanvasObject{widget.NewLabel(fmt.Sprintf("Error loading order: %v", err))}
+		p.box.Refresh()
+		return
+	}
+
+	order := details.Order
+	title := widget.NewLabelWithStyle(fmt.Sprintf("Order %d - %s", order.ID, order.ClientName),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	editBtn := widget.NewButton("Edit...", func() {
+		// Edit the current version, not the one shown
+		current, err := internal.LoadOrder(p.db, order.ID)
+		if err != nil {
+			dialog.ShowError(err, p.window)
+			return
+		}
+		showEditOrderDialog(p.window, p.db, current, p.user)
+	})
+	if !p.user.CanEditOrder(order) || order.Status() != internal.StatusPending {
+		editBtn.Disable()
+	}
+	paymentBtn := widget.NewButton("Record Payment...", func() {
+		showRecordPaymentDialog(p.window, p.db, order.ID, details.Balance())
+	})
+	if !p.user.CanEditOrder(order) && !p.user.Can(internal.PermCompleteOrders) {
+		paymentBtn.Disable()
+	}
+	noteBtn := widget.NewButton("Add Note...", func() {
+		showAddNoteDialog(p.window, p.db, order.ID)
+	})
+
+	p.box.Objects = []fyne.CanvasObject{
+		title,
+		container.NewHBox(editBtn, paymentBtn, noteBtn),
+		widget.NewCard("Order", "", orderFieldsForm(order, loadBusinessHours(p.db))),
+		widget.NewCard("Items", "", orderItemsGrid(order)),
+		widget.NewCard("Payments", "", paymentsList(details)),
+		widget.NewCard("Status", "", timelineList(details.Timeline())),
+		widget.NewCard("Notes", "", notesList(details.Notes)),
+		widget.NewCard("History", "", historyList(details.History)),
+	}
+	p.box.Refresh()
+}
+
+func wrappedLabel(text string) *widget.Label {
+	label := widget.NewLabel(text)
+	label.Wrapping = fyne.TextWrapWord
+	return label
+}
+
+func orderFieldsForm(order internal.Order, hours internal.BusinessHours) fyne.CanvasObject {
+	delivery := "No"
+	if order.NeedsDelivery {
+		delivery = "Yes"
+	}
+	return widget.NewForm(
+		widget.NewFormItem("Status", widget.NewLabel(order.Status())),
+		widget.NewFormItem("Ordered", widget.NewLabel(order.CreatedAt.Format("2006-01-02 15:04"))),
+		widget.NewFormItem("Due", widget.NewLabel(hours.FormatDue(order.DueDate))),
+		widget.NewFormItem("Client", wrappedLabel(order.ClientName)),
+		widget.NewFormItem("Contact", wrappedLabel(order.Contact)),
+		widget.NewFormItem("Representative", widget.NewLabel(order.RepresentativeName)),
+		widget.NewFormItem("Delivery", widget.NewLabel(delivery)),
+		widget.NewFormItem("Address", wrappedLabel(order.DeliveryAddress)),
+		widget.NewFormItem("Comment", wrappedLabel(order.Comment)),
+	)
+}
+
+func orderItemsGrid(order internal.Order) fyne.CanvasObject {
+	bold := fyne.TextStyle{Bold: true}
+	grid := container.NewGridWithColumns(4,
+		widget.NewLabelWithStyle("Product", fyne.TextAlignLeading, bold),
+		widget.NewLabelWithStyle("Qty", fyne.TextAlignTrailing, bold),
+		widget.NewLabelWithStyle("Unit", fyne.TextAlignTrailing, bold),
+		widget.NewLabelWithStyle("Line", fyne.TextAlignTrailing, bold),
+	)
+	for _, item := range order.Items {
+		grid.Add(wrappedLabel(item.ProductName))
+		grid.Add(widget.NewLabelWithStyle(strconv.Itoa(item.Quantity), fyne.TextAlignTrailing, fyne.TextStyle{}))
+		grid.Add(widget.NewLabelWithStyle(fmt.Sprintf("R%.2f", item.UnitPrice()), fyne.TextAlignTrailing, fyne.TextStyle{}))
+		grid.Add(widget.NewLabelWithStyle(fmt.Sprintf("R%.2f", item.Price), fyne.TextAlignTrailing, fyne.TextStyle{}))
+	}
+	total := widget.NewLabelWithStyle(fmt.Sprintf("Total R%.2f", order.TotalPrice), fyne.TextAlignTrailing, bold)
+	return container.NewVBox(grid, total)
+}
+
+func paymentsList(details internal.OrderDetails) fyne.CanvasObject {
+	box := container.NewVBox()
+	for _, payment := range details.Payments {
+		text := fmt.Sprintf("%s  R%.2f", payment.PaidAt.Format("2006-01-02 15:04"), payment.Amount)
+		if payment.Method != "" {
+			text += "  " + payment.Method
+		}
+		if payment.Reference != "" {
+			text += " (" + payment.Reference + ")"
+		}
+		box.Add(widget.NewLabel(text + "  by " + payment.User))
+	}
+
+	balance := widget.NewLabelWithStyle(
+		fmt.Sprintf("Paid R%.2f, balance R%.2f", details.Paid(), details.Balance()),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	if details.Balance() > 0 {
+		balance.Importance = widget.WarningImportance
+	}
+	box.Add(balance)
+	return box
+}
+
+func timelineList(timeline []internal.StatusChange) fyne.CanvasObject {
+	box := container.NewVBox()
+	for _, change := range timeline {
+		when := "date unknown"
+		if !change.Time.IsZero() {
+			when = change.Time.Format("2006-01-02 15:04")
+		}
+		text := when + "  " + change.Status
+		if change.User != "" {
+			text += " by " + change.User
+		}
+		box.Add(widget.NewLabel(text))
+	}
+	return box
+}
+
+func notesList(notes []internal.Note) fyne.CanvasObject {
+	if len(notes) == 0 {
+		return widget.NewLabel("No notes")
+	}
+	box := container.NewVBox()
+	for _, note := range notes {
+		header := widget.NewLabelWithStyle(
+			note.CreatedAt.Format("2006-01-02 15:04")+"  "+note.User,
+			fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		box.Add(container.NewVBox(header, wrappedLabel(note.Body)))
+	}
+	return box
+}
+
+func historyList(history []internal.AuditEntry) fyne.CanvasObject {
+	if len(history) == 0 {
+		return widget.NewLabel("No recorded changes")
+	}
+	box := container.NewVBox()
+	for _, e := range history {
+		text := fmt.Sprintf("%s  %s %s", e.CreatedAt.Format("2006-01-02 15:04"), e.User, e.Action)
+		if e.Action == internal.ActionUpdate {
+			var fields []string
+			for _, field := range e.ChangedFields() {
+				// The version changes with every edit
+				if field != "Version" {
+					fields = append(fields, field)
+				}
+			}
+			if len(fields) > 0 {
+				text += ": " + strings.Join(fields, ", ")
+			}
+		}
+		box.Add(wrappedLabel(text))
+	}
+	return box
+}
+
+func showRecordPaymentDialog(window fyne.Window, db *sql.DB, orderID int64, balance float64) {
+	amountEntry := widget.NewEntry()
+	if balance > 0 {
+		amountEntry.SetText(fmt.Sprintf("%.2f", balance))
+	}
+	methodSelect := widget.NewSelectEntry(paymentMethods)
+	referenceEntry := widget.NewEntry()
+	referenceEntry.SetPlaceHolder("Optional")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Amount", amountEntry),
+		widget.NewFormItem("Method", methodSelect),
+		widget.NewFormItem("Reference", referenceEntry),
+	}
+	dialog.ShowForm("Record Payment", "Record", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		amount, err := strconv.ParseFloat(strings.TrimSpace(amountEntry.Text), 64)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("Invalid amount"), window)
+			return
+		}
+		_, err = internal.RecordPayment(db, internal.Payment{
+			OrderID:   orderID,
+			Amount:    amount,
+			Method:    methodSelect.Text,
+			Reference: referenceEntry.Text,
+		})
+		if err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+}
+
+func showAddNoteDialog(window fyne.Window, db *sql.DB, orderID int64) {
+	noteEntry := widget.NewMultiLineEntry()
+	noteEntry.SetMinRowsVisible(4)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Note", noteEntry),
+	}
+	formDialog := dialog.NewForm("Add Note", "Add", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		if _, err := internal.AddNote(db, orderID, noteEntry.Text); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
+	formDialog.Resize(fyne.NewSize(500, 250))
+	formDialog.Show()
+}
@@ -0,0 +1,203 @@
+// cmd/orderFilter.go
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/export"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const allRepresentatives = "All representatives"
+
+// orderColumnSorts is the sort of each order table column, empty for columns
+// that cannot be sorted
+var orderColumnSorts = []string{
+	internal.SortCreated,
+	internal.SortClient,
+	"",
+	internal.SortTotal,
+	internal.SortRepresentative,
+	internal.SortDueDate,
+	internal.SortStatus,
+	"",
+}
+
+// orderFilter is the search, filter and sort state of the order table as the
+// user entered it. It is remembered per profile in the preferences, since
+// representatives differ between databases.
+type orderFilter struct {
+	Search         string `json:"search,omitempty"`
+	Representative string `json:"representative,omitempty"`
+	DueFrom        string `json:"due_from,omitempty"`
+	DueTo          string `json:"due_to,omitempty"`
+	Status         string `json:"status,omitempty"`
+	SortBy         string `json:"sort_by,omitempty"`
+	Descending     bool   `json:"descending,omitempty"`
+}
+
+// defaultOrderFilter shows the pending orders, newest first
+var defaultOrderFilter = orderFilter{
+	Status:     internal.StatusPending,
+	SortBy:     internal.SortCreated,
+	Descending: true,
+}
+
+func orderFilterKey(profile string) string {
+	return "orders.filter." + profile
+}
+
+func loadOrderFilter(profile string) orderFilter {
+	filter := defaultOrderFilter
+	saved := fyne.CurrentApp().Preferences().String(orderFilterKey(profile))
+	if saved != "" {
+		if err := json.Unmarshal([]byte(saved), &filter); err != nil {
+			log.Printf("Error reading saved order filter: %v", err)
+			return defaultOrderFilter
+		}
+	}
+	return filter
+}
+
+func saveOrderFilter(profile string, filter orderFilter) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		log.Printf("Error saving order filter: %v", err)
+		return
+	}
+	fyne.CurrentApp().Preferences().SetString(orderFilterKey(profile), string(data))
+}
+
+// query converts the filter to an order query, with days starting in loc.
+// Dates that are not complete yet are ignored rather than reported, since the
+// filter is applied while typing.
+func (f orderFilter) query(representatives []internal.Representative, loc *time.Location) internal.OrderQuery {
+	q := internal.OrderQuery{
+		Search:     f.Search,
+		Status:     f.Status,
+		SortBy:     f.SortBy,
+		Descending: f.Descending,
+	}
+	for _, r := range representatives {
+		if r.Name == f.Representative {
+			q.RepresentativeID = r.ID
+		}
+	}
+	if dates, err := export.ParseFilter(f.DueFrom, "", "", loc); err == nil {
+		q.DueFrom = dates.From
+	}
+	if dates, err := export.ParseFilter("", f.DueTo, "", loc); err == nil {
+		q.DueTo = dates.To
+	}
+	return q
+}
+
+// toggleSort sorts by the column, or reverses the order when it is already
+// sorted by it
+func (f *orderFilter) toggleSort(col int) bool {
+	if col < 0 || col >= len(orderColumnSorts) || orderColumnSorts[col] == "" {
+		return false
+	}
+	if f.SortBy == orderColumnSorts[col] {
+		f.Descending = !f.Descending
+	} else {
+		f.SortBy = orderColumnSorts[col]
+		f.Descending = false
+	}
+	return true
+}
+
+// header returns the column title with an arrow on the sorted column
+func (f orderFilter) header(col int, title string) string {
+	if orderColumnSorts[col] == "" || orderColumnSorts[col] != f.SortBy {
+		return title
+	}
+	if f.Descending {
+		return title + " ▼"
+	}
+	return title + " ▲"
+}
+
+// newOrderFilterBar returns the search and filter controls of the order
+// table, and a function to update the representatives to choose from.
+// onChange is called with every change.
+func newOrderFilterBar(filter *orderFilter, representatives []internal.Representative, onChange func()) (fyne.CanvasObject, func([]internal.Representative)) {
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search client, contact, product or comment")
+	searchEntry.SetText(filter.Search)
+	searchEntry.OnChanged = func(text string) {
+		filter.Search = text
+		onChange()
+	}
+
+	repNames := func(representatives []internal.Representative) []string {
+		names := []string{allRepresentatives}
+		for _, r := range representatives {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+	repSelect := widget.NewSelect(repNames(representatives), nil)
+	repSelect.SetSelected(allRepresentatives)
+	if filter.Representative != "" {
+		repSelect.SetSelected(filter.Representative)
+	}
+	repSelect.OnChanged = func(name string) {
+		filter.Representative = strings.TrimPrefix(name, allRepresentatives)
+		onChange()
+	}
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("Due from YYYY-MM-DD")
+	fromEntry.SetText(filter.DueFrom)
+	fromEntry.OnChanged = func(text string) {
+		filter.DueFrom = text
+		onChange()
+	}
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("Due to YYYY-MM-DD")
+	toEntry.SetText(filter.DueTo)
+	toEntry.OnChanged = func(text string) {
+		filter.DueTo = text
+		onChange()
+	}
+
+	statusSelect := widget.NewSelect(append([]string{"All"}, internal.Statuses...), nil)
+	statusSelect.SetSelected("All")
+	if filter.Status != "" {
+		statusSelect.SetSelected(filter.Status)
+	}
+	statusSelect.OnChanged = func(status string) {
+		filter.Status = strings.TrimPrefix(status, "All")
+		onChange()
+	}
+
+	clearBtn := widget.NewButton("Clear", func() {
+		sortBy, descending := filter.SortBy, filter.Descending
+		searchEntry.SetText("")
+		repSelect.SetSelected(allRepresentatives)
+		fromEntry.SetText("")
+		toEntry.SetText("")
+		statusSelect.SetSelected("All")
+		*filter = orderFilter{SortBy: sortBy, Descending: descending}
+		onChange()
+	})
+
+	setRepresentatives := func(representatives []internal.Representative) {
+		repSelect.Options = repNames(representatives)
+		repSelect.Refresh()
+	}
+
+	filters := container.NewGridWithColumns(4, repSelect, fromEntry, toEntry, statusSelect)
+	return container.NewVBox(
+		container.NewBorder(nil, nil, nil, clearBtn, searchEntry),
+		filters,
+	), setRepresentatives
+}
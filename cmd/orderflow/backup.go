@@ -0,0 +1,83 @@
+// cmd/orderflow/backup.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/backup"
+)
+
+func runBackup(database *sql.DB, action string, args []string, out io.Writer) error {
+	switch action {
+	case "create":
+		return createBackup(database, args, out)
+	case "auto":
+		return autoBackup(database, args, out)
+	case "restore":
+		return restoreBackup(database, args, out)
+	}
+	return errUsage
+}
+
+func createBackup(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("backup create")
+	path := fs.String("o", backup.FileName(time.Now()), "file to write the backup to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	archive, err := backup.Create(database)
+	if err != nil {
+		return err
+	}
+	if err := backup.WriteFile(*path, archive); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Backed up %d rows from %d tables to %s\n", archive.Rows(), len(archive.Tables), *path)
+	return nil
+}
+
+// autoBackup makes a backup into a directory and deletes the oldest ones, for
+// use from cron
+func autoBackup(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("backup auto")
+	dir := fs.String("dir", "", "directory to keep the backups in")
+	keep := fs.Int("keep", 14, "number of backups to keep, 0 keeps all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return fmt.Errorf("-dir is required")
+	}
+
+	path, err := backup.NewScheduler(database, *dir, 0, *keep).Backup()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Backed up to %s\n", path)
+	return nil
+}
+
+func restoreBackup(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("backup restore")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected the backup file to restore")
+	}
+
+	archive, err := backup.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := backup.Restore(database, archive); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Restored %d rows from %d tables, backed up %s\n",
+		archive.Rows(), len(archive.Tables), archive.CreatedAt.Format("2006-01-02 15:04"))
+	return nil
+}
@@ -0,0 +1,175 @@
+// cmd/orderflow/main.go
+
+// Command orderflow manages orders, products and representatives from the
+// command line using the same database as the desktop application.
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/webhooks"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+)
+
+const usage = `Usage: orderflow [config flags] <command> <action> [flags]
+
+Commands:
+  orders list        List orders (pending by default)
+  orders add         Create an order
+  orders complete    Mark orders as completed
+  orders export      Export orders to .xlsx, .csv, .json or .jsonl
+  orders ical        Export open orders as an iCalendar (.ics) feed
+  products list      List active products
+  products add       Add a product
+  products update    Change the name or price of a product
+  products deactivate  Deactivate products
+  reps list          List active representatives
+  reps add           Add a representative
+  reps deactivate    Deactivate representatives
+  serve              Serve the JSON API and calendar feed over HTTP
+  webhooks list      List webhook endpoints
+  webhooks deliver   Send queued webhook deliveries that are due
+  backup create      Back up every table to a file
+  backup auto        Back up into a directory, keeping the newest backups
+  backup restore     Restore a backup into an empty database
+  config show        Show the configuration and where each value came from
+
+Config flags:
+  -config path           Config file (ORDERFLOW_CONFIG)
+  -profile name          Database profile (ORDERFLOW_PROFILE)
+  -db-url url            Database URL (TURSO_DATABASE_URL)
+  -auth-token token      Database auth token (TURSO_AUTH_TOKEN)
+  -connect-timeout d     Time to wait for the database (ORDERFLOW_CONNECT_TIMEOUT)
+
+Run "orderflow <command> <action> -h" for the flags of an action.
+The database connection is read from the desktop application's configuration
+file, overridden by the environment variables, overridden by the flags.
+`
+
+// errUsage is returned when the arguments do not name a known action
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	overrides, args, err := parseConfigFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) || (err == nil && (len(args) == 0 || args[0] == "help")) {
+		fmt.Fprint(os.Stderr, usage)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orderflow: %v\n", err)
+		os.Exit(2)
+	}
+
+	config, err := db.LoadConfig(overrides)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orderflow: %v\n", err)
+		os.Exit(1)
+	}
+	if args[0] == "config" {
+		if err := runConfig(config, args[1:], os.Stdout); err != nil {
+			if errors.Is(err, errUsage) {
+				fmt.Fprint(os.Stderr, usage)
+				os.Exit(2)
+			}
+			fmt.Fprintf(os.Stderr, "orderflow: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "orderflow: database configuration invalid: %v\n", err)
+		os.Exit(1)
+	}
+	database, err := config.Open()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "orderflow: %v\n", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	// Queue webhooks for the changes made by this command
+	webhooks.Subscribe(internal.DefaultBus, database)
+
+	if err := run(database, args, os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "orderflow: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseConfigFlags reads the configuration flags given before the command
+// and returns the remaining arguments
+func parseConfigFlags(args []string) (db.Overrides, []string, error) {
+	var overrides db.Overrides
+	fs := flag.NewFlagSet("orderflow", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&overrides.ConfigPath, "config", "", "config file")
+	fs.StringVar(&overrides.Profile, "profile", "", "database profile")
+	fs.StringVar(&overrides.DatabaseURL, "db-url", "", "database URL")
+	fs.StringVar(&overrides.AuthToken, "auth-token", "", "database auth token")
+	fs.StringVar(&overrides.ConnectTimeout, "connect-timeout", "", "time to wait for the database")
+	if err := fs.Parse(args); err != nil {
+		return db.Overrides{}, nil, err
+	}
+	return overrides, fs.Args(), nil
+}
+
+// runConfig shows the resolved configuration without connecting
+func runConfig(config db.Config, args []string, out io.Writer) error {
+	if len(args) != 1 || args[0] != "show" {
+		return errUsage
+	}
+
+	fmt.Fprint(out, config.Describe())
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("configuration invalid: %w", err)
+	}
+	fmt.Fprintln(out, "Configuration is valid")
+	return nil
+}
+
+// run dispatches a command and writes its output to out
+func run(database *sql.DB, args []string, out io.Writer) error {
+	if len(args) > 0 && args[0] == "serve" {
+		return serve(database, args[1:], out)
+	}
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	command, action, args := args[0], args[1], args[2:]
+	switch command {
+	case "orders":
+		return runOrders(database, action, args, out)
+	case "products":
+		return runProducts(database, action, args, out)
+	case "reps", "representatives":
+		return runRepresentatives(database, action, args, out)
+	case "webhooks":
+		return runWebhooks(database, action, args, out)
+	case "backup":
+		return runBackup(database, action, args, out)
+	}
+	return errUsage
+}
+
+// newFlagSet creates the flag set of an action. Errors are returned rather than
+// exiting so that run can be tested.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("orderflow "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
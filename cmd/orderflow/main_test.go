@@ -0,0 +1,255 @@
+// cmd/orderflow/main_test.go
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/webhooks"
+	schema "github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	if err := schema.CreateSchema(db); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// runArgs runs a command line and returns its output
+func runArgs(t *testing.T, db *sql.DB, line string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := run(db, strings.Fields(line), &out); err != nil {
+		t.Fatalf("%q failed: %v", line, err)
+	}
+	return out.String()
+}
+
+func TestProductsAndRepresentatives(t *testing.T) {
+	db := setupTestDB(t)
+
+	runArgs(t, db, "products add -name Muffin -price 12.50")
+	runArgs(t, db, "products add -name Cake -price 80")
+	runArgs(t, db, "products update -id 1 -price 15")
+	runArgs(t, db, "products deactivate 2")
+	runArgs(t, db, "reps add -name Anna")
+
+	var products []productJSON
+	if err := json.Unmarshal([]byte(runArgs(t, db, "products list -json")), &products); err != nil {
+		t.Fatalf("Failed to decode products: %v", err)
+	}
+	if len(products) != 1 || products[0].Name != "Muffin" || products[0].Price != 15 {
+		t.Errorf("Unexpected products: %+v", products)
+	}
+
+	table := runArgs(t, db, "reps list")
+	if !strings.Contains(table, "NAME") || !strings.Contains(table, "Anna") {
+		t.Errorf("Unexpected representatives table:\n%s", table)
+	}
+}
+
+func TestOrders(t *testing.T) {
+	db := setupTestDB(t)
+	runArgs(t, db, "products add -name Muffin -price 12.50")
+	runArgs(t, db, "reps add -name Anna")
+
+	out := runArgs(t, db, "orders add -client Bob -contact 0821234567 -rep anna -due 2024-06-01 -item Muffin=4 -item 1=2")
+	if out != "Added order 1\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	var orders []struct {
+		ClientName string  `json:"client_name"`
+		Status     string  `json:"status"`
+		TotalPrice float64 `json:"total_price"`
+		Items      []struct {
+			Quantity int64 `json:"quantity"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(runArgs(t, db, "orders list -json")), &orders); err != nil {
+		t.Fatalf("Failed to decode orders: %v", err)
+	}
+	if len(orders) != 1 || orders[0].ClientName != "Bob" || orders[0].TotalPrice != 75 || len(orders[0].Items) != 2 {
+		t.Fatalf("Unexpected orders: %+v", orders)
+	}
+
+	if feed := runArgs(t, db, "orders ical"); !strings.HasPrefix(feed, "BEGIN:VCALENDAR") || !strings.Contains(feed, "Bob") {
+		t.Errorf("Expected an iCalendar feed with the order, got:\n%s", feed)
+	}
+
+	runArgs(t, db, "orders complete 1")
+	if table := runArgs(t, db, "orders list"); strings.Contains(table, "Bob") {
+		t.Errorf("Expected completed order to be hidden:\n%s", table)
+	}
+	if table := runArgs(t, db, "orders list -status completed"); !strings.Contains(table, "Bob") {
+		t.Errorf("Expected completed order to be listed:\n%s", table)
+	}
+
+	csv := runArgs(t, db, "orders export -o - -format csv")
+	if lines := strings.Split(strings.TrimSpace(csv), "\n"); len(lines) != 3 {
+		t.Errorf("Expected header and 2 item rows, got:\n%s", csv)
+	}
+
+	path := filepath.Join(t.TempDir(), "orders.jsonl")
+	runArgs(t, db, "orders export -status completed -o "+path)
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"client_name":"Bob"`) {
+		t.Errorf("Unexpected export file: %s (%v)", data, err)
+	}
+}
+
+func TestOrders_Capacity(t *testing.T) {
+	db := setupTestDB(t)
+	runArgs(t, db, "products add -name Muffin -price 12.50")
+	runArgs(t, db, "reps add -name Anna")
+	if err := internal.SaveCapacity(db, internal.Capacity{ItemsPerDay: 5}); err != nil {
+		t.Fatalf("SaveCapacity failed: %v", err)
+	}
+
+	out := runArgs(t, db, "orders add -client Bob -rep Anna -due 2024-06-03 -item Muffin=6")
+	if !strings.Contains(out, "Added order 1") || !strings.Contains(out, "Warning: the due day is over capacity") {
+		t.Errorf("Expected a capacity warning, got %q", out)
+	}
+
+	if err := internal.SaveCapacity(db, internal.Capacity{ItemsPerDay: 5, Block: true}); err != nil {
+		t.Fatalf("SaveCapacity failed: %v", err)
+	}
+	var buf bytes.Buffer
+	err := run(db, []string{"orders", "add", "-client", "Bob", "-rep", "Anna", "-due", "2024-06-03", "-item", "Muffin=1"}, &buf)
+	if !errors.Is(err, internal.ErrOverbooked) {
+		t.Errorf("Expected the order to be blocked, got %v", err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	db := setupTestDB(t)
+
+	var out bytes.Buffer
+	if err := run(db, []string{"orders"}, &out); !errors.Is(err, errUsage) {
+		t.Errorf("Expected usage error, got %v", err)
+	}
+	if err := run(db, []string{"orders", "add", "-client", "Bob", "-rep", "Nobody", "-due", "2024-06-01"}, &out); err == nil {
+		t.Error("Expected error for unknown representative")
+	}
+	// Sundays are closed by default
+	if err := run(db, []string{"orders", "add", "-client", "Bob", "-rep", "Anna", "-due", "2024-06-02", "-item", "Muffin=1"}, &out); err == nil {
+		t.Error("Expected error for a due date on a closed day")
+	}
+	if err := run(db, []string{"orders", "complete", "7"}, &out); err == nil {
+		t.Error("Expected error for unknown order")
+	}
+}
+
+func TestServe_RequiresToken(t *testing.T) {
+	t.Setenv("ORDERFLOW_API_TOKEN", "")
+	db := setupTestDB(t)
+
+	var out bytes.Buffer
+	if err := run(db, []string{"serve", "-addr", "127.0.0.1:0"}, &out); err == nil || !strings.Contains(err.Error(), "token") {
+		t.Errorf("Expected missing token error, got %v", err)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	db := setupTestDB(t)
+	unsubscribe := webhooks.Subscribe(internal.DefaultBus, db)
+	defer unsubscribe()
+
+	if _, err := webhooks.AddEndpoint(db, webhooks.Endpoint{URL: "http://127.0.0.1:1/hook",
+		Events: []string{webhooks.EventOrderCreated}}); err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	if table := runArgs(t, db, "webhooks list"); !strings.Contains(table, "order.created") {
+		t.Errorf("Unexpected webhooks table:\n%s", table)
+	}
+
+	runArgs(t, db, "products add -name Muffin -price 12.50")
+	runArgs(t, db, "reps add -name Anna")
+	runArgs(t, db, "orders add -client Bob -rep Anna -due 2024-06-01 -item Muffin=1")
+
+	if out := runArgs(t, db, "webhooks deliver"); out != "Delivered 0 webhooks\n" {
+		t.Errorf("Unexpected output: %q", out)
+	}
+	deliveries, err := webhooks.LoadDeliveries(db, 10)
+	if err != nil || len(deliveries) != 1 || deliveries[0].Attempts != 1 {
+		t.Errorf("Expected one failed attempt, got %+v (%v)", deliveries, err)
+	}
+}
+
+func TestConfigFlags(t *testing.T) {
+	for _, name := range []string{schema.EnvProfile, schema.EnvDatabaseURL, schema.EnvAuthToken, schema.EnvConnectTimeout} {
+		t.Setenv(name, "")
+	}
+	t.Setenv(schema.EnvConfigPath, filepath.Join(t.TempDir(), "config.json"))
+	t.Setenv(schema.EnvAuthToken, "env-token")
+
+	overrides, args, err := parseConfigFlags([]string{"-db-url", "libsql://flag.turso.io", "config", "show"})
+	if err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	if strings.Join(args, " ") != "config show" {
+		t.Errorf("Expected the command to remain, got %v", args)
+	}
+	config, err := schema.LoadConfig(overrides)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runConfig(config, args[1:], &out); err != nil {
+		t.Fatalf("config show failed: %v", err)
+	}
+	for _, want := range []string{"libsql://flag.turso.io", "flag -db-url", "environment variable TURSO_AUTH_TOKEN", "Configuration is valid"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "env-token") {
+		t.Errorf("Expected the token to be hidden:\n%s", out.String())
+	}
+
+	if _, _, err := parseConfigFlags([]string{"-unknown", "orders", "list"}); err == nil {
+		t.Error("Expected unknown flag to fail")
+	}
+}
+
+func TestBackup(t *testing.T) {
+	db := setupTestDB(t)
+	runArgs(t, db, "products add -name Muffin -price 12.50")
+
+	path := filepath.Join(t.TempDir(), "backup.json.gz")
+	if out := runArgs(t, db, "backup create -o "+path); !strings.Contains(out, "Backed up 2 rows") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+
+	var out bytes.Buffer
+	if err := run(db, []string{"backup", "restore", path}, &out); err == nil {
+		t.Error("Expected restore into a non-empty database to fail")
+	}
+
+	target, err := sql.Open("sqlite3", fmt.Sprintf("file:%s_target?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer target.Close()
+	runArgs(t, target, "backup restore "+path)
+	if out := runArgs(t, target, "products list"); !strings.Contains(out, "Muffin") {
+		t.Errorf("Expected restored product, got:\n%s", out)
+	}
+}
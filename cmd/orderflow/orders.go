@@ -0,0 +1,279 @@
+// cmd/orderflow/orders.go
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/export"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/ical"
+)
+
+func runOrders(database *sql.DB, action string, args []string, out io.Writer) error {
+	switch action {
+	case "list":
+		return listOrders(database, args, out)
+	case "add":
+		return addOrder(database, args, out)
+	case "complete":
+		return completeOrders(database, args, out)
+	case "export":
+		return exportOrders(database, args, out)
+	case "ical":
+		return exportCalendar(database, args, out)
+	}
+	return errUsage
+}
+
+// filterFlags are the order filters shared by list and export
+type filterFlags struct {
+	from, to, status string
+}
+
+func (f *filterFlags) register(fs *flag.FlagSet, defaultStatus string) {
+	fs.StringVar(&f.from, "from", "", "first order date, YYYY-MM-DD")
+	fs.StringVar(&f.to, "to", "", "last order date, YYYY-MM-DD")
+	fs.StringVar(&f.status, "status", defaultStatus, "pending, completed, cancelled or all")
+}
+
+// filter parses the flags with days starting in the time zone of the business
+func (f *filterFlags) filter(database *sql.DB) (export.Filter, error) {
+	hours, err := internal.LoadBusinessHours(database)
+	if err != nil {
+		return export.Filter{}, err
+	}
+	return export.ParseFilter(f.from, f.to, f.status, hours.Location())
+}
+
+func listOrders(database *sql.DB, args []string, out io.Writer) error {
+	var (
+		o       output
+		filters filterFlags
+	)
+	fs := newFlagSet("orders list")
+	o.register(fs)
+	filters.register(fs, internal.StatusPending)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := filters.filter(database)
+	if err != nil {
+		return err
+	}
+	rows, err := export.LoadRows(database, filter)
+	if err != nil {
+		return err
+	}
+
+	orders := export.Orders(rows)
+	var table [][]string
+	for _, order := range orders {
+		table = append(table, []string{
+			strconv.FormatInt(order.ID, 10),
+			order.CreatedAt.Format("2006-01-02 15:04"),
+			order.DueDate,
+			order.ClientName,
+			order.Contact,
+			order.Representative,
+			order.Status,
+			strconv.Itoa(len(order.Items)),
+			formatRand(order.TotalPrice),
+		})
+	}
+	return o.write(out, orders,
+		[]string{"ID", "DATE", "DUE", "CLIENT", "CONTACT", "REPRESENTATIVE", "STATUS", "ITEMS", "TOTAL"},
+		table)
+}
+
+// itemFlags collects repeated -item PRODUCT=QUANTITY flags
+type itemFlags []string
+
+func (i *itemFlags) String() string { return strings.Join(*i, ", ") }
+
+func (i *itemFlags) Set(value string) error {
+	*i = append(*i, value)
+	return nil
+}
+
+func addOrder(database *sql.DB, args []string, out io.Writer) error {
+	var items itemFlags
+	fs := newFlagSet("orders add")
+	client := fs.String("client", "", "client name (required)")
+	contact := fs.String("contact", "", "client contact details")
+	rep := fs.String("rep", "", "representative name or ID (required)")
+	due := fs.String("due", "", "due date, YYYY-MM-DD or YYYY-MM-DD HH:MM in the business time zone (required)")
+	comment := fs.String("comment", "", "order comment")
+	address := fs.String("deliver-to", "", "delivery address, if the order must be delivered")
+	fs.Var(&items, "item", "product name or ID and quantity, e.g. Muffin=12 (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(*client) == "" {
+		return fmt.Errorf("-client is required")
+	}
+	hours, err := internal.LoadBusinessHours(database)
+	if err != nil {
+		return err
+	}
+	dueDate, err := hours.ParseDue(*due)
+	if err != nil {
+		return err
+	}
+	if err := hours.Check(dueDate); err != nil {
+		return err
+	}
+	representative, err := findRepresentative(database, strings.TrimSpace(*rep))
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("at least one -item is required")
+	}
+
+	order := internal.Order{
+		DueDate:          dueDate,
+		ClientName:       strings.TrimSpace(*client),
+		Contact:          *contact,
+		RepresentativeID: representative.ID,
+		NeedsDelivery:    strings.TrimSpace(*address) != "",
+		DeliveryAddress:  strings.TrimSpace(*address),
+		Comment:          *comment,
+	}
+	for _, value := range items {
+		item, err := parseItem(database, value)
+		if err != nil {
+			return err
+		}
+		order.Items = append(order.Items, item)
+	}
+
+	check, err := internal.CheckCapacity(database, order)
+	if err != nil {
+		return err
+	}
+	if err := check.Err(); err != nil {
+		return err
+	}
+
+	id, err := internal.CreateOrder(database, order)
+	if err != nil {
+		return fmt.Errorf("error creating order: %w", err)
+	}
+	fmt.Fprintf(out, "Added order %d\n", id)
+	if !check.OK() {
+		fmt.Fprintf(out, "Warning: %s\n", check.Message())
+	}
+	return nil
+}
+
+// parseItem turns PRODUCT=QUANTITY into an order item priced at the current product price
+func parseItem(database *sql.DB, value string) (internal.OrderItem, error) {
+	key, qty, ok := strings.Cut(value, "=")
+	if !ok {
+		return internal.OrderItem{}, fmt.Errorf("invalid item %q, use PRODUCT=QUANTITY", value)
+	}
+	quantity, err := strconv.Atoi(strings.TrimSpace(qty))
+	if err != nil || quantity <= 0 {
+		return internal.OrderItem{}, fmt.Errorf("invalid quantity in item %q", value)
+	}
+
+	product, err := findProduct(database, strings.TrimSpace(key))
+	if err != nil {
+		return internal.OrderItem{}, err
+	}
+	return internal.OrderItem{
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		Quantity:    quantity,
+		Price:       product.Price * float64(quantity),
+	}, nil
+}
+
+func completeOrders(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("orders complete")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := internal.CompleteOrder(database, id); err != nil {
+			return fmt.Errorf("error completing order: %w", err)
+		}
+		fmt.Fprintf(out, "Completed order %d\n", id)
+	}
+	return nil
+}
+
+func exportOrders(database *sql.DB, args []string, out io.Writer) error {
+	var filters filterFlags
+	fs := newFlagSet("orders export")
+	path := fs.String("o", "", "output file; the format follows its extension (required)")
+	format := fs.String("format", "", "format when writing to standard output with -o -: csv, json, jsonl or xlsx")
+	filters.register(fs, "all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter, err := filters.filter(database)
+	if err != nil {
+		return err
+	}
+
+	switch *path {
+	case "":
+		return fmt.Errorf("-o is required")
+	case "-":
+		exporter, err := export.ForPath("stdout." + strings.TrimPrefix(*format, "."))
+		if err != nil {
+			return err
+		}
+		rows, err := export.LoadRows(database, filter)
+		if err != nil {
+			return err
+		}
+		return exporter.Export(out, rows)
+	}
+
+	if err := export.ExportFile(database, *path, filter); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Exported orders to %s\n", *path)
+	return nil
+}
+
+func exportCalendar(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("orders ical")
+	path := fs.String("o", "-", "output file, - for standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *path == "-" {
+		return ical.WriteFeed(database, out)
+	}
+	file, err := os.Create(*path)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	if err := ical.WriteFeed(database, file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Exported open orders to %s\n", *path)
+	return nil
+}
@@ -0,0 +1,41 @@
+// cmd/orderflow/output.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// output writes results either as an aligned table or as JSON
+type output struct {
+	json bool
+}
+
+// register adds the -json flag to fs
+func (o *output) register(fs *flag.FlagSet) {
+	fs.BoolVar(&o.json, "json", false, "print JSON instead of a table")
+}
+
+// write prints rows under headers, or value as JSON when -json is set
+func (o *output) write(out io.Writer, value interface{}, headers []string, rows [][]string) error {
+	if o.json {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatRand(amount float64) string {
+	return fmt.Sprintf("R%.2f", amount)
+}
@@ -0,0 +1,157 @@
+// cmd/orderflow/products.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+type productJSON struct {
+	ID    int64   `json:"id"`
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+func runProducts(database *sql.DB, action string, args []string, out io.Writer) error {
+	switch action {
+	case "list":
+		return listProducts(database, args, out)
+	case "add":
+		return addProduct(database, args, out)
+	case "update":
+		return updateProduct(database, args, out)
+	case "deactivate":
+		return deactivateProducts(database, args, out)
+	}
+	return errUsage
+}
+
+func listProducts(database *sql.DB, args []string, out io.Writer) error {
+	var o output
+	fs := newFlagSet("products list")
+	o.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	products, err := internal.LoadProducts(database)
+	if err != nil {
+		return fmt.Errorf("error loading products: %w", err)
+	}
+
+	values := []productJSON{}
+	var rows [][]string
+	for _, p := range products {
+		values = append(values, productJSON{ID: p.ID, Name: p.Name, Price: p.Price})
+		rows = append(rows, []string{strconv.FormatInt(p.ID, 10), p.Name, formatRand(p.Price)})
+	}
+	return o.write(out, values, []string{"ID", "NAME", "PRICE"}, rows)
+}
+
+func addProduct(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("products add")
+	name := fs.String("name", "", "product name (required)")
+	price := fs.Float64("price", 0, "unit price (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(*name) == "" {
+		return fmt.Errorf("-name is required")
+	}
+	if *price <= 0 {
+		return fmt.Errorf("-price must be greater than zero")
+	}
+
+	id, err := internal.AddProduct(database, strings.TrimSpace(*name), *price)
+	if err != nil {
+		return fmt.Errorf("error adding product: %w", err)
+	}
+	fmt.Fprintf(out, "Added product %d\n", id)
+	return nil
+}
+
+func updateProduct(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("products update")
+	id := fs.Int64("id", 0, "product ID (required)")
+	name := fs.String("name", "", "new product name")
+	price := fs.Float64("price", 0, "new unit price")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	product, err := findProduct(database, strconv.FormatInt(*id, 10))
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*name) != "" {
+		product.Name = strings.TrimSpace(*name)
+	}
+	if *price < 0 {
+		return fmt.Errorf("-price must not be negative")
+	}
+	if *price > 0 {
+		product.Price = *price
+	}
+
+	if err := internal.UpdateProduct(database, product); err != nil {
+		return fmt.Errorf("error updating product: %w", err)
+	}
+	fmt.Fprintf(out, "Updated product %d\n", product.ID)
+	return nil
+}
+
+func deactivateProducts(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("products deactivate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := internal.DeactivateProduct(database, id); err != nil {
+			return fmt.Errorf("error deactivating product: %w", err)
+		}
+		fmt.Fprintf(out, "Deactivated product %d\n", id)
+	}
+	return nil
+}
+
+// findProduct looks up an active product by ID or case-insensitive name
+func findProduct(database *sql.DB, key string) (internal.Product, error) {
+	products, err := internal.LoadProducts(database)
+	if err != nil {
+		return internal.Product{}, fmt.Errorf("error loading products: %w", err)
+	}
+	for _, p := range products {
+		if strconv.FormatInt(p.ID, 10) == key || strings.EqualFold(p.Name, key) {
+			return p, nil
+		}
+	}
+	return internal.Product{}, fmt.Errorf("no active product %q", key)
+}
+
+// parseIDs converts positional arguments to IDs, requiring at least one
+func parseIDs(args []string) ([]int64, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("at least one ID is required")
+	}
+
+	var ids []int64
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
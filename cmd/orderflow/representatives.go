@@ -0,0 +1,103 @@
+// cmd/orderflow/representatives.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+type representativeJSON struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func runRepresentatives(database *sql.DB, action string, args []string, out io.Writer) error {
+	switch action {
+	case "list":
+		return listRepresentatives(database, args, out)
+	case "add":
+		return addRepresentative(database, args, out)
+	case "deactivate":
+		return deactivateRepresentatives(database, args, out)
+	}
+	return errUsage
+}
+
+func listRepresentatives(database *sql.DB, args []string, out io.Writer) error {
+	var o output
+	fs := newFlagSet("reps list")
+	o.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	representatives, err := internal.LoadRepresentatives(database)
+	if err != nil {
+		return fmt.Errorf("error loading representatives: %w", err)
+	}
+
+	values := []representativeJSON{}
+	var rows [][]string
+	for _, r := range representatives {
+		values = append(values, representativeJSON{ID: r.ID, Name: r.Name})
+		rows = append(rows, []string{strconv.FormatInt(r.ID, 10), r.Name})
+	}
+	return o.write(out, values, []string{"ID", "NAME"}, rows)
+}
+
+func addRepresentative(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("reps add")
+	name := fs.String("name", "", "representative name (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(*name) == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	id, err := internal.AddRepresentative(database, strings.TrimSpace(*name))
+	if err != nil {
+		return fmt.Errorf("error adding representative: %w", err)
+	}
+	fmt.Fprintf(out, "Added representative %d\n", id)
+	return nil
+}
+
+func deactivateRepresentatives(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("reps deactivate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := internal.DeactivateRepresentative(database, id); err != nil {
+			return fmt.Errorf("error deactivating representative: %w", err)
+		}
+		fmt.Fprintf(out, "Deactivated representative %d\n", id)
+	}
+	return nil
+}
+
+// findRepresentative looks up an active representative by ID or case-insensitive name
+func findRepresentative(database *sql.DB, key string) (internal.Representative, error) {
+	representatives, err := internal.LoadRepresentatives(database)
+	if err != nil {
+		return internal.Representative{}, fmt.Errorf("error loading representatives: %w", err)
+	}
+	for _, r := range representatives {
+		if strconv.FormatInt(r.ID, 10) == key || strings.EqualFold(r.Name, key) {
+			return r, nil
+		}
+	}
+	return internal.Representative{}, fmt.Errorf("no active representative %q", key)
+}
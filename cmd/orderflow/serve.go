@@ -0,0 +1,47 @@
+// cmd/orderflow/serve.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/api"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/webhooks"
+)
+
+func serve(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("serve")
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on")
+	token := fs.String("token", os.Getenv("ORDERFLOW_API_TOKEN"),
+		"token clients must send as \"Authorization: Bearer <token>\" (default $ORDERFLOW_API_TOKEN)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	server, err := api.New(database, *token)
+	if err != nil {
+		return fmt.Errorf("%w; set -token or ORDERFLOW_API_TOKEN", err)
+	}
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           server,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Changes made through the API are audited as the API user
+	internal.SetAuditUser("api")
+
+	// Send queued webhook deliveries while serving
+	go webhooks.NewDispatcher(database).Run(context.Background(), 30*time.Second)
+
+	fmt.Fprintf(out, "Serving the API on http://%s/api (description at /api/openapi.json)\n", *addr)
+	fmt.Fprintf(out, "Calendar apps can subscribe to http://%s/api/calendar.ics?token=<token>\n", *addr)
+	return httpServer.ListenAndServe()
+}
@@ -0,0 +1,69 @@
+// cmd/orderflow/webhooks.go
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/webhooks"
+)
+
+type endpointJSON struct {
+	ID     int64    `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+}
+
+func runWebhooks(database *sql.DB, action string, args []string, out io.Writer) error {
+	switch action {
+	case "list":
+		return listWebhooks(database, args, out)
+	case "deliver":
+		return deliverWebhooks(database, args, out)
+	}
+	return errUsage
+}
+
+func listWebhooks(database *sql.DB, args []string, out io.Writer) error {
+	var o output
+	fs := newFlagSet("webhooks list")
+	o.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	endpoints, err := webhooks.LoadEndpoints(database)
+	if err != nil {
+		return err
+	}
+
+	values := []endpointJSON{}
+	var rows [][]string
+	for _, e := range endpoints {
+		values = append(values, endpointJSON{ID: e.ID, URL: e.URL, Events: e.Events, Active: e.Active})
+		rows = append(rows, []string{strconv.FormatInt(e.ID, 10), e.URL,
+			strings.Join(e.Events, ","), strconv.FormatBool(e.Active)})
+	}
+	return o.write(out, values, []string{"ID", "URL", "EVENTS", "ACTIVE"}, rows)
+}
+
+// deliverWebhooks sends due deliveries once, for use from cron when the
+// desktop application and API server are not running
+func deliverWebhooks(database *sql.DB, args []string, out io.Writer) error {
+	fs := newFlagSet("webhooks deliver")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	delivered, err := webhooks.NewDispatcher(database).DeliverDue(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Delivered %d webhooks\n", delivered)
+	return nil
+}
@@ -0,0 +1,311 @@
+// cmd/profiles.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// session is the database connection the window currently works on. Opening
+// another profile closes it, so the connection can be switched without
+// restarting the application.
+type session struct {
+	window  fyne.Window
+	profile string
+	db      *sql.DB
+	// stop ends the background work of the main app started for db
+	stop func()
+}
+
+func newSession(window fyne.Window) *session {
+	s := &session{window: window}
+	window.SetOnClosed(s.close)
+	return s
+}
+
+// open connects to a profile and asks the user to log in to it. An empty
+// name opens the active profile, whose connection the environment overrides
+// as it does for the command line. A named profile was chosen by the user, so
+// its own connection is used. The current connection is kept when the new one
+// fails.
+func (s *session) open(name string) {
+	config, err := db.LoadConfig(db.Overrides{Profile: name})
+	if err == nil {
+		err = config.Validate()
+	}
+	var database *sql.DB
+	if err == nil {
+		database, err = config.Open()
+	}
+	if err != nil {
+		if name == "" {
+			name = "the database"
+		}
+		dialog.ShowError(fmt.Errorf("error opening %s: %w", name, err), s.window)
+		if s.db == nil {
+			// Nothing to fall back to, let the user pick another profile.
+			// Profiles are only managed after logging in, since they hold
+			// the credentials of every database.
+			showProfilePicker(s)
+		}
+		return
+	}
+
+	name = config.Profile
+	if name != "" {
+		if err := db.SetActiveProfile(name); err != nil {
+			log.Printf("Warning: Could not save active profile: %v", err)
+		}
+	}
+	s.close()
+	s.db = database
+	s.profile = name
+	s.window.SetTitle(windowTitle(name))
+
+	// Accounts belong to a database, so every switch needs a new login
+	s.window.SetMainMenu(nil)
+	showLogin(s.window, database, func(user internal.User) {
+		s.stop = initializeMainApp(s.window, database, user, s)
+	})
+}
+
+// windowTitle names the profile in use, if any
+func windowTitle(profile string) string {
+	if profile == "" {
+		return "OrderFlow Manager"
+	}
+	return "OrderFlow Manager - " + profile
+}
+
+// close stops the main app and closes the connection
+func (s *session) close() {
+	if s.stop != nil {
+		s.stop()
+		s.stop = nil
+	}
+	if s.db != nil {
+		s.db.Close()
+		s.db = nil
+	}
+}
+
+// showProfilePicker asks which profile to open, starting on the active one
+func showProfilePicker(s *session) {
+	names, active, err := db.ListProfiles()
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+
+	profileSelect := widget.NewSelect(names, nil)
+	profileSelect.SetSelected(active)
+
+	form := widget.NewForm(widget.NewFormItem("Database", profileSelect))
+	form.SubmitText = "Open"
+	form.OnSubmit = func() {
+		if profileSelect.Selected != "" {
+			s.open(profileSelect.Selected)
+		}
+	}
+
+	box := container.NewVBox(
+		widget.NewLabelWithStyle("Choose a database", fyne.TextAlignCenter, fyne.TextStyle{Bold: true}),
+		form,
+	)
+	s.window.SetContent(container.NewCenter(container.NewGridWrap(fyne.NewSize(400, 200), box)))
+	s.window.Resize(fyne.NewSize(1024, 768))
+}
+
+// showSwitchDatabaseDialog opens another profile from the running app
+func showSwitchDatabaseDialog(s *session) {
+	names, _, err := db.ListProfiles()
+	if err != nil {
+		dialog.ShowError(err, s.window)
+		return
+	}
+
+	profileSelect := widget.NewSelect(names, nil)
+	profileSelect.SetSelected(s.profile)
+
+	items := []*widget.FormItem{widget.NewFormItem("Database", profileSelect)}
+	dialog.ShowForm("Switch Database", "Switch", "Cancel", items, func(submitted bool) {
+		if !submitted || profileSelect.Selected == "" || profileSelect.Selected == s.profile {
+			return
+		}
+		s.open(profileSelect.Selected)
+	}, s.window)
+}
+
+// showDatabaseProfilesDialog lists the saved connection profiles
+func showDatabaseProfilesDialog(window fyne.Window, s *session) {
+	names, active, err := db.ListProfiles()
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	var profilesDialog dialog.Dialog
+	reopen := func() {
+		profilesDialog.Hide()
+		showDatabaseProfilesDialog(window, s)
+	}
+
+	list := widget.NewList(
+		func() int { return len(names) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButton("Open", func() {}),
+					widget.NewButton("Edit", func() {}),
+					widget.NewButton("Restore...", func() {}),
+					widget.NewButton("Delete", func() {}),
+				),
+				widget.NewLabel("Template"),
+			)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			box := item.(*fyne.Container)
+			label := box.Objects[0].(*widget.Label)
+			buttons := box.Objects[1].(*fyne.Container)
+			openBtn := buttons.Objects[0].(*widget.Button)
+			editBtn := buttons.Objects[1].(*widget.Button)
+			restoreBtn := buttons.Objects[2].(*widget.Button)
+			deleteBtn := buttons.Objects[3].(*widget.Button)
+
+			name := names[id]
+			text := name
+			if name == s.profile {
+				text += " (connected)"
+			} else if name == active && s.db == nil {
+				text += " (last used)"
+			}
+			label.SetText(text)
+
+			openBtn.OnTapped = func() {
+				profilesDialog.Hide()
+				s.open(name)
+			}
+			// The connected database is in use, so it is never empty
+			if name == s.profile {
+				openBtn.Disable()
+				restoreBtn.Disable()
+				deleteBtn.Disable()
+			} else {
+				openBtn.Enable()
+				restoreBtn.Enable()
+				deleteBtn.Enable()
+			}
+			restoreBtn.OnTapped = func() {
+				showRestoreBackupDialog(window, name)
+			}
+			editBtn.OnTapped = func() {
+				showProfileDialog(window, s, name, reopen)
+			}
+			deleteBtn.OnTapped = func() {
+				dialog.ShowConfirm("Delete Profile",
+					fmt.Sprintf("Delete the profile %q? The database itself is not changed.", name),
+					func(confirmed bool) {
+						if !confirmed {
+							return
+						}
+						if err := db.DeleteProfile(name); err != nil {
+							dialog.ShowError(err, window)
+							return
+						}
+						reopen()
+					}, window)
+			}
+		},
+	)
+
+	addBtn := widget.NewButton("Add Profile", func() {
+		showProfileDialog(window, s, "", reopen)
+	})
+
+	profilesDialog = dialog.NewCustom("Database Profiles", "Close", container.NewBorder(nil, addBtn, nil, nil, list), window)
+	profilesDialog.Resize(fyne.NewSize(700, 400))
+	profilesDialog.Show()
+}
+
+// showProfileDialog adds a profile, or edits the named one. Changes to the
+// connected profile take effect when it is opened again.
+func showProfileDialog(window fyne.Window, s *session, name string, onSaved func()) {
+	var existing db.DatabaseConfig
+	if name != "" {
+		var err error
+		if existing, err = db.LoadProfile(name); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+	}
+
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("e.g. Shop, Market Stall, Test")
+	nameEntry.SetText(name)
+
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("Turso Database URL")
+	urlEntry.SetText(existing.DatabaseURL)
+
+	// The saved token is never shown, an empty entry keeps it
+	tokenEntry := widget.NewPasswordEntry()
+	tokenEntry.SetPlaceHolder("Turso Auth Token")
+	if name != "" {
+		tokenEntry.SetPlaceHolder("Leave empty to keep the saved token")
+	}
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Database URL", urlEntry),
+		widget.NewFormItem("Auth Token", tokenEntry),
+	}
+
+	title := "Add Profile"
+	if name != "" {
+		title = "Edit " + name
+	}
+	formDialog := dialog.NewForm(title, "Save", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+
+		if name != "" && nameEntry.Text != name {
+			if err := db.RenameProfile(name, nameEntry.Text); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if name == s.profile {
+				s.profile = strings.TrimSpace(nameEntry.Text)
+				window.SetTitle(windowTitle(s.profile))
+			}
+		}
+		token := tokenEntry.Text
+		if token == "" {
+			token = existing.AuthToken
+		}
+		err := db.SaveProfile(db.Profile{
+			Name: nameEntry.Text,
+			DatabaseConfig: db.DatabaseConfig{
+				DatabaseURL: urlEntry.Text,
+				AuthToken:   token,
+			},
+		})
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		onSaved()
+	}, window)
+	formDialog.Resize(fyne.NewSize(600, 300))
+	formDialog.Show()
+}
@@ -0,0 +1,228 @@
+// cmd/reports.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/reports"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+var reportRanges = []string{"This Week", "This Month", "Last Month", "This Year", "Custom"}
+
+var reportGranularities = map[string]reports.Granularity{
+	"Day":   reports.Daily,
+	"Week":  reports.Weekly,
+	"Month": reports.Monthly,
+}
+
+func showSalesReportDialog(window fyne.Window, db *sql.DB) {
+	rangeSelect := widget.NewSelect(reportRanges, nil)
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("From (YYYY-MM-DD)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("To (YYYY-MM-DD)")
+	customRange := container.NewGridWithColumns(2, fromEntry, toEntry)
+	customRange.Hide()
+
+	rangeSelect.OnChanged = func(selected string) {
+		if selected == "Custom" {
+			customRange.Show()
+		} else {
+			customRange.Hide()
+		}
+	}
+	rangeSelect.SetSelected("This Month")
+
+	granularitySelect := widget.NewSelect([]string{"Day", "Week", "Month"}, nil)
+	granularitySelect.SetSelected("Day")
+
+	var report reports.Report
+	summaryLabel := widget.NewLabel("")
+	tabs := container.NewAppTabs()
+
+	runReport := func() {
+		r, err := selectedReportRange(rangeSelect.Selected, fromEntry.Text, toEntry.Text,
+			loadBusinessHours(db).Location())
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		report, err = reports.Build(db, r, reportGranularities[granularitySelect.Selected])
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		summaryLabel.SetText(formatReportSummary(report))
+		tabs.SetItems([]*container.TabItem{
+			container.NewTabItem("By "+report.Granularity.String(),
+				newReportTable(linesToRows(report.Periods))),
+			container.NewTabItem("By Product",
+				newReportTable(comparisonsToRows("Product", report.Products))),
+			container.NewTabItem("By Representative",
+				newReportTable(comparisonsToRows("Representative", report.Representatives))),
+			container.NewTabItem("By Customer",
+				newReportTable(comparisonsToRows("Customer", report.Customers))),
+		})
+	}
+
+	runBtn := widget.NewButton("Run Report", runReport)
+
+	exportBtn := widget.NewButton("Export to Excel", func() {
+		saveDialog := dialog.NewFileSave(
+			func(writer fyne.URIWriteCloser, err error) {
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				if writer == nil {
+					return // user cancelled
+				}
+				defer writer.Close()
+
+				path := writer.URI().Path()
+				if !strings.HasSuffix(strings.ToLower(path), ".xlsx") {
+					path += ".xlsx"
+				}
+
+				if err := reports.ExportToExcel(report, path); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+
+				dialog.ShowInformation("Success",
+					"Report has been exported successfully to:\n"+path,
+					window)
+			},
+			window)
+
+		saveDialog.SetFileName(fmt.Sprintf("sales_report_%s.xlsx",
+			report.Range.From.Format("2006-01-02")))
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".xlsx"}))
+		saveDialog.Show()
+	})
+
+	controls := container.NewVBox(
+		container.NewGridWithColumns(2, rangeSelect, granularitySelect),
+		customRange,
+		container.NewHBox(runBtn, exportBtn),
+		summaryLabel,
+	)
+
+	content := container.NewBorder(controls, nil, nil, nil, tabs)
+
+	runReport()
+
+	reportDialog := dialog.NewCustom("Sales Report", "Close", content, window)
+	reportDialog.Resize(fyne.NewSize(900, 600))
+	reportDialog.Show()
+}
+
+// selectedReportRange returns the chosen range with days starting in loc
+func selectedReportRange(selected, from, to string, loc *time.Location) (reports.Range, error) {
+	now := time.Now().In(loc)
+	switch selected {
+	case "This Week":
+		return reports.ThisWeek(now), nil
+	case "Last Month":
+		return reports.LastMonth(now), nil
+	case "This Year":
+		return reports.ThisYear(now), nil
+	case "Custom":
+		fromDate, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return reports.Range{}, fmt.Errorf("Invalid start date format. Please use YYYY-MM-DD")
+		}
+		toDate, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return reports.Range{}, fmt.Errorf("Invalid end date format. Please use YYYY-MM-DD")
+		}
+		if toDate.Before(fromDate) {
+			return reports.Range{}, fmt.Errorf("End date must not be before start date")
+		}
+		return reports.Days(fromDate, toDate), nil
+	default:
+		return reports.ThisMonth(now), nil
+	}
+}
+
+func formatReportSummary(report reports.Report) string {
+	total := report.Total
+	summary := fmt.Sprintf("%s: %d orders, R%.2f (previous period %s: %d orders, R%.2f)",
+		report.Range, total.Current.Orders, total.Current.Revenue,
+		report.Previous, total.Previous.Orders, total.Previous.Revenue)
+	if pct, ok := total.ChangePercent(); ok {
+		summary += fmt.Sprintf(", %+.1f%%", pct)
+	}
+	return summary
+}
+
+func linesToRows(lines []reports.Line) [][]string {
+	rows := [][]string{{"Period", "Orders", "Quantity", "Revenue"}}
+	for _, l := range lines {
+		rows = append(rows, []string{
+			l.Label,
+			fmt.Sprintf("%d", l.Orders),
+			fmt.Sprintf("%d", l.Quantity),
+			fmt.Sprintf("R%.2f", l.Revenue),
+		})
+	}
+	return rows
+}
+
+func comparisonsToRows(label string, comparisons []reports.Comparison) [][]string {
+	rows := [][]string{{label, "Orders", "Quantity", "Revenue", "Previous", "Change"}}
+	for _, c := range comparisons {
+		change := fmt.Sprintf("R%.2f", c.Change())
+		if pct, ok := c.ChangePercent(); ok {
+			change += fmt.Sprintf(" (%+.1f%%)", pct)
+		}
+		rows = append(rows, []string{
+			c.Label,
+			fmt.Sprintf("%d", c.Current.Orders),
+			fmt.Sprintf("%d", c.Current.Quantity),
+			fmt.Sprintf("R%.2f", c.Current.Revenue),
+			fmt.Sprintf("R%.2f", c.Previous.Revenue),
+			change,
+		})
+	}
+	return rows
+}
+
+// newReportTable shows rows of text with the first row as header
+func newReportTable(rows [][]string) *widget.Table {
+	table := widget.NewTable(
+		func() (int, int) {
+			if len(rows) == 0 {
+				return 0, 0
+			}
+			return len(rows), len(rows[0])
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			label.SetText(rows[id.Row][id.Col])
+		},
+	)
+
+	table.SetColumnWidth(0, 220)
+	for col := 1; col < 5; col++ {
+		table.SetColumnWidth(col, 120)
+	}
+	table.SetColumnWidth(5, 180)
+	return table
+}
@@ -0,0 +1,222 @@
+// cmd/users.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showManageUsersDialog lists the user accounts with their roles
+func showManageUsersDialog(window fyne.Window, db *sql.DB) {
+	users, err := internal.LoadUsers(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	representatives, err := internal.LoadRepresentatives(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	var usersDialog dialog.Dialog
+	reopen := func() {
+		usersDialog.Hide()
+		showManageUsersDialog(window, db)
+	}
+
+	list := widget.NewList(
+		func() int { return len(users) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButton("Edit", func() {}),
+					widget.NewButton("Reset Password", func() {}),
+				),
+				widget.NewLabel("Template"),
+			)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			box := item.(*fyne.Container)
+			label := box.Objects[0].(*widget.Label)
+			buttons := box.Objects[1].(*fyne.Container)
+			editBtn := buttons.Objects[0].(*widget.Button)
+			resetBtn := buttons.Objects[1].(*widget.Button)
+
+			user := users[id]
+			text := fmt.Sprintf("%s (%s)", user.Username, user.Role)
+			if user.RepresentativeID != 0 {
+				text += " - " + representativeName(representatives, user.RepresentativeID)
+			}
+			if !user.Active {
+				text += " - inactive"
+			}
+			label.SetText(text)
+
+			editBtn.OnTapped = func() {
+				showEditUserDialog(window, db, user, representatives, reopen)
+			}
+			resetBtn.OnTapped = func() {
+				showResetPasswordDialog(window, db, user)
+			}
+		},
+	)
+
+	addBtn := widget.NewButton("Add User", func() {
+		showAddUserDialog(window, db, representatives, reopen)
+	})
+
+	usersDialog = dialog.NewCustom("Users", "Close", container.NewBorder(nil, addBtn, nil, nil, list), window)
+	usersDialog.Resize(fyne.NewSize(600, 400))
+	usersDialog.Show()
+}
+
+// newRoleFields returns the role and representative selects of the user
+// forms. The representative is only asked for representative accounts.
+func newRoleFields(representatives []internal.Representative, user internal.User) (*widget.Select, *widget.Select) {
+	var repNames []string
+	for _, r := range representatives {
+		repNames = append(repNames, r.Name)
+	}
+	repSelect := widget.NewSelect(repNames, nil)
+	repSelect.PlaceHolder = "Select rep"
+	if user.RepresentativeID != 0 {
+		repSelect.SetSelected(representativeName(representatives, user.RepresentativeID))
+	}
+
+	var roles []string
+	for _, role := range internal.Roles {
+		roles = append(roles, string(role))
+	}
+	roleSelect := widget.NewSelect(roles, func(selected string) {
+		if internal.Role(selected) == internal.RoleRepresentative {
+			repSelect.Enable()
+		} else {
+			repSelect.Disable()
+		}
+	})
+	roleSelect.SetSelected(string(user.Role))
+	if user.Role == "" {
+		roleSelect.SetSelected(string(internal.RoleRepresentative))
+	}
+	return roleSelect, repSelect
+}
+
+// selectedRepresentative returns the ID of the representative chosen for a
+// representative account, or zero for other roles
+func selectedRepresentative(representatives []internal.Representative, roleSelect, repSelect *widget.Select) int64 {
+	if internal.Role(roleSelect.Selected) != internal.RoleRepresentative {
+		return 0
+	}
+	for _, r := range representatives {
+		if r.Name == repSelect.Selected {
+			return r.ID
+		}
+	}
+	return 0
+}
+
+func representativeName(representatives []internal.Representative, id int64) string {
+	for _, r := range representatives {
+		if r.ID == id {
+			return r.Name
+		}
+	}
+	return fmt.Sprintf("representative %d", id)
+}
+
+func showAddUserDialog(window fyne.Window, db *sql.DB, representatives []internal.Representative, onSaved func()) {
+	usernameEntry := widget.NewEntry()
+	passwordEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+	roleSelect, repSelect := newRoleFields(representatives, internal.User{})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Username", usernameEntry),
+		widget.NewFormItem("Password", passwordEntry),
+		widget.NewFormItem("Confirm Password", confirmEntry),
+		widget.NewFormItem("Role", roleSelect),
+		widget.NewFormItem("Representative", repSelect),
+	}
+
+	dialog.ShowForm("Add User", "Add", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			dialog.ShowError(fmt.Errorf("Passwords do not match"), window)
+			return
+		}
+
+		_, err := internal.CreateUser(db, internal.User{
+			Username:         usernameEntry.Text,
+			Role:             internal.Role(roleSelect.Selected),
+			RepresentativeID: selectedRepresentative(representatives, roleSelect, repSelect),
+		}, passwordEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		onSaved()
+	}, window)
+}
+
+func showEditUserDialog(window fyne.Window, db *sql.DB, user internal.User,
+	representatives []internal.Representative, onSaved func()) {
+	roleSelect, repSelect := newRoleFields(representatives, user)
+	activeCheck := widget.NewCheck("Can log in", nil)
+	activeCheck.SetChecked(user.Active)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Role", roleSelect),
+		widget.NewFormItem("Representative", repSelect),
+		widget.NewFormItem("Active", activeCheck),
+	}
+
+	dialog.ShowForm("Edit "+user.Username, "Save", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+
+		user.Role = internal.Role(roleSelect.Selected)
+		user.RepresentativeID = selectedRepresentative(representatives, roleSelect, repSelect)
+		user.Active = activeCheck.Checked
+		if err := internal.UpdateUser(db, user); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		onSaved()
+	}, window)
+}
+
+func showResetPasswordDialog(window fyne.Window, db *sql.DB, user internal.User) {
+	passwordEntry := widget.NewPasswordEntry()
+	confirmEntry := widget.NewPasswordEntry()
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("New Password", passwordEntry),
+		widget.NewFormItem("Confirm Password", confirmEntry),
+	}
+
+	dialog.ShowForm("Reset Password for "+user.Username, "Save", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+		if passwordEntry.Text != confirmEntry.Text {
+			dialog.ShowError(fmt.Errorf("Passwords do not match"), window)
+			return
+		}
+		if err := internal.SetPassword(db, user.ID, passwordEntry.Text); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Success", "Password has been reset", window)
+	}, window)
+}
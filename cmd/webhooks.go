@@ -0,0 +1,188 @@
+// cmd/webhooks.go
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/webhooks"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showWebhooksDialog manages webhook endpoints and shows the delivery log
+func showWebhooksDialog(window fyne.Window, db *sql.DB) {
+	endpoints, err := webhooks.LoadEndpoints(db)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	deliveries, err := webhooks.LoadDeliveries(db, 200)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+
+	var webhooksDialog dialog.Dialog
+	reopen := func() {
+		webhooksDialog.Hide()
+		showWebhooksDialog(window, db)
+	}
+
+	endpointList := widget.NewList(
+		func() int { return len(endpoints) },
+		func() fyne.CanvasObject {
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(
+					widget.NewButton("Disable", func() {}),
+					widget.NewButton("Secret", func() {}),
+					widget.NewButton("Delete", func() {}),
+				),
+				widget.NewLabel("Template"),
+			)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			box := item.(*fyne.Container)
+			label := box.Objects[0].(*widget.Label)
+			buttons := box.Objects[1].(*fyne.Container)
+			toggleBtn := buttons.Objects[0].(*widget.Button)
+			secretBtn := buttons.Objects[1].(*widget.Button)
+			deleteBtn := buttons.Objects[2].(*widget.Button)
+
+			endpoint := endpoints[id]
+			state := "active"
+			toggleBtn.SetText("Disable")
+			if !endpoint.Active {
+				state = "disabled"
+				toggleBtn.SetText("Enable")
+			}
+			label.SetText(fmt.Sprintf("%s (%s) - %s", endpoint.URL, state, strings.Join(endpoint.Events, ", ")))
+
+			toggleBtn.OnTapped = func() {
+				endpoint.Active = !endpoint.Active
+				if err := webhooks.UpdateEndpoint(db, endpoint); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				reopen()
+			}
+			secretBtn.OnTapped = func() {
+				secretEntry := widget.NewEntry()
+				secretEntry.SetText(endpoint.Secret)
+				dialog.ShowCustom("Signing Secret", "Close", container.NewVBox(
+					widget.NewLabel("Receivers verify the "+webhooks.HeaderSignature+" header, the HMAC-SHA256\n"+
+						"of \"<"+webhooks.HeaderTimestamp+">.<body>\" keyed with this secret."),
+					secretEntry,
+				), window)
+			}
+			deleteBtn.OnTapped = func() {
+				dialog.ShowConfirm("Delete Webhook",
+					"Delete this endpoint and its delivery log?",
+					func(confirm bool) {
+						if !confirm {
+							return
+						}
+						if err := webhooks.DeleteEndpoint(db, endpoint.ID); err != nil {
+							dialog.ShowError(err, window)
+							return
+						}
+						reopen()
+					},
+					window,
+				)
+			}
+		},
+	)
+
+	addBtn := widget.NewButton("Add Endpoint", func() {
+		showAddWebhookDialog(window, db, reopen)
+	})
+
+	rows := [][]string{{"Time", "Event", "Endpoint", "Status", "Attempts", "Response", "Error"}}
+	failed := 0
+	for _, d := range deliveries {
+		response := ""
+		if d.LastStatusCode != 0 {
+			response = strconv.Itoa(d.LastStatusCode)
+		}
+		if d.Status == webhooks.StatusFailed {
+			failed++
+		}
+		rows = append(rows, []string{
+			d.CreatedAt.Format("2006-01-02 15:04:05"),
+			d.Event,
+			d.EndpointURL,
+			d.Status,
+			strconv.Itoa(d.Attempts),
+			response,
+			d.LastError,
+		})
+	}
+	logTable := newReportTable(rows)
+	logTable.SetColumnWidth(0, 160)
+	logTable.SetColumnWidth(2, 240)
+	logTable.SetColumnWidth(6, 300)
+
+	retryBtn := widget.NewButton(fmt.Sprintf("Retry Failed (%d)", failed), func() {
+		for _, d := range deliveries {
+			if d.Status != webhooks.StatusFailed {
+				continue
+			}
+			if err := webhooks.Retry(db, d.ID); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+		}
+		reopen()
+	})
+	if failed == 0 {
+		retryBtn.Disable()
+	}
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Endpoints", container.NewBorder(nil, addBtn, nil, nil, endpointList)),
+		container.NewTabItem("Delivery Log", container.NewBorder(nil,
+			container.NewHBox(widget.NewButton("Refresh", reopen), retryBtn), nil, nil, logTable)),
+	)
+
+	webhooksDialog = dialog.NewCustom("Webhooks", "Close", tabs, window)
+	webhooksDialog.Resize(fyne.NewSize(900, 500))
+	webhooksDialog.Show()
+}
+
+func showAddWebhookDialog(window fyne.Window, db *sql.DB, onAdded func()) {
+	urlEntry := widget.NewEntry()
+	urlEntry.SetPlaceHolder("https://example.com/orderflow")
+
+	eventsCheck := widget.NewCheckGroup(webhooks.Events, nil)
+	eventsCheck.SetSelected(webhooks.Events)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("URL", urlEntry),
+		widget.NewFormItem("Events", eventsCheck),
+	}
+
+	dialog.ShowForm("Add Webhook Endpoint", "Add", "Cancel", items, func(submitted bool) {
+		if !submitted {
+			return
+		}
+
+		endpoint, err := webhooks.AddEndpoint(db, webhooks.Endpoint{
+			URL:    urlEntry.Text,
+			Events: eventsCheck.Selected,
+		})
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+
+		onAdded()
+		dialog.ShowInformation("Webhook Added",
+			"Deliveries are signed with this secret:\n"+endpoint.Secret, window)
+	}, window)
+}
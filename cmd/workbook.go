@@ -0,0 +1,132 @@
+// cmd/workbook.go
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/workbook"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showExportWorkbookDialog saves the structured workbook that can later be applied back
+func showExportWorkbookDialog(window fyne.Window, db *sql.DB) {
+	saveDialog := dialog.NewFileSave(
+		func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			if writer == nil {
+				return // user cancelled
+			}
+			defer writer.Close()
+
+			path := writer.URI().Path()
+			if !strings.HasSuffix(strings.ToLower(path), ".xlsx") {
+				path += ".xlsx"
+			}
+
+			if err := workbook.Export(db, path); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+
+			dialog.ShowInformation("Success",
+				"Workbook has been exported successfully to:\n"+path+
+					"\n\nEdit it and use Data > Apply Workbook Changes to update the database.",
+				window)
+		},
+		window)
+
+	saveDialog.SetFileName(fmt.Sprintf("orderflow_%s.xlsx", time.Now().Format("2006-01-02")))
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".xlsx"}))
+	saveDialog.Show()
+}
+
+// showApplyWorkbookDialog applies an edited structured workbook to the database
+func showApplyWorkbookDialog(window fyne.Window, db *sql.DB) {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if reader == nil {
+			return // user cancelled
+		}
+		reader.Close()
+		path := reader.URI().Path()
+
+		dialog.ShowConfirm("Apply Workbook Changes",
+			"Changes made in this workbook will overwrite the current data. Continue?",
+			func(confirm bool) {
+				if !confirm {
+					return
+				}
+
+				result, err := workbook.Apply(db, path)
+				var validation *workbook.ValidationError
+				if errors.As(err, &validation) {
+					showWorkbookErrors(window, validation.Errors)
+					return
+				}
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+
+				dialog.ShowInformation("Workbook Applied", formatWorkbookResult(result), window)
+			},
+			window,
+		)
+	}, window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".xlsx"}))
+	openDialog.Show()
+}
+
+func showWorkbookErrors(window fyne.Window, cellErrors []workbook.CellError) {
+	list := widget.NewList(
+		func() int { return len(cellErrors) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(cellErrors[id].Error())
+		},
+	)
+
+	errorDialog := dialog.NewCustom(
+		fmt.Sprintf("Nothing was changed: %d errors in workbook", len(cellErrors)),
+		"Close", list, window)
+	errorDialog.Resize(fyne.NewSize(700, 400))
+	errorDialog.Show()
+}
+
+func formatWorkbookResult(result workbook.Result) string {
+	lines := []string{}
+	for _, entry := range []struct {
+		name   string
+		counts workbook.Counts
+	}{
+		{"Products", result.Products},
+		{"Representatives", result.Representatives},
+		{"Orders", result.Orders},
+		{"Order items", result.Items},
+	} {
+		c := entry.counts
+		if c == (workbook.Counts{}) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %d added, %d updated, %d deleted",
+			entry.name, c.Added, c.Updated, c.Deleted))
+	}
+	if len(lines) == 0 {
+		return "No changes found in workbook"
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,342 @@
+// internal/api/api_test.go
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+)
+
+const testToken = "secret-token"
+
+func setupTestServer(t *testing.T) (*Server, *sql.DB) {
+	database, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := db.CreateSchema(database); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	_, err = database.Exec(`
+		INSERT INTO products (name, price, active) VALUES ('Muffin', 12.5, true), ('Old Cake', 30, false);
+		INSERT INTO representatives (name, active) VALUES ('Anna', true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	server, err := New(database, testToken)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server, database
+}
+
+// do sends an authenticated request and decodes the JSON response into v
+func do(t *testing.T, server *Server, method, path, body string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: failed to decode %q: %v", method, path, rec.Body.String(), err)
+		}
+	}
+	return rec
+}
+
+func TestNew_RequiresToken(t *testing.T) {
+	if _, err := New(nil, " "); err == nil {
+		t.Error("Expected error for empty token")
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		req := httptest.NewRequest(http.MethodGet, "/api/products", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: expected 401, got %d", header, rec.Code)
+		}
+	}
+
+	// The description is public
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	var spec map[string]interface{}
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &spec) != nil || spec["openapi"] == nil {
+		t.Errorf("Expected OpenAPI description, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestCreateAndCompleteOrder(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	var created Order
+	rec := do(t, server, http.MethodPost, "/api/orders", `{
+		"client_name": "Bob",
+		"contact": "0821234567",
+		"representative_id": 1,
+		"due_date": "2024-06-01",
+		"items": [{"product_id": 1, "quantity": 4}]
+	}`, &created)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if created.ID != 1 || created.TotalPrice != 50 || created.Representative != "Anna" || len(created.Items) != 1 {
+		t.Errorf("Unexpected created order: %+v", created)
+	}
+	if rec.Header().Get("Location") != "/api/orders/1" {
+		t.Errorf("Unexpected Location header %q", rec.Header().Get("Location"))
+	}
+
+	var items []OrderItem
+	do(t, server, http.MethodGet, "/api/orders/1/items", "", &items)
+	if len(items) != 1 || items[0].Product != "Muffin" || items[0].Quantity != 4 {
+		t.Errorf("Unexpected items: %+v", items)
+	}
+
+	var completed Order
+	rec = do(t, server, http.MethodPost, "/api/orders/1/complete", "", &completed)
+	if rec.Code != http.StatusOK || !completed.Completed {
+		t.Errorf("Expected completed order, got %d %+v", rec.Code, completed)
+	}
+
+	var page struct {
+		Data  []Order `json:"data"`
+		Total int     `json:"total"`
+	}
+	do(t, server, http.MethodGet, "/api/orders", "", &page)
+	if page.Total != 0 {
+		t.Errorf("Expected no pending orders, got %d", page.Total)
+	}
+	do(t, server, http.MethodGet, "/api/orders?status=completed", "", &page)
+	if page.Total != 1 || page.Data[0].ClientName != "Bob" {
+		t.Errorf("Expected the completed order, got %+v", page)
+	}
+
+	rec = do(t, server, http.MethodGet, "/api/orders/99", "", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown order, got %d", rec.Code)
+	}
+}
+
+func TestListOrders_Cancelled(t *testing.T) {
+	server, database := setupTestServer(t)
+	for _, client := range []string{"Alice", "Bob"} {
+		rec := do(t, server, http.MethodPost, "/api/orders", `{"client_name": "`+client+`",
+			"representative_id": 1, "due_date": "2024-06-03", "items": [{"product_id": 1, "quantity": 1}]}`, nil)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	if err := internal.CancelOrders(database, []int64{1}); err != nil {
+		t.Fatalf("CancelOrders failed: %v", err)
+	}
+
+	var page struct {
+		Data  []Order `json:"data"`
+		Total int     `json:"total"`
+	}
+	do(t, server, http.MethodGet, "/api/orders", "", &page)
+	if page.Total != 1 || page.Data[0].ClientName != "Bob" || page.Data[0].Status != "pending" {
+		t.Errorf("Expected only Bob to be pending, got %+v", page.Data)
+	}
+	do(t, server, http.MethodGet, "/api/orders?status=cancelled", "", &page)
+	if page.Total != 1 || page.Data[0].ClientName != "Alice" || page.Data[0].Status != "cancelled" || page.Data[0].Completed {
+		t.Errorf("Expected Alice to be cancelled, got %+v", page.Data)
+	}
+	do(t, server, http.MethodGet, "/api/orders?status=all", "", &page)
+	if page.Total != 2 {
+		t.Errorf("Expected both orders, got %+v", page.Data)
+	}
+	do(t, server, http.MethodGet, "/api/orders?status=all&page=2&per_page=1", "", &page)
+	if page.Total != 2 || len(page.Data) != 1 || page.Data[0].ClientName != "Alice" || len(page.Data[0].Items) != 1 {
+		t.Errorf("Expected Alice on the second page, got %+v", page)
+	}
+	if rec := do(t, server, http.MethodGet, "/api/orders?status=open", "", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown status, got %d", rec.Code)
+	}
+}
+
+func TestCreateOrder_Validation(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	var resp ErrorResponse
+	rec := do(t, server, http.MethodPost, "/api/orders", `{
+		"representative_id": 5,
+		"due_date": "01/06/2024",
+		"needs_delivery": true,
+		"items": [{"product_id": 2, "quantity": 0}]
+	}`, &resp)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, field := range []string{"client_name", "due_date", "representative_id",
+		"delivery_address", "items[0].quantity", "items[0].product_id"} {
+		if resp.Fields[field] == "" {
+			t.Errorf("Expected validation error for %s, got %v", field, resp.Fields)
+		}
+	}
+
+	rec = do(t, server, http.MethodPost, "/api/orders", `{"client": "Bob"}`, nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown field, got %d", rec.Code)
+	}
+}
+
+func TestCreateOrder_DueTime(t *testing.T) {
+	server, database := setupTestServer(t)
+	hours := internal.DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	if err := internal.SaveBusinessHours(database, hours); err != nil {
+		t.Fatalf("Failed to save business hours: %v", err)
+	}
+
+	newOrder := func(due string) string {
+		return fmt.Sprintf(`{"client_name": "Bob", "representative_id": 1, "due_date": %q,
+			"items": [{"product_id": 1, "quantity": 1}]}`, due)
+	}
+
+	var created Order
+	rec := do(t, server, http.MethodPost, "/api/orders", newOrder("2024-06-10T07:30:00Z"), &created)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if created.DueDate != "2024-06-10" || created.DueAt == nil ||
+		!created.DueAt.Equal(time.Date(2024, 6, 10, 7, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected due time: %s %v", created.DueDate, created.DueAt)
+	}
+
+	// Sundays are closed and Mondays close at 17:00
+	for _, due := range []string{"2024-06-09", "2024-06-10 17:30"} {
+		var resp ErrorResponse
+		rec := do(t, server, http.MethodPost, "/api/orders", newOrder(due), &resp)
+		if rec.Code != http.StatusUnprocessableEntity || resp.Fields["due_date"] == "" {
+			t.Errorf("%s: expected a due date error, got %d %+v", due, rec.Code, resp)
+		}
+	}
+}
+
+func TestCreateOrder_Capacity(t *testing.T) {
+	server, database := setupTestServer(t)
+	if err := internal.SaveCapacity(database, internal.Capacity{ItemsPerDay: 5, Block: true}); err != nil {
+		t.Fatalf("Failed to save capacity: %v", err)
+	}
+
+	body := `{"client_name": "Bob", "representative_id": 1, "due_date": "2024-06-03",
+		"items": [{"product_id": 1, "quantity": 4}]}`
+	if rec := do(t, server, http.MethodPost, "/api/orders", body, nil); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ErrorResponse
+	rec := do(t, server, http.MethodPost, "/api/orders", body, &resp)
+	if rec.Code != http.StatusUnprocessableEntity || !strings.Contains(resp.Fields["due_date"], "Days with room: Tue 2024-06-04") {
+		t.Errorf("Expected the day to be full, got %d %+v", rec.Code, resp)
+	}
+}
+
+func TestCalendarFeed(t *testing.T) {
+	server, _ := setupTestServer(t)
+	do(t, server, http.MethodPost, "/api/orders", `{"client_name": "Bob", "representative_id": 1,
+		"due_date": "2024-06-01", "items": [{"product_id": 1, "quantity": 4}]}`, nil)
+
+	// Calendar apps pass the token in the URL
+	for _, query := range []string{"", "?token=wrong"} {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/calendar.ics"+query, nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%q: expected 401, got %d", query, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/calendar.ics?token="+testToken, nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+		t.Fatalf("Expected the feed, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "SUMMARY:Order 1 for Bob") ||
+		!strings.Contains(rec.Body.String(), "DTSTART;VALUE=DATE:20240601") {
+		t.Errorf("Unexpected feed:\n%s", rec.Body.String())
+	}
+
+	// The header works too
+	if rec := do(t, server, http.MethodGet, "/api/calendar.ics", "", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with the Authorization header, got %d", rec.Code)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	server, database := setupTestServer(t)
+	for i := 0; i < 4; i++ {
+		if _, err := database.Exec("INSERT INTO representatives (name, active) VALUES (?, true)",
+			fmt.Sprintf("Rep %d", i)); err != nil {
+			t.Fatalf("Failed to insert representative: %v", err)
+		}
+	}
+
+	var page struct {
+		Data    []Representative `json:"data"`
+		Page    int              `json:"page"`
+		PerPage int              `json:"per_page"`
+		Total   int              `json:"total"`
+	}
+	do(t, server, http.MethodGet, "/api/representatives?page=2&per_page=2", "", &page)
+	if page.Total != 5 || page.Page != 2 || page.PerPage != 2 || len(page.Data) != 2 {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+	do(t, server, http.MethodGet, "/api/representatives?page=9", "", &page)
+	if len(page.Data) != 0 {
+		t.Errorf("Expected empty page, got %+v", page.Data)
+	}
+
+	for _, query := range []string{"page=0", "per_page=500", "page=x"} {
+		if rec := do(t, server, http.MethodGet, "/api/products?"+query, "", nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rec.Code)
+		}
+	}
+}
+
+func TestCreateProductAndRepresentative(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	var product Product
+	rec := do(t, server, http.MethodPost, "/api/products", `{"name": " Scone ", "price": 9.5}`, &product)
+	if rec.Code != http.StatusCreated || product.Name != "Scone" || !product.Active {
+		t.Errorf("Unexpected product response %d %+v", rec.Code, product)
+	}
+
+	var resp ErrorResponse
+	rec = do(t, server, http.MethodPost, "/api/products", `{"name": "", "price": -1}`, &resp)
+	if rec.Code != http.StatusUnprocessableEntity || resp.Fields["name"] == "" || resp.Fields["price"] == "" {
+		t.Errorf("Expected validation errors, got %d %+v", rec.Code, resp)
+	}
+
+	var rep Representative
+	rec = do(t, server, http.MethodPost, "/api/representatives", `{"name": "Ben"}`, &rep)
+	if rec.Code != http.StatusCreated || rep.ID != 2 {
+		t.Errorf("Unexpected representative response %d %+v", rec.Code, rep)
+	}
+}
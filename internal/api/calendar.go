@@ -0,0 +1,23 @@
+// internal/api/calendar.go
+package api
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/ical"
+)
+
+// handleCalendar serves the open orders as an iCalendar feed that calendar
+// apps can subscribe to
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	// Write to a buffer first, so that an error is not sent as half a feed
+	var buf bytes.Buffer
+	if err := ical.WriteFeed(s.db, &buf); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="orders.ics"`)
+	w.Write(buf.Bytes())
+}
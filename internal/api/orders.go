@@ -0,0 +1,309 @@
+// internal/api/orders.go
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Order is the JSON representation of an order
+type Order struct {
+	ID               int64       `json:"id"`
+	CreatedAt        time.Time   `json:"created_at"`
+	DueDate          string      `json:"due_date"`
+	DueAt            *time.Time  `json:"due_at,omitempty"`
+	ClientName       string      `json:"client_name"`
+	Contact          string      `json:"contact"`
+	RepresentativeID int64       `json:"representative_id"`
+	Representative   string      `json:"representative"`
+	NeedsDelivery    bool        `json:"needs_delivery"`
+	DeliveryAddress  string      `json:"delivery_address"`
+	Comment          string      `json:"comment"`
+	Completed        bool        `json:"completed"`
+	Status           string      `json:"status"`
+	TotalPrice       float64     `json:"total_price"`
+	Items            []OrderItem `json:"items"`
+}
+
+// OrderItem is the JSON representation of an order item
+type OrderItem struct {
+	ID        int64   `json:"id"`
+	ProductID int64   `json:"product_id"`
+	Product   string  `json:"product"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// NewOrder is the request body for creating an order
+type NewOrder struct {
+	ClientName       string         `json:"client_name"`
+	Contact          string         `json:"contact"`
+	RepresentativeID int64          `json:"representative_id"`
+	DueDate          string         `json:"due_date"`
+	NeedsDelivery    bool           `json:"needs_delivery"`
+	DeliveryAddress  string         `json:"delivery_address"`
+	Comment          string         `json:"comment"`
+	Items            []NewOrderItem `json:"items"`
+}
+
+// NewOrderItem is an item of NewOrder. The price is taken from the product.
+type NewOrderItem struct {
+	ProductID int64 `json:"product_id"`
+	Quantity  int   `json:"quantity"`
+}
+
+func orderJSON(o internal.Order) Order {
+	items := []OrderItem{}
+	for _, item := range o.Items {
+		items = append(items, orderItemJSON(item))
+	}
+	var dueAt *time.Time
+	if internal.HasDueTime(o.DueDate) {
+		dueAt = &o.DueDate
+	}
+	return Order{
+		ID:               o.ID,
+		CreatedAt:        o.CreatedAt,
+		DueDate:          o.DueDate.Format("2006-01-02"),
+		DueAt:            dueAt,
+		ClientName:       o.ClientName,
+		Contact:          o.Contact,
+		RepresentativeID: o.RepresentativeID,
+		Representative:   o.RepresentativeName,
+		NeedsDelivery:    o.NeedsDelivery,
+		DeliveryAddress:  o.DeliveryAddress,
+		Comment:          o.Comment,
+		Completed:        o.Completed,
+		Status:           strings.ToLower(o.Status()),
+		TotalPrice:       o.TotalPrice,
+		Items:            items,
+	}
+}
+
+func orderItemJSON(item internal.OrderItem) OrderItem {
+	return OrderItem{
+		ID:        item.ID,
+		ProductID: item.ProductID,
+		Product:   item.ProductName,
+		Quantity:  item.Quantity,
+		Price:     item.Price,
+	}
+}
+
+// orderStatuses maps the status query parameter to order statuses
+var orderStatuses = map[string]string{
+	"":          internal.StatusPending,
+	"pending":   internal.StatusPending,
+	"completed": internal.StatusCompleted,
+	"cancelled": internal.StatusCancelled,
+	"all":       "",
+}
+
+// handleListOrders lists orders filtered by
+// ?status=pending|completed|cancelled|all, pending by default. Only the
+// requested page is loaded.
+func (s *Server) handleListOrders(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, ok := orderStatuses[r.URL.Query().Get("status")]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "status must be pending, completed, cancelled or all")
+		return
+	}
+	orders, total, err := internal.LoadOrderPage(s.db, status, perPage, (page-1)*perPage)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	result := []Order{}
+	for _, o := range orders {
+		result = append(result, orderJSON(o))
+	}
+	writeJSON(w, http.StatusOK, Page{Data: result, Page: page, PerPage: perPage, Total: total})
+}
+
+func (s *Server) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := internal.LoadOrder(s.db, id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orderJSON(order))
+}
+
+func (s *Server) handleListOrderItems(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	order, err := internal.LoadOrder(s.db, id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orderJSON(order).Items)
+}
+
+func (s *Server) handleCreateOrder(w http.ResponseWriter, r *http.Request) {
+	var body NewOrder
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products, err := internal.LoadProducts(s.db)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	representatives, err := internal.LoadRepresentatives(s.db)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	hours, err := internal.LoadBusinessHours(s.db)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	order, v := validateNewOrder(body, products, representatives, hours)
+	if v.write(w) {
+		return
+	}
+
+	// Orders that do not fit the capacity of their due day are only rejected
+	// when overbooking is blocked
+	check, err := internal.CheckCapacity(s.db, order)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	if check.Err() != nil {
+		v.check(false, "due_date", check.Message())
+		v.write(w)
+		return
+	}
+
+	id, err := internal.CreateOrder(s.db, order)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	created, err := internal.LoadOrder(s.db, id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/orders/%d", id))
+	writeJSON(w, http.StatusCreated, orderJSON(created))
+}
+
+// validateNewOrder checks the body against the active products,
+// representatives and business hours and builds the order to insert
+func validateNewOrder(body NewOrder, products []internal.Product, representatives []internal.Representative,
+	hours internal.BusinessHours) (internal.Order, validation) {
+	v := validation{}
+
+	v.check(strings.TrimSpace(body.ClientName) != "", "client_name", "is required")
+
+	dueDate, err := parseDue(body.DueDate, hours)
+	v.check(err == nil, "due_date", "must be a date in YYYY-MM-DD form, or a time in RFC 3339 or YYYY-MM-DD HH:MM form")
+	if err == nil {
+		if err := hours.Check(dueDate); err != nil {
+			v.check(false, "due_date", err.Error())
+		}
+	}
+
+	var repFound bool
+	for _, rep := range representatives {
+		repFound = repFound || rep.ID == body.RepresentativeID
+	}
+	v.check(repFound, "representative_id", "must be the ID of an active representative")
+
+	v.check(!body.NeedsDelivery || strings.TrimSpace(body.DeliveryAddress) != "",
+		"delivery_address", "is required when needs_delivery is true")
+	v.check(len(body.Items) > 0, "items", "must contain at least one item")
+
+	order := internal.Order{
+		DueDate:          dueDate,
+		ClientName:       strings.TrimSpace(body.ClientName),
+		Contact:          strings.TrimSpace(body.Contact),
+		RepresentativeID: body.RepresentativeID,
+		NeedsDelivery:    body.NeedsDelivery,
+		DeliveryAddress:  strings.TrimSpace(body.DeliveryAddress),
+		Comment:          body.Comment,
+	}
+
+	for i, item := range body.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		v.check(item.Quantity > 0, field+".quantity", "must be greater than zero")
+
+		var product *internal.Product
+		for j := range products {
+			if products[j].ID == item.ProductID {
+				product = &products[j]
+			}
+		}
+		v.check(product != nil, field+".product_id", "must be the ID of an active product")
+		if product == nil {
+			continue
+		}
+
+		order.Items = append(order.Items, internal.OrderItem{
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			Price:       product.Price * float64(item.Quantity),
+		})
+	}
+	return order, v
+}
+
+// parseDue accepts a due time with its offset in RFC 3339 form, or what
+// BusinessHours.ParseDue accepts
+func parseDue(text string, hours internal.BusinessHours) (time.Time, error) {
+	if due, err := time.Parse(time.RFC3339, text); err == nil {
+		return due, nil
+	}
+	return hours.ParseDue(text)
+}
+
+func (s *Server) handleCompleteOrder(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := internal.CompleteOrder(s.db, id); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	order, err := internal.LoadOrder(s.db, id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orderJSON(order))
+}
@@ -0,0 +1,114 @@
+// internal/api/products.go
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Product is the JSON representation of a product, also used to create one
+type Product struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Price  float64 `json:"price"`
+	Active bool    `json:"active"`
+}
+
+// Representative is the JSON representation of a representative, also used to
+// create one
+type Representative struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+}
+
+func (s *Server) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	products, err := internal.LoadProducts(s.db)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	result := []Product{}
+	for _, p := range products {
+		result = append(result, Product{ID: p.ID, Name: p.Name, Price: p.Price, Active: p.Active})
+	}
+	writeJSON(w, http.StatusOK, paginate(result, page, perPage))
+}
+
+func (s *Server) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
+	var body Product
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	v := validation{}
+	v.check(body.ID == 0, "id", "must not be set")
+	v.check(strings.TrimSpace(body.Name) != "", "name", "is required")
+	v.check(body.Price > 0, "price", "must be greater than zero")
+	if v.write(w) {
+		return
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	id, err := internal.AddProduct(s.db, body.Name, body.Price)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, Product{ID: id, Name: body.Name, Price: body.Price, Active: true})
+}
+
+func (s *Server) handleListRepresentatives(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	representatives, err := internal.LoadRepresentatives(s.db)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	result := []Representative{}
+	for _, rep := range representatives {
+		result = append(result, Representative{ID: rep.ID, Name: rep.Name, Active: rep.Active})
+	}
+	writeJSON(w, http.StatusOK, paginate(result, page, perPage))
+}
+
+func (s *Server) handleCreateRepresentative(w http.ResponseWriter, r *http.Request) {
+	var body Representative
+	if err := decode(w, r, &body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	v := validation{}
+	v.check(body.ID == 0, "id", "must not be set")
+	v.check(strings.TrimSpace(body.Name) != "", "name", "is required")
+	if v.write(w) {
+		return
+	}
+
+	body.Name = strings.TrimSpace(body.Name)
+	id, err := internal.AddRepresentative(s.db, body.Name)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, Representative{ID: id, Name: body.Name, Active: true})
+}
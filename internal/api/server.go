@@ -0,0 +1,203 @@
+// internal/api/server.go
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Pagination limits for list endpoints
+const (
+	DefaultPerPage = 50
+	MaxPerPage     = 200
+)
+
+// Server serves the JSON API and the calendar feed. Every endpoint except the
+// OpenAPI description requires an "Authorization: Bearer <token>" header; the
+// calendar feed also accepts the token as the token query parameter.
+type Server struct {
+	db    *sql.DB
+	token string
+	mux   *http.ServeMux
+}
+
+// New creates a server for db that accepts requests carrying token
+func New(db *sql.DB, token string) (*Server, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("an API token is required")
+	}
+
+	s := &Server{db: db, token: token, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /api/openapi.json", s.handleOpenAPI)
+
+	s.handle("GET /api/orders", s.handleListOrders)
+	s.handle("POST /api/orders", s.handleCreateOrder)
+	s.handle("GET /api/orders/{id}", s.handleGetOrder)
+	s.handle("GET /api/orders/{id}/items", s.handleListOrderItems)
+	s.handle("POST /api/orders/{id}/complete", s.handleCompleteOrder)
+
+	s.handle("GET /api/products", s.handleListProducts)
+	s.handle("POST /api/products", s.handleCreateProduct)
+
+	s.handle("GET /api/representatives", s.handleListRepresentatives)
+	s.handle("POST /api/representatives", s.handleCreateRepresentative)
+
+	// Calendar apps cannot send headers, so the feed also accepts the token
+	// as a query parameter
+	s.mux.HandleFunc("GET /api/calendar.ics", s.authorize(s.handleCalendar, true))
+
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle registers an endpoint that requires the API token
+func (s *Server) handle(pattern string, handler http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, s.authorize(handler, false))
+}
+
+// authorize requires the API token in the Authorization header or, when
+// inQuery is set, in the token query parameter
+func (s *Server) authorize(handler http.HandlerFunc, inQuery bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok && inQuery {
+			token, ok = r.URL.Query().Get("token"), true
+		}
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="orderflow"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid API token")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// Page is the envelope of list responses
+type Page struct {
+	Data    interface{} `json:"data"`
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
+	Total   int         `json:"total"`
+}
+
+// ErrorResponse is the body of every error response. Fields holds validation
+// messages keyed by the name of the invalid field.
+type ErrorResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// pagination reads the page and per_page query parameters
+func pagination(r *http.Request) (page, perPage int, err error) {
+	page, perPage = 1, DefaultPerPage
+
+	if value := r.URL.Query().Get("page"); value != "" {
+		page, err = strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive number")
+		}
+	}
+	if value := r.URL.Query().Get("per_page"); value != "" {
+		perPage, err = strconv.Atoi(value)
+		if err != nil || perPage < 1 || perPage > MaxPerPage {
+			return 0, 0, fmt.Errorf("per_page must be between 1 and %d", MaxPerPage)
+		}
+	}
+	return page, perPage, nil
+}
+
+// paginate slices items for the requested page
+func paginate[T any](items []T, page, perPage int) Page {
+	start := (page - 1) * perPage
+	if start > len(items) {
+		start = len(items)
+	}
+	end := start + perPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return Page{Data: items[start:end], Page: page, PerPage: perPage, Total: len(items)}
+}
+
+// pathID reads the numeric {id} path parameter
+func pathID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id < 1 {
+		return 0, fmt.Errorf("invalid ID %q", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// decode reads a JSON request body, rejecting unknown fields
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing API response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
+}
+
+// writeStoreError maps errors from the internal package to responses
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, internal.ErrNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	log.Printf("API error: %v", err)
+	writeError(w, http.StatusInternalServerError, "internal server error")
+}
+
+// validation collects field errors of a request body
+type validation map[string]string
+
+func (v validation) check(ok bool, field, message string) {
+	if !ok {
+		if _, exists := v[field]; !exists {
+			v[field] = message
+		}
+	}
+}
+
+// write sends the collected errors and reports whether there were any
+func (v validation) write(w http.ResponseWriter) bool {
+	if len(v) == 0 {
+		return false
+	}
+	writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: "validation failed", Fields: v})
+	return true
+}
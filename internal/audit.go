@@ -0,0 +1,257 @@
+// internal/audit.go
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Audited entities
+const (
+	EntityOrder          = "order"
+	EntityOrderItem      = "order_item"
+	EntityPayment        = "payment"
+	EntityNote           = "note"
+	EntityProduct        = "product"
+	EntityRepresentative = "representative"
+	EntityUser           = "user"
+	EntitySetting        = "setting"
+	EntityWebhook        = "webhook"
+)
+
+// AuditEntities lists the entities that can be filtered on in the audit log
+var AuditEntities = []string{
+	EntityOrder, EntityOrderItem, EntityPayment, EntityNote,
+	EntityProduct, EntityRepresentative, EntityUser, EntitySetting,
+	EntityWebhook,
+}
+
+// Audited actions
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionComplete = "complete"
+	ActionCancel   = "cancel"
+	ActionDelete   = "delete"
+)
+
+// AuditEntry is a row of the append-only audit_log table. Before and After
+// hold the JSON of the record around the change, empty when it did not exist.
+type AuditEntry struct {
+	ID        int64
+	Entity    string
+	EntityID  int64
+	Action    string
+	Before    string
+	After     string
+	User      string
+	CreatedAt time.Time
+}
+
+var (
+	auditMu   sync.RWMutex
+	auditUser string
+)
+
+// SetAuditUser sets the user recorded for the changes made from now on
+func SetAuditUser(name string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditUser = name
+}
+
+// AuditUser returns the user recorded in the audit log, which defaults to the
+// operating system user
+func AuditUser() string {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+	if auditUser != "" {
+		return auditUser
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// RecordAudit appends an audit entry within tx, so it is only kept when the
+// change itself is committed. before and after are stored as JSON; nil is
+// stored as NULL.
+func RecordAudit(tx *sql.Tx, entity string, id int64, action string, before, after interface{}) error {
+	beforeJSON, err := auditJSON(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditJSON(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO audit_log (entity, entity_id, action, before_json, after_json, user, created_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entity, id, action, beforeJSON, afterJSON, AuditUser(), time.Now())
+	if err != nil {
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+	return nil
+}
+
+func auditJSON(v interface{}) (sql.NullString, error) {
+	if v == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("error encoding audit log: %w", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// AuditFilter selects audit entries. Zero fields match everything; From is
+// inclusive and To exclusive.
+type AuditFilter struct {
+	Entity   string
+	EntityID int64
+	User     string
+	From     time.Time
+	To       time.Time
+
+	// Limit is the most entries to return, all when zero, after skipping
+	// the newest Offset
+	Limit  int
+	Offset int
+}
+
+// where returns the SQL condition of the filter and its arguments
+func (f AuditFilter) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if f.Entity != "" {
+		conditions = append(conditions, "entity = ?")
+		args = append(args, f.Entity)
+	}
+	if f.EntityID != 0 {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, f.EntityID)
+	}
+	if f.User != "" {
+		conditions = append(conditions, "user = ?")
+		args = append(args, f.User)
+	}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "datetime(created_at) >= ?")
+		args = append(args, SQLTime(f.From))
+	}
+	if !f.To.IsZero() {
+		conditions = append(conditions, "datetime(created_at) < ?")
+		args = append(args, SQLTime(f.To))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// LoadAuditLog returns the audit entries selected by filter, newest first
+func LoadAuditLog(db *sql.DB, filter AuditFilter) ([]AuditEntry, error) {
+	where, args := filter.where()
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := db.Query(`
+        SELECT id, entity, entity_id, action, before_json, after_json, user, created_at
+        FROM audit_log
+        `+where+`
+        ORDER BY id DESC
+        LIMIT ? OFFSET ?
+    `, append(args, limit, filter.Offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("error loading audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var e AuditEntry
+		var before, after sql.NullString
+		err := rows.Scan(&e.ID, &e.Entity, &e.EntityID, &e.Action, &before, &after, &e.User, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		e.Before, e.After = before.String, after.String
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// ChangedFields returns the names of the top-level fields that differ between
+// Before and After, in the order they appear in After
+func (e AuditEntry) ChangedFields() []string {
+	before, after := auditFields(e.Before), auditFields(e.After)
+
+	var changed []string
+	seen := make(map[string]bool)
+	for _, fields := range [][]auditField{after, before} {
+		for _, field := range fields {
+			if seen[field.name] {
+				continue
+			}
+			seen[field.name] = true
+			if fieldValue(before, field.name) != fieldValue(after, field.name) {
+				changed = append(changed, field.name)
+			}
+		}
+	}
+	return changed
+}
+
+type auditField struct {
+	name  string
+	value string
+}
+
+// auditFields decodes a JSON object into its fields in document order
+func auditFields(data string) []auditField {
+	if data == "" {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil
+	}
+
+	var fields []auditField
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fields
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return fields
+		}
+		fields = append(fields, auditField{name: key.(string), value: string(value)})
+	}
+	return fields
+}
+
+func fieldValue(fields []auditField, name string) string {
+	for _, field := range fields {
+		if field.name == name {
+			return field.value
+		}
+	}
+	return ""
+}
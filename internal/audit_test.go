@@ -0,0 +1,94 @@
+// internal/audit_test.go
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestProductChangesAudited(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	SetAuditUser("anna")
+	defer SetAuditUser("")
+
+	id, err := AddProduct(db, "Scone", 8)
+	if err != nil {
+		t.Fatalf("AddProduct failed: %v", err)
+	}
+	if err := UpdateProduct(db, Product{ID: id, Name: "Scone", Price: 9.5}); err != nil {
+		t.Fatalf("UpdateProduct failed: %v", err)
+	}
+	// Failed changes are not audited
+	if err := UpdateProduct(db, Product{ID: id + 100, Name: "Missing"}); err == nil {
+		t.Fatal("Expected error for unknown product")
+	}
+
+	entries, err := LoadAuditLog(db, AuditFilter{Entity: EntityProduct})
+	if err != nil {
+		t.Fatalf("LoadAuditLog failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 audit entries, got %d", len(entries))
+	}
+
+	// Newest first
+	update := entries[0]
+	if update.Action != ActionUpdate || update.EntityID != id || update.User != "anna" {
+		t.Errorf("Unexpected update entry: %+v", update)
+	}
+	var before, after Product
+	if err := json.Unmarshal([]byte(update.Before), &before); err != nil {
+		t.Fatalf("Invalid before JSON %q: %v", update.Before, err)
+	}
+	if err := json.Unmarshal([]byte(update.After), &after); err != nil {
+		t.Fatalf("Invalid after JSON %q: %v", update.After, err)
+	}
+	if before.Price != 8 || after.Price != 9.5 {
+		t.Errorf("Expected price change 8 -> 9.5, got %v -> %v", before.Price, after.Price)
+	}
+
+	if create := entries[1]; create.Action != ActionCreate || create.Before != "" || create.After == "" {
+		t.Errorf("Unexpected create entry: %+v", create)
+	}
+
+	// Date and entity filters
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	if entries, _ := LoadAuditLog(db, AuditFilter{From: tomorrow}); len(entries) != 0 {
+		t.Errorf("Expected no entries from tomorrow, got %d", len(entries))
+	}
+	if entries, _ := LoadAuditLog(db, AuditFilter{Entity: EntityOrder}); len(entries) != 0 {
+		t.Errorf("Expected no order entries, got %d", len(entries))
+	}
+	yesterday := time.Now().In(time.FixedZone("UTC+14", 14*3600)).AddDate(0, 0, -1)
+	if entries, _ := LoadAuditLog(db, AuditFilter{From: yesterday, To: tomorrow}); len(entries) != 2 {
+		t.Errorf("Expected 2 entries since yesterday in another zone, got %d", len(entries))
+	}
+
+	// User filter and pages
+	if entries, _ := LoadAuditLog(db, AuditFilter{User: "ben"}); len(entries) != 0 {
+		t.Errorf("Expected no entries by ben, got %d", len(entries))
+	}
+	page, err := LoadAuditLog(db, AuditFilter{User: "anna", Limit: 1, Offset: 1})
+	if err != nil || len(page) != 1 || page[0].ID != entries[1].ID {
+		t.Errorf("Expected the second entry only, got %+v (%v)", page, err)
+	}
+}
+
+func TestAuditEntry_ChangedFields(t *testing.T) {
+	e := AuditEntry{
+		Before: `{"ID":1,"Name":"Scone","Price":8,"Active":true}`,
+		After:  `{"ID":1,"Name":"Scone","Price":9.5,"Active":false}`,
+	}
+	changed := e.ChangedFields()
+	if len(changed) != 2 || changed[0] != "Price" || changed[1] != "Active" {
+		t.Errorf("Expected [Price Active], got %v", changed)
+	}
+
+	created := AuditEntry{After: `{"ID":2,"Name":"Tart"}`}
+	if changed := created.ChangedFields(); len(changed) != 2 {
+		t.Errorf("Expected every field of a created record, got %v", changed)
+	}
+}
@@ -0,0 +1,329 @@
+// internal/backup/backup.go
+
+// Package backup dumps every table of the database to a portable archive and
+// loads an archive into an empty database.
+package backup
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Format identifies OrderFlow backup archives
+const Format = "orderflow-backup"
+
+// Version is the archive version written by this package. Archives with a
+// higher version are rejected when restoring.
+const Version = 1
+
+// timeFormat is the format times are stored in by the SQLite drivers
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// Archive is a copy of every table of a database. It is stored as gzip
+// compressed JSON.
+type Archive struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Tables    []Table   `json:"tables"`
+}
+
+// Table holds the rows of a table together with the statement creating it,
+// so that tables unknown to the restoring database can be created
+type Table struct {
+	Name    string          `json:"name"`
+	Schema  string          `json:"schema"`
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// blob is the JSON form of binary values, which JSON has no type for
+type blob struct {
+	Base64 string `json:"base64"`
+}
+
+// Create copies every table of db, in the order the tables were created so
+// that referenced tables are restored first
+func Create(db *sql.DB) (*Archive, error) {
+	tables, err := listTables(db)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{Format: Format, Version: Version, CreatedAt: time.Now()}
+	for _, t := range tables {
+		table, err := dumpTable(db, t)
+		if err != nil {
+			return nil, err
+		}
+		archive.Tables = append(archive.Tables, table)
+	}
+	return archive, nil
+}
+
+// listTables returns the application tables with their schema, leaving out
+// the internal tables of SQLite and libSQL and the data_version counter, which
+// every database keeps for itself
+func listTables(db *sql.DB) ([]Table, error) {
+	rows, err := db.Query(`
+		SELECT name, sql FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' AND name NOT LIKE 'libsql\_%' ESCAPE '\'
+		    AND name NOT LIKE '\_%' ESCAPE '\' AND name != 'data_version'
+		ORDER BY rowid
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var t Table
+		if err := rows.Scan(&t.Name, &t.Schema); err != nil {
+			return nil, fmt.Errorf("error scanning table: %w", err)
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+func dumpTable(db *sql.DB, table Table) (Table, error) {
+	rows, err := db.Query("SELECT * FROM " + quote(table.Name))
+	if err != nil {
+		return Table{}, fmt.Errorf("error reading %s: %w", table.Name, err)
+	}
+	defer rows.Close()
+
+	if table.Columns, err = rows.Columns(); err != nil {
+		return Table{}, fmt.Errorf("error reading columns of %s: %w", table.Name, err)
+	}
+	table.Rows = [][]interface{}{}
+
+	for rows.Next() {
+		values := make([]interface{}, len(table.Columns))
+		pointers := make([]interface{}, len(values))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return Table{}, fmt.Errorf("error scanning %s: %w", table.Name, err)
+		}
+		for i, v := range values {
+			switch v := v.(type) {
+			case time.Time:
+				values[i] = v.Format(timeFormat)
+			case []byte:
+				values[i] = blob{base64.StdEncoding.EncodeToString(v)}
+			}
+		}
+		table.Rows = append(table.Rows, values)
+	}
+	return table, rows.Err()
+}
+
+// Write stores the archive as gzip compressed JSON
+func Write(w io.Writer, archive *Archive) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(archive); err != nil {
+		return fmt.Errorf("error writing backup: %w", err)
+	}
+	return zw.Close()
+}
+
+// WriteFile stores the archive in a new file at path
+func WriteFile(path string, archive *Archive) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating backup file: %w", err)
+	}
+	if err := Write(f, archive); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// Read loads and validates an archive written by Write
+func Read(r io.Reader) (*Archive, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an OrderFlow backup: %w", err)
+	}
+	defer zr.Close()
+
+	decoder := json.NewDecoder(zr)
+	decoder.UseNumber()
+	var archive Archive
+	if err := decoder.Decode(&archive); err != nil {
+		return nil, fmt.Errorf("error reading backup: %w", err)
+	}
+	if err := archive.Validate(); err != nil {
+		return nil, err
+	}
+
+	for _, table := range archive.Tables {
+		for _, row := range table.Rows {
+			for i, v := range row {
+				if row[i], err = decodeValue(v); err != nil {
+					return nil, fmt.Errorf("error reading %s: %w", table.Name, err)
+				}
+			}
+		}
+	}
+	return &archive, nil
+}
+
+// ReadFile loads and validates the archive at path
+func ReadFile(path string) (*Archive, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening backup file: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// decodeValue converts a JSON cell back to the value it was read as
+func decodeValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case map[string]interface{}:
+		encoded, ok := v["base64"].(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected value %v", v)
+		}
+		return base64.StdEncoding.DecodeString(encoded)
+	}
+	return v, nil
+}
+
+// Validate checks that the archive is a backup this version can restore
+func (a *Archive) Validate() error {
+	if a.Format != Format {
+		return fmt.Errorf("not an OrderFlow backup")
+	}
+	if a.Version < 1 || a.Version > Version {
+		return fmt.Errorf("backup version %d is not supported; this version of OrderFlow restores up to version %d", a.Version, Version)
+	}
+
+	seen := make(map[string]bool)
+	for _, table := range a.Tables {
+		if table.Name == "" || len(table.Columns) == 0 {
+			return fmt.Errorf("backup contains a table without name or columns")
+		}
+		if seen[table.Name] {
+			return fmt.Errorf("backup contains table %s twice", table.Name)
+		}
+		seen[table.Name] = true
+		for i, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				return fmt.Errorf("row %d of %s has %d values, expected %d", i+1, table.Name, len(row), len(table.Columns))
+			}
+		}
+	}
+	return nil
+}
+
+// Rows returns the number of rows in the archive
+func (a *Archive) Rows() int {
+	n := 0
+	for _, table := range a.Tables {
+		n += len(table.Rows)
+	}
+	return n
+}
+
+// Restore loads the archive into db in a single transaction. Tables missing
+// from db are created from the archive. Every table in the archive must be
+// empty in db, so that a restore never mixes two databases.
+func Restore(db *sql.DB, archive *Archive) error {
+	if err := archive.Validate(); err != nil {
+		return err
+	}
+
+	existing, err := listTables(db)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool)
+	for _, t := range existing {
+		exists[t.Name] = true
+	}
+
+	for _, table := range archive.Tables {
+		if !exists[table.Name] {
+			continue
+		}
+		var count int
+		if err := db.QueryRow("SELECT COUNT(*) FROM " + quote(table.Name)).Scan(&count); err != nil {
+			return fmt.Errorf("error checking %s: %w", table.Name, err)
+		}
+		if count > 0 {
+			return fmt.Errorf("database is not empty: %s has %d rows; restore into a new database", table.Name, count)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range archive.Tables {
+		if !exists[table.Name] {
+			if _, err := tx.Exec(table.Schema); err != nil {
+				return fmt.Errorf("error creating %s: %w", table.Name, err)
+			}
+		}
+		if err := restoreTable(tx, table); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing restore: %w", err)
+	}
+	return nil
+}
+
+func restoreTable(tx *sql.Tx, table Table) error {
+	if len(table.Rows) == 0 {
+		return nil
+	}
+
+	columns := make([]string, len(table.Columns))
+	for i, c := range table.Columns {
+		columns[i] = quote(c)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quote(table.Name), strings.Join(columns, ", "), placeholders))
+	if err != nil {
+		return fmt.Errorf("error preparing %s: %w", table.Name, err)
+	}
+	defer stmt.Close()
+
+	for i, row := range table.Rows {
+		if _, err := stmt.Exec(row...); err != nil {
+			return fmt.Errorf("error restoring row %d of %s: %w", i+1, table.Name, err)
+		}
+	}
+	return nil
+}
+
+// quote quotes an identifier for SQLite
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
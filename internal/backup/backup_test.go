@@ -0,0 +1,175 @@
+// internal/backup/backup_test.go
+package backup
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+)
+
+func openTestDB(t *testing.T, name string) *sql.DB {
+	database, err := sql.Open("sqlite3", fmt.Sprintf("file:%s_%s?mode=memory&cache=shared", t.Name(), name))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := db.CreateSchema(database); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	return database
+}
+
+// fillTestDB adds an order, a user and a table the schema does not know
+func fillTestDB(t *testing.T, database *sql.DB) {
+	productID, err := internal.AddProduct(database, "Muffin", 12.5)
+	if err != nil {
+		t.Fatalf("Failed to add product: %v", err)
+	}
+	repID, err := internal.AddRepresentative(database, "Anna")
+	if err != nil {
+		t.Fatalf("Failed to add representative: %v", err)
+	}
+	_, err = internal.CreateOrder(database, internal.Order{
+		DueDate:          time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		ClientName:       "Bob",
+		RepresentativeID: repID,
+		Items:            []internal.OrderItem{{ProductID: productID, Quantity: 4, Price: 12.5}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	if _, err := internal.CreateUser(database, internal.User{Username: "admin", Role: internal.RoleAdmin}, "secret123"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	_, err = database.Exec(`
+		CREATE TABLE future_notes (id INTEGER PRIMARY KEY, note TEXT, data BLOB);
+		INSERT INTO future_notes (note, data) VALUES ('hello', x'00ff10');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create extra table: %v", err)
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	source := openTestDB(t, "source")
+	fillTestDB(t, source)
+
+	archive, err := Create(source)
+	if err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, archive); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+	archive, err = Read(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+
+	target := openTestDB(t, "target")
+	if err := Restore(target, archive); err != nil {
+		t.Fatalf("Failed to restore backup: %v", err)
+	}
+
+	wantOrders, _ := internal.LoadAllOrders(source)
+	gotOrders, err := internal.LoadAllOrders(target)
+	if err != nil || !reflect.DeepEqual(gotOrders, wantOrders) {
+		t.Errorf("Expected orders %+v, got %+v (%v)", wantOrders, gotOrders, err)
+	}
+	if _, err := internal.Authenticate(target, "admin", "secret123"); err != nil {
+		t.Errorf("Expected restored user to log in, got %v", err)
+	}
+
+	var note string
+	var data []byte
+	if err := target.QueryRow("SELECT note, data FROM future_notes").Scan(&note, &data); err != nil {
+		t.Fatalf("Failed to read restored extra table: %v", err)
+	}
+	if note != "hello" || !bytes.Equal(data, []byte{0x00, 0xff, 0x10}) {
+		t.Errorf("Unexpected restored row: %q %x", note, data)
+	}
+
+	// New records continue after the restored IDs
+	id, err := internal.AddProduct(target, "Cake", 80)
+	if err != nil || id != 2 {
+		t.Errorf("Expected new product ID 2, got %d (%v)", id, err)
+	}
+
+	// Restoring again would mix the databases
+	if err := Restore(target, archive); err == nil || !strings.Contains(err.Error(), "not empty") {
+		t.Errorf("Expected restore into a non-empty database to fail, got %v", err)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	if _, err := Read(strings.NewReader("not gzip")); err == nil {
+		t.Error("Expected error for a file that is not a backup")
+	}
+
+	for name, archive := range map[string]*Archive{
+		"format":  {Format: "other", Version: 1},
+		"version": {Format: Format, Version: Version + 1},
+		"row":     {Format: Format, Version: 1, Tables: []Table{{Name: "t", Columns: []string{"a", "b"}, Rows: [][]interface{}{{1}}}}},
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, archive); err != nil {
+			t.Fatalf("Failed to write backup: %v", err)
+		}
+		if _, err := Read(&buf); err == nil {
+			t.Errorf("Expected invalid %s to be rejected", name)
+		}
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	database := openTestDB(t, "db")
+	fillTestDB(t, database)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep me"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.Local)
+	scheduler := NewScheduler(database, dir, 24*time.Hour, 2)
+	scheduler.now = func() time.Time { return now }
+
+	var made []string
+	for _, hours := range []int{0, 1, 24, 48, 72} {
+		now = time.Date(2024, 6, 1, 8+hours, 0, 0, 0, time.Local)
+		path, err := scheduler.BackupIfDue()
+		if err != nil {
+			t.Fatalf("Failed to back up: %v", err)
+		}
+		if path != "" {
+			made = append(made, filepath.Base(path))
+		}
+	}
+	if len(made) != 4 {
+		t.Errorf("Expected a backup on the first run and every 24 hours, got %v", made)
+	}
+
+	files, _ := listBackups(dir)
+	if !reflect.DeepEqual(files, made[2:]) {
+		t.Errorf("Expected the newest two backups %v, got %v", made[2:], files)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.txt")); err != nil {
+		t.Errorf("Expected other files to be kept: %v", err)
+	}
+	if _, err := ReadFile(filepath.Join(dir, files[1])); err != nil {
+		t.Errorf("Expected a readable backup, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+// internal/backup/schedule.go
+package backup
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// File names of automatic backups sort by the time they were made
+const (
+	filePrefix     = "orderflow-backup-"
+	fileSuffix     = ".json.gz"
+	fileTimeFormat = "20060102-150405"
+)
+
+// FileName returns the name of a backup made at t
+func FileName(t time.Time) string {
+	return filePrefix + t.Format(fileTimeFormat) + fileSuffix
+}
+
+// Scheduler makes a backup into a directory whenever the newest one there is
+// older than the interval, and keeps only the newest backups
+type Scheduler struct {
+	db       *sql.DB
+	Dir      string
+	Interval time.Duration
+	// Keep is the number of backups kept; older ones are deleted. Zero keeps
+	// all of them.
+	Keep int
+	now  func() time.Time
+}
+
+// NewScheduler creates a scheduler for backups of db
+func NewScheduler(db *sql.DB, dir string, interval time.Duration, keep int) *Scheduler {
+	return &Scheduler{db: db, Dir: dir, Interval: interval, Keep: keep, now: time.Now}
+}
+
+// Run checks every minute whether a backup is due until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.BackupIfDue(); err != nil {
+			log.Printf("Error making automatic backup: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// BackupIfDue makes a backup when the newest one is older than the interval
+// and returns its path, or "" when no backup was due
+func (s *Scheduler) BackupIfDue() (string, error) {
+	files, err := listBackups(s.Dir)
+	if err != nil {
+		return "", err
+	}
+	if len(files) > 0 {
+		last, err := time.ParseInLocation(fileTimeFormat, backupTime(files[len(files)-1]), time.Local)
+		if err == nil && s.now().Sub(last) < s.Interval {
+			return "", nil
+		}
+	}
+	return s.Backup()
+}
+
+// Backup makes a backup now and deletes the backups beyond Keep
+func (s *Scheduler) Backup() (string, error) {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return "", fmt.Errorf("error creating backup directory: %w", err)
+	}
+
+	archive, err := Create(s.db)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.Dir, FileName(s.now()))
+	if err := WriteFile(path, archive); err != nil {
+		return "", err
+	}
+
+	if err := Prune(s.Dir, s.Keep); err != nil {
+		return path, err
+	}
+	return path, nil
+}
+
+// Prune deletes all but the newest keep backups in dir. Other files are left
+// alone.
+func Prune(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	files, err := listBackups(dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > keep {
+		if err := os.Remove(filepath.Join(dir, files[0])); err != nil {
+			return fmt.Errorf("error deleting old backup: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// listBackups returns the names of the automatic backups in dir, oldest first
+func listBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading backup directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), filePrefix) && strings.HasSuffix(e.Name(), fileSuffix) {
+			files = append(files, e.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func backupTime(name string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix)
+}
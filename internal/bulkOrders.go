@@ -0,0 +1,118 @@
+// internal/bulkOrders.go
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// CompleteOrders marks orders as completed in a single transaction. Nothing
+// is changed when one of the orders is missing or cancelled.
+func CompleteOrders(db *sql.DB, ids []int64) error {
+	return changeOrders(db, ids, ActionComplete, func(tx *sql.Tx, order *Order) (sql.Result, error) {
+		if order.Cancelled {
+			return nil, fmt.Errorf("order %d is cancelled", order.ID)
+		}
+		order.Completed = true
+		return tx.Exec("UPDATE orders SET completed = true, version = version + 1 WHERE id = ?", order.ID)
+	}, func(id int64) Event { return OrderCompleted{OrderID: id} })
+}
+
+// CancelOrders marks orders as cancelled in a single transaction. Cancelled
+// orders are kept for the audit log but no longer count as pending or as
+// sales. Nothing is changed when one of the orders is missing or completed.
+func CancelOrders(db *sql.DB, ids []int64) error {
+	return changeOrders(db, ids, ActionCancel, func(tx *sql.Tx, order *Order) (sql.Result, error) {
+		if order.Completed {
+			return nil, fmt.Errorf("order %d is already completed", order.ID)
+		}
+		order.Cancelled = true
+		return tx.Exec("UPDATE orders SET cancelled = true, version = version + 1 WHERE id = ?", order.ID)
+	}, func(id int64) Event { return OrderCancelled{OrderID: id} })
+}
+
+// AssignRepresentative assigns orders to an active representative in a single
+// transaction
+func AssignRepresentative(db *sql.DB, ids []int64, representativeID int64) error {
+	var rep *Representative
+	return changeOrders(db, ids, ActionUpdate, func(tx *sql.Tx, order *Order) (sql.Result, error) {
+		if rep == nil {
+			loaded, err := loadRepresentative(tx, representativeID)
+			if err != nil {
+				return nil, err
+			}
+			if !loaded.Active {
+				return nil, fmt.Errorf("representative %s is inactive", loaded.Name)
+			}
+			rep = &loaded
+		}
+		order.RepresentativeID, order.RepresentativeName = rep.ID, rep.Name
+		return tx.Exec("UPDATE orders SET representative_id = ?, version = version + 1 WHERE id = ?", rep.ID, order.ID)
+	}, func(id int64) Event { return OrderEdited{OrderID: id} })
+}
+
+// SetDueDate changes the due date of orders in a single transaction
+func SetDueDate(db *sql.DB, ids []int64, due time.Time) error {
+	if due.IsZero() {
+		return fmt.Errorf("due date is required")
+	}
+	return changeOrders(db, ids, ActionUpdate, func(tx *sql.Tx, order *Order) (sql.Result, error) {
+		order.DueDate = due
+		return tx.Exec("UPDATE orders SET due_date = ?, version = version + 1 WHERE id = ?", due, order.ID)
+	}, func(id int64) Event { return OrderEdited{OrderID: id} })
+}
+
+// changeOrders loads each order inside one transaction, lets change update it
+// and records the change in the audit log. The events are published only
+// after everything was committed.
+func changeOrders(
+	db *sql.DB,
+	ids []int64,
+	action string,
+	change func(tx *sql.Tx, order *Order) (sql.Result, error),
+	event func(id int64) Event,
+) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	changed := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		before, err := loadOrder(tx, id)
+		if err != nil {
+			return err
+		}
+
+		after := before
+		after.Version++
+		result, err := change(tx, &after)
+		if err != nil {
+			return err
+		}
+		if err := expectOneRow(result, "order", id); err != nil {
+			return err
+		}
+
+		if err := RecordAudit(tx, EntityOrder, id, action, before, after); err != nil {
+			return err
+		}
+		changed = append(changed, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, id := range changed {
+		DefaultBus.Publish(event(id))
+	}
+	return nil
+}
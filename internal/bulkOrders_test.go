@@ -0,0 +1,143 @@
+// internal/bulkOrders_test.go
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+)
+
+// setupOrdersDB creates the full schema with two representatives and three
+// pending orders
+func setupOrdersDB(t *testing.T) *sql.DB {
+	database, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := db.CreateSchema(database); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	_, err = database.Exec(`
+		INSERT INTO representatives (name, active) VALUES ('Anna', true), ('Ben', true), ('Old', false);
+		INSERT INTO products (name, price, active) VALUES ('Muffin', 12.5, true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	for _, client := range []string{"Alice", "Bob", "Carol"} {
+		_, err := CreateOrder(database, Order{
+			DueDate:          time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC),
+			ClientName:       client,
+			RepresentativeID: 1,
+			Items:            []OrderItem{{ProductID: 1, Quantity: 1, Price: 12.5}},
+		})
+		if err != nil {
+			t.Fatalf("CreateOrder failed: %v", err)
+		}
+	}
+	return database
+}
+
+func TestBulkOrderChanges(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	var events []Event
+	unsubscribe := DefaultBus.Subscribe(func(e Event) { events = append(events, e) })
+	defer unsubscribe()
+
+	if err := AssignRepresentative(database, []int64{1, 2}, 2); err != nil {
+		t.Fatalf("AssignRepresentative failed: %v", err)
+	}
+	due := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	if err := SetDueDate(database, []int64{2, 3}, due); err != nil {
+		t.Fatalf("SetDueDate failed: %v", err)
+	}
+	if err := CancelOrders(database, []int64{3}); err != nil {
+		t.Fatalf("CancelOrders failed: %v", err)
+	}
+	if err := CompleteOrders(database, []int64{1, 2, 2}); err != nil {
+		t.Fatalf("CompleteOrders failed: %v", err)
+	}
+
+	orders, err := LoadAllOrders(database)
+	if err != nil {
+		t.Fatalf("LoadAllOrders failed: %v", err)
+	}
+	got := map[int64]Order{}
+	for _, o := range orders {
+		got[o.ID] = o
+	}
+	if got[1].RepresentativeName != "Ben" || got[2].RepresentativeName != "Ben" || got[3].RepresentativeName != "Anna" {
+		t.Errorf("Unexpected representatives: %+v", orders)
+	}
+	if !got[2].DueDate.Equal(due) || !got[3].DueDate.Equal(due) || got[1].DueDate.Equal(due) {
+		t.Errorf("Unexpected due dates: %+v", orders)
+	}
+	if got[1].Status() != StatusCompleted || got[2].Status() != StatusCompleted || got[3].Status() != StatusCancelled {
+		t.Errorf("Unexpected statuses: %+v", orders)
+	}
+
+	pending, err := LoadOrders(database)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("Expected no pending orders, got %v (%v)", pending, err)
+	}
+
+	want := []Event{
+		OrderEdited{OrderID: 1}, OrderEdited{OrderID: 2},
+		OrderEdited{OrderID: 2}, OrderEdited{OrderID: 3},
+		OrderCancelled{OrderID: 3},
+		OrderCompleted{OrderID: 1}, OrderCompleted{OrderID: 2},
+	}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("Expected events %v, got %v", want, events)
+	}
+
+	entries, err := LoadAuditLog(database, AuditFilter{Entity: EntityOrder, EntityID: 3})
+	if err != nil {
+		t.Fatalf("LoadAuditLog failed: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Action != ActionCancel {
+		t.Errorf("Expected create, update and cancel of order 3, got %+v", entries)
+	}
+}
+
+func TestBulkOrderChanges_AllOrNothing(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	var events []Event
+	unsubscribe := DefaultBus.Subscribe(func(e Event) { events = append(events, e) })
+	defer unsubscribe()
+
+	if err := CompleteOrders(database, []int64{1, 42}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if err := AssignRepresentative(database, []int64{1, 2}, 3); err == nil {
+		t.Error("Expected error assigning an inactive representative")
+	}
+	if err := CancelOrders(database, []int64{2}); err != nil {
+		t.Fatalf("CancelOrders failed: %v", err)
+	}
+	if err := CompleteOrders(database, []int64{1, 2}); err == nil {
+		t.Error("Expected error completing a cancelled order")
+	}
+
+	pending, err := LoadOrders(database)
+	if err != nil {
+		t.Fatalf("LoadOrders failed: %v", err)
+	}
+	if len(pending) != 2 || pending[0].RepresentativeName != "Anna" || pending[1].RepresentativeName != "Anna" {
+		t.Errorf("Expected orders 1 and 3 unchanged, got %+v", pending)
+	}
+	if len(events) != 1 || events[0] != (OrderCancelled{OrderID: 2}) {
+		t.Errorf("Expected only the cancel event, got %v", events)
+	}
+}
@@ -0,0 +1,236 @@
+// internal/businessHours.go
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SettingBusinessHours is the settings key of the business hours
+const SettingBusinessHours = "business_hours"
+
+// DayHours are the opening hours of a weekday in "15:04" form. A day without
+// hours is closed.
+type DayHours struct {
+	Open  string `json:"open,omitempty"`
+	Close string `json:"close,omitempty"`
+}
+
+// Closed reports whether the business is closed all day
+func (d DayHours) Closed() bool {
+	return d.Open == "" && d.Close == ""
+}
+
+// BusinessHours are the times orders can be due. Due times are offered in
+// slots of SlotMinutes from opening time until closing time.
+type BusinessHours struct {
+	// TimeZone is the IANA name of the time zone of the business, empty for
+	// the time zone of this computer
+	TimeZone string `json:"time_zone,omitempty"`
+
+	// Days holds the hours of each weekday, indexed by time.Weekday
+	Days [7]DayHours `json:"days"`
+
+	// ClosedDates are the dates in "2006-01-02" form the business is closed,
+	// such as public holidays
+	ClosedDates []string `json:"closed_dates,omitempty"`
+
+	SlotMinutes int `json:"slot_minutes"`
+}
+
+// DefaultBusinessHours are open 08:00 to 17:00 on weekdays and until 13:00 on
+// Saturdays, in half hour slots
+func DefaultBusinessHours() BusinessHours {
+	weekday := DayHours{Open: "08:00", Close: "17:00"}
+	return BusinessHours{
+		Days: [7]DayHours{
+			time.Sunday:    {},
+			time.Monday:    weekday,
+			time.Tuesday:   weekday,
+			time.Wednesday: weekday,
+			time.Thursday:  weekday,
+			time.Friday:    weekday,
+			time.Saturday:  {Open: "08:00", Close: "13:00"},
+		},
+		SlotMinutes: 30,
+	}
+}
+
+// LoadBusinessHours returns the configured business hours, or the defaults
+// when none were saved
+func LoadBusinessHours(db *sql.DB) (BusinessHours, error) {
+	hours := DefaultBusinessHours()
+	if _, err := LoadSetting(db, SettingBusinessHours, &hours); err != nil {
+		return DefaultBusinessHours(), err
+	}
+	return hours, nil
+}
+
+// SaveBusinessHours validates and stores the business hours
+func SaveBusinessHours(db *sql.DB, hours BusinessHours) error {
+	if err := hours.Validate(); err != nil {
+		return err
+	}
+	return SaveSetting(db, SettingBusinessHours, hours)
+}
+
+// Validate checks the time zone, hours and closed dates
+func (h BusinessHours) Validate() error {
+	if _, err := time.LoadLocation(h.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %q", h.TimeZone)
+	}
+	if h.SlotMinutes < 5 || h.SlotMinutes > 24*60 {
+		return fmt.Errorf("time slots must be between 5 minutes and a day")
+	}
+	for day, hours := range h.Days {
+		if hours.Closed() {
+			continue
+		}
+		open, err := parseClock(hours.Open)
+		if err != nil {
+			return fmt.Errorf("%s opening time: %w", time.Weekday(day), err)
+		}
+		closing, err := parseClock(hours.Close)
+		if err != nil {
+			return fmt.Errorf("%s closing time: %w", time.Weekday(day), err)
+		}
+		if closing <= open {
+			return fmt.Errorf("%s closes before it opens", time.Weekday(day))
+		}
+	}
+	for _, date := range h.ClosedDates {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("closed date %q is not in YYYY-MM-DD form", date)
+		}
+	}
+	return nil
+}
+
+// parseClock returns the minutes since midnight of a "15:04" time
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not in HH:MM form", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Location returns the time zone of the business
+func (h BusinessHours) Location() *time.Location {
+	if loc, err := time.LoadLocation(h.TimeZone); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
+// localDate returns t in the time zone of the business. Dates without a time
+// of day are kept as they are, so that they never move to another day.
+func (h BusinessHours) localDate(t time.Time) time.Time {
+	if !HasDueTime(t) {
+		return t
+	}
+	return t.In(h.Location())
+}
+
+// ClosedReason returns why the business is closed on the date of day, or ""
+// when it is open
+func (h BusinessHours) ClosedReason(day time.Time) string {
+	day = h.localDate(day)
+	date := day.Format("2006-01-02")
+	for _, closed := range h.ClosedDates {
+		if closed == date {
+			return "closed on " + date
+		}
+	}
+	if h.Days[day.Weekday()].Closed() {
+		return "closed on " + day.Weekday().String() + "s"
+	}
+	return ""
+}
+
+// Slots returns the due times that can be chosen on the date of day, in the
+// time zone of the business. The last slot starts before closing time.
+func (h BusinessHours) Slots(day time.Time) []time.Time {
+	if h.ClosedReason(day) != "" || h.SlotMinutes <= 0 {
+		return nil
+	}
+	loc := h.Location()
+	day = h.localDate(day)
+	hours := h.Days[day.Weekday()]
+	open, err := parseClock(hours.Open)
+	if err != nil {
+		return nil
+	}
+	closing, err := parseClock(hours.Close)
+	if err != nil {
+		return nil
+	}
+
+	var slots []time.Time
+	for minute := open; minute < closing; minute += h.SlotMinutes {
+		slots = append(slots, time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, loc))
+	}
+	return slots
+}
+
+// Check returns an error when the business is closed at due. Due dates
+// without a time of day, see HasDueTime, are only checked against closed days.
+func (h BusinessHours) Check(due time.Time) error {
+	if reason := h.ClosedReason(due); reason != "" {
+		return fmt.Errorf("due date is not possible, the business is %s", reason)
+	}
+	if !HasDueTime(due) {
+		return nil
+	}
+
+	local := due.In(h.Location())
+	hours := h.Days[local.Weekday()]
+	minute := local.Hour()*60 + local.Minute()
+	open, _ := parseClock(hours.Open)
+	closing, _ := parseClock(hours.Close)
+	if minute < open || minute >= closing {
+		return fmt.Errorf("due time %s is outside business hours %s to %s on %ss",
+			local.Format("15:04"), hours.Open, hours.Close, local.Weekday())
+	}
+	return nil
+}
+
+// HasDueTime reports whether a due date includes a time of day. Orders from
+// before due times were introduced are due at midnight.
+func HasDueTime(due time.Time) bool {
+	return due.Hour() != 0 || due.Minute() != 0
+}
+
+// FormatDue formats a due date in the time zone of the business, with the
+// time of day when it has one. Dates without a time are shown as stored, so
+// that they never move to another day.
+func (h BusinessHours) FormatDue(due time.Time) string {
+	if !HasDueTime(due) {
+		return due.Format("2006-01-02")
+	}
+	return due.In(h.Location()).Format("2006-01-02 15:04")
+}
+
+// FormatDueRFC3339 formats a due date like FormatDue, but as RFC 3339 with
+// the offset of the business when it has a time of day
+func (h BusinessHours) FormatDueRFC3339(due time.Time) string {
+	if !HasDueTime(due) {
+		return due.Format("2006-01-02")
+	}
+	return due.In(h.Location()).Format(time.RFC3339)
+}
+
+// ParseDue parses a due date in "2006-01-02" form, or with a time of day in
+// "2006-01-02 15:04" form in the time zone of the business
+func (h BusinessHours) ParseDue(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if due, err := time.ParseInLocation("2006-01-02 15:04", text, h.Location()); err == nil {
+		return due, nil
+	}
+	if due, err := time.Parse("2006-01-02", text); err == nil {
+		return due, nil
+	}
+	return time.Time{}, fmt.Errorf("Invalid due date format. Please use YYYY-MM-DD or YYYY-MM-DD HH:MM")
+}
@@ -0,0 +1,126 @@
+// internal/businessHours_test.go
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBusinessHours_Check(t *testing.T) {
+	hours := DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	hours.ClosedDates = []string{"2024-12-25"}
+	loc := hours.Location()
+
+	tests := []struct {
+		name  string
+		due   time.Time
+		valid bool
+	}{
+		{"weekday slot", time.Date(2024, 6, 10, 9, 30, 0, 0, loc), true},
+		{"date only", time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), true},
+		{"before opening", time.Date(2024, 6, 10, 7, 30, 0, 0, loc), false},
+		{"at closing", time.Date(2024, 6, 10, 17, 0, 0, 0, loc), false},
+		{"saturday afternoon", time.Date(2024, 6, 8, 14, 0, 0, 0, loc), false},
+		{"sunday", time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC), false},
+		{"closed date", time.Date(2024, 12, 25, 10, 0, 0, 0, loc), false},
+		// 07:00 UTC is 09:00 in Johannesburg
+		{"other time zone", time.Date(2024, 6, 10, 7, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := hours.Check(tt.due)
+			if tt.valid && err != nil {
+				t.Errorf("Expected %v to be valid, got %v", tt.due, err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Expected %v to be rejected", tt.due)
+			}
+		})
+	}
+}
+
+func TestBusinessHours_Slots(t *testing.T) {
+	hours := DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	hours.SlotMinutes = 60
+
+	slots := hours.Slots(time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC))
+	if len(slots) != 5 || slots[0].Format("15:04") != "08:00" || slots[4].Format("15:04") != "12:00" {
+		t.Errorf("Expected Saturday slots from 08:00 to 12:00, got %v", slots)
+	}
+	if slots[0].Location().String() != "Africa/Johannesburg" {
+		t.Errorf("Expected slots in the business time zone, got %v", slots[0].Location())
+	}
+	if slots := hours.Slots(time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC)); len(slots) != 0 {
+		t.Errorf("Expected no slots on Sunday, got %v", slots)
+	}
+
+	due, err := hours.ParseDue("2024-06-08 10:00")
+	if err != nil || hours.FormatDue(due) != "2024-06-08 10:00" || hours.FormatDue(due.UTC()) != "2024-06-08 10:00" {
+		t.Errorf("Expected due time to round trip, got %v (%v)", due, err)
+	}
+	if due, err := hours.ParseDue("2024-06-08"); err != nil || HasDueTime(due) || hours.FormatDue(due) != "2024-06-08" {
+		t.Errorf("Expected a date without time, got %v (%v)", due, err)
+	}
+	if _, err := hours.ParseDue("08/06/2024"); err == nil {
+		t.Error("Expected error for an invalid due date")
+	}
+}
+
+func TestBusinessHours_Validate(t *testing.T) {
+	for name, change := range map[string]func(*BusinessHours){
+		"time zone":   func(h *BusinessHours) { h.TimeZone = "Mars/Olympus" },
+		"slot":        func(h *BusinessHours) { h.SlotMinutes = 0 },
+		"clock":       func(h *BusinessHours) { h.Days[time.Monday].Open = "8am" },
+		"order":       func(h *BusinessHours) { h.Days[time.Monday].Close = "07:00" },
+		"closed date": func(h *BusinessHours) { h.ClosedDates = []string{"25/12/2024"} },
+	} {
+		hours := DefaultBusinessHours()
+		change(&hours)
+		if err := hours.Validate(); err == nil {
+			t.Errorf("Expected invalid %s to be rejected", name)
+		}
+	}
+}
+
+func TestSaveBusinessHours(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	loaded, err := LoadBusinessHours(database)
+	if err != nil || loaded.SlotMinutes != DefaultBusinessHours().SlotMinutes {
+		t.Fatalf("Expected the default hours, got %+v (%v)", loaded, err)
+	}
+
+	hours := DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	hours.Days[time.Sunday] = DayHours{Open: "09:00", Close: "12:00"}
+	hours.ClosedDates = []string{"2024-12-25"}
+	if err := SaveBusinessHours(database, hours); err != nil {
+		t.Fatalf("SaveBusinessHours failed: %v", err)
+	}
+	hours.SlotMinutes = 15
+	if err := SaveBusinessHours(database, hours); err != nil {
+		t.Fatalf("SaveBusinessHours failed: %v", err)
+	}
+
+	loaded, err = LoadBusinessHours(database)
+	if err != nil {
+		t.Fatalf("LoadBusinessHours failed: %v", err)
+	}
+	if loaded.TimeZone != hours.TimeZone || loaded.Days[time.Sunday].Open != "09:00" ||
+		len(loaded.ClosedDates) != 1 || loaded.SlotMinutes != 15 {
+		t.Errorf("Unexpected hours: %+v", loaded)
+	}
+
+	entries, err := LoadAuditLog(database, AuditFilter{Entity: EntitySetting})
+	if err != nil || len(entries) != 2 || entries[0].Action != ActionUpdate || entries[1].Action != ActionCreate {
+		t.Errorf("Expected the create and update to be audited, got %+v (%v)", entries, err)
+	}
+
+	hours.SlotMinutes = 0
+	if err := SaveBusinessHours(database, hours); err == nil {
+		t.Error("Expected invalid hours to be rejected")
+	}
+}
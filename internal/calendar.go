@@ -0,0 +1,91 @@
+// internal/calendar.go
+package internal
+
+import (
+	"sort"
+	"time"
+)
+
+// CalendarDay holds the orders due on a date
+type CalendarDay struct {
+	// Date is the day at midnight UTC
+	Date   time.Time
+	Orders []Order
+}
+
+// Items returns the number of items of the orders due that day
+func (d CalendarDay) Items() int {
+	var items int
+	for _, order := range d.Orders {
+		for _, item := range order.Items {
+			items += item.Quantity
+		}
+	}
+	return items
+}
+
+// Usage returns the items due that day
+func (d CalendarDay) Usage() Usage {
+	var usage Usage
+	for _, order := range d.Orders {
+		usage.add(order)
+	}
+	return usage
+}
+
+// Over returns the limits of capacity the orders due that day exceed
+func (d CalendarDay) Over(capacity Capacity) []Overbooking {
+	names := make(map[int64]string)
+	for _, order := range d.Orders {
+		for _, item := range order.Items {
+			names[item.ProductID] = item.ProductName
+		}
+	}
+	return capacity.Exceeded(d.Usage(), names)
+}
+
+// DueDay returns the date of due in the time zone of the business, at
+// midnight UTC
+func (h BusinessHours) DueDay(due time.Time) time.Time {
+	due = h.localDate(due)
+	return time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// MoveToDay returns due moved to the date of day, keeping its time of day in
+// the time zone of the business
+func (h BusinessHours) MoveToDay(due, day time.Time) time.Time {
+	if !HasDueTime(due) {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	local := due.In(h.Location())
+	return time.Date(day.Year(), day.Month(), day.Day(), local.Hour(), local.Minute(), 0, 0, h.Location())
+}
+
+// OrderCalendar places orders on the days days from first on by their due
+// date. Orders due on other days are left out. The orders of a day are sorted
+// by due time and client.
+func OrderCalendar(orders []Order, hours BusinessHours, first time.Time, days int) []CalendarDay {
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	calendar := make([]CalendarDay, days)
+	for i := range calendar {
+		calendar[i].Date = first.AddDate(0, 0, i)
+	}
+	for _, order := range orders {
+		i := int(hours.DueDay(order.DueDate).Sub(first).Hours() / 24)
+		if i < 0 || i >= days {
+			continue
+		}
+		calendar[i].Orders = append(calendar[i].Orders, order)
+	}
+
+	for _, day := range calendar {
+		sort.SliceStable(day.Orders, func(i, j int) bool {
+			a, b := day.Orders[i], day.Orders[j]
+			if !a.DueDate.Equal(b.DueDate) {
+				return a.DueDate.Before(b.DueDate)
+			}
+			return a.ClientName < b.ClientName
+		})
+	}
+	return calendar
+}
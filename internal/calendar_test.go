@@ -0,0 +1,53 @@
+// internal/calendar_test.go
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderCalendar(t *testing.T) {
+	hours := DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	loc := hours.Location()
+
+	orders := []Order{
+		{ID: 1, ClientName: "Carol", DueDate: time.Date(2024, 6, 10, 14, 0, 0, 0, loc), Items: []OrderItem{{Quantity: 3}}},
+		{ID: 2, ClientName: "Alice", DueDate: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), Items: []OrderItem{{Quantity: 2}, {Quantity: 5}}},
+		// 23:30 UTC is already the next day in Johannesburg
+		{ID: 3, ClientName: "Bob", DueDate: time.Date(2024, 6, 10, 23, 30, 0, 0, time.UTC), Items: []OrderItem{{Quantity: 1}}},
+		{ID: 4, ClientName: "Dave", DueDate: time.Date(2024, 6, 20, 0, 0, 0, 0, time.UTC)},
+	}
+
+	calendar := OrderCalendar(orders, hours, time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), 7)
+	if len(calendar) != 7 || !calendar[6].Date.Equal(time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected a week from 2024-06-10, got %+v", calendar)
+	}
+	monday := calendar[0]
+	if len(monday.Orders) != 2 || monday.Orders[0].ID != 2 || monday.Orders[1].ID != 1 || monday.Items() != 10 {
+		t.Errorf("Unexpected Monday: %+v", monday)
+	}
+	if len(calendar[1].Orders) != 1 || calendar[1].Orders[0].ID != 3 {
+		t.Errorf("Expected Bob on Tuesday, got %+v", calendar[1].Orders)
+	}
+
+	if len(monday.Over(Capacity{})) != 0 || len(monday.Over(Capacity{ItemsPerDay: 9})) != 1 ||
+		len(monday.Over(Capacity{ItemsPerDay: 10})) != 0 {
+		t.Error("Expected Monday to be over a capacity of 9 items only")
+	}
+}
+
+func TestBusinessHours_MoveToDay(t *testing.T) {
+	hours := DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	day := time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)
+
+	moved := hours.MoveToDay(time.Date(2024, 6, 10, 8, 30, 0, 0, time.UTC), day)
+	if hours.FormatDue(moved) != "2024-06-12 10:30" {
+		t.Errorf("Expected the time of day to be kept, got %s", hours.FormatDue(moved))
+	}
+	moved = hours.MoveToDay(time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), day)
+	if !moved.Equal(day) {
+		t.Errorf("Expected a date without time, got %v", moved)
+	}
+}
@@ -0,0 +1,352 @@
+// internal/capacity.go
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SettingCapacity is the settings key of the production capacity
+const SettingCapacity = "capacity"
+
+// capacitySearchDays is how far after the due date to look for days with
+// room for an order that does not fit
+const capacitySearchDays = 60
+
+// capacitySuggestions is the number of days with room suggested
+const capacitySuggestions = 3
+
+// ErrOverbooked is returned when an order does not fit the capacity of its
+// due day and overbooking is blocked
+var ErrOverbooked = errors.New("over capacity")
+
+// Capacity is how much can be made per day. Limits are in items, summed over
+// the open orders due that day; zero means no limit.
+type Capacity struct {
+	// ItemsPerDay limits all products together
+	ItemsPerDay int `json:"items_per_day,omitempty"`
+
+	// Products limits single products, keyed by product ID
+	Products map[int64]int `json:"products,omitempty"`
+
+	// Categories limit groups of products together, such as all cakes
+	Categories []CapacityCategory `json:"categories,omitempty"`
+
+	// Block rejects orders that do not fit instead of only warning
+	Block bool `json:"block,omitempty"`
+}
+
+// CapacityCategory limits the products in it together
+type CapacityCategory struct {
+	Name        string  `json:"name"`
+	ProductIDs  []int64 `json:"product_ids"`
+	ItemsPerDay int     `json:"items_per_day"`
+}
+
+// Validate checks that limits are not negative and categories are named
+func (c Capacity) Validate() error {
+	if c.ItemsPerDay < 0 {
+		return fmt.Errorf("items per day cannot be negative")
+	}
+	for _, limit := range c.Products {
+		if limit < 0 {
+			return fmt.Errorf("product capacity cannot be negative")
+		}
+	}
+	names := make(map[string]bool)
+	for _, category := range c.Categories {
+		name := strings.TrimSpace(category.Name)
+		if name == "" {
+			return fmt.Errorf("every capacity category needs a name")
+		}
+		if names[strings.ToLower(name)] {
+			return fmt.Errorf("capacity category %q is listed twice", name)
+		}
+		names[strings.ToLower(name)] = true
+		if category.ItemsPerDay < 0 {
+			return fmt.Errorf("capacity of %s cannot be negative", name)
+		}
+	}
+	return nil
+}
+
+// LoadCapacity returns the configured capacity, without limits when none was
+// saved
+func LoadCapacity(db *sql.DB) (Capacity, error) {
+	var capacity Capacity
+	if _, err := LoadSetting(db, SettingCapacity, &capacity); err != nil {
+		return Capacity{}, err
+	}
+	return capacity, nil
+}
+
+// SaveCapacity validates and stores the capacity
+func SaveCapacity(db *sql.DB, capacity Capacity) error {
+	if err := capacity.Validate(); err != nil {
+		return err
+	}
+	return SaveSetting(db, SettingCapacity, capacity)
+}
+
+// Usage is the number of items due on a day, in total and per product
+type Usage struct {
+	Items    int
+	Products map[int64]int
+}
+
+// add counts the items of order
+func (u *Usage) add(order Order) {
+	if u.Products == nil {
+		u.Products = make(map[int64]int)
+	}
+	for _, item := range order.Items {
+		u.Items += item.Quantity
+		u.Products[item.ProductID] += item.Quantity
+	}
+}
+
+// plus returns the sum of u and other
+func (u Usage) plus(other Usage) Usage {
+	sum := Usage{Items: u.Items + other.Items, Products: make(map[int64]int)}
+	for id, n := range u.Products {
+		sum.Products[id] += n
+	}
+	for id, n := range other.Products {
+		sum.Products[id] += n
+	}
+	return sum
+}
+
+// minus returns the items u has more than other
+func (u Usage) minus(other Usage) Usage {
+	diff := Usage{Products: make(map[int64]int)}
+	if u.Items > other.Items {
+		diff.Items = u.Items - other.Items
+	}
+	for id, n := range u.Products {
+		if n > other.Products[id] {
+			diff.Products[id] = n - other.Products[id]
+		}
+	}
+	return diff
+}
+
+// Overbooking is a limit that is exceeded
+type Overbooking struct {
+	// Limit names what is limited: "all products", a product or a category
+	Limit    string
+	Due      int
+	Capacity int
+
+	// ProductIDs are the products the limit applies to, nil for all
+	ProductIDs []int64
+}
+
+func (o Overbooking) String() string {
+	return fmt.Sprintf("%s: %d items due, capacity %d", o.Limit, o.Due, o.Capacity)
+}
+
+// Exceeded returns the limits usage exceeds. names gives the names of
+// products; others are shown by ID.
+func (c Capacity) Exceeded(usage Usage, names map[int64]string) []Overbooking {
+	var exceeded []Overbooking
+	if c.ItemsPerDay > 0 && usage.Items > c.ItemsPerDay {
+		exceeded = append(exceeded, Overbooking{Limit: "all products", Due: usage.Items, Capacity: c.ItemsPerDay})
+	}
+
+	// Sorted, so that the messages do not change order
+	var ids []int64
+	for id := range c.Products {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		limit := c.Products[id]
+		if limit > 0 && usage.Products[id] > limit {
+			name, ok := names[id]
+			if !ok {
+				name = fmt.Sprintf("product %d", id)
+			}
+			exceeded = append(exceeded, Overbooking{Limit: name, Due: usage.Products[id], Capacity: limit, ProductIDs: []int64{id}})
+		}
+	}
+
+	for _, category := range c.Categories {
+		var due int
+		for _, id := range category.ProductIDs {
+			due += usage.Products[id]
+		}
+		if category.ItemsPerDay > 0 && due > category.ItemsPerDay {
+			exceeded = append(exceeded, Overbooking{Limit: category.Name, Due: due, Capacity: category.ItemsPerDay,
+				ProductIDs: category.ProductIDs})
+		}
+	}
+	return exceeded
+}
+
+// CapacityCheck is the result of checking an order against the capacity of
+// its due day
+type CapacityCheck struct {
+	// Overbooked holds the limits the order would exceed
+	Overbooked []Overbooking
+
+	// Suggestions are the next days, after the due day, the order fits on
+	Suggestions []time.Time
+
+	// Blocked is set when orders that do not fit are rejected
+	Blocked bool
+}
+
+// OK reports whether the order fits
+func (c CapacityCheck) OK() bool {
+	return len(c.Overbooked) == 0
+}
+
+// Message describes the exceeded limits and the suggested days
+func (c CapacityCheck) Message() string {
+	var lines []string
+	for _, o := range c.Overbooked {
+		lines = append(lines, o.String())
+	}
+	message := "the due day is over capacity for " + strings.Join(lines, "; ")
+	if len(c.Suggestions) > 0 {
+		var days []string
+		for _, day := range c.Suggestions {
+			days = append(days, day.Format("Mon 2006-01-02"))
+		}
+		message += ". Days with room: " + strings.Join(days, ", ")
+	}
+	return message
+}
+
+// Err returns nil when the order fits or overbooking only warns, and an error
+// wrapping ErrOverbooked when it is blocked
+func (c CapacityCheck) Err() error {
+	if c.OK() || !c.Blocked {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOverbooked, c.Message())
+}
+
+// CheckCapacity checks whether order fits the capacity of its due day next
+// to the other open orders due that day. Only the limits the order adds items
+// to are checked, so an order is not held responsible for a day that was
+// already over capacity in other products.
+func CheckCapacity(db *sql.DB, order Order) (CapacityCheck, error) {
+	return CheckCapacityOf(db, []Order{order}, order.DueDate)
+}
+
+// CheckCapacityOf checks whether orders together fit the capacity of the day
+// of due, as when they are all moved there at once
+func CheckCapacityOf(db *sql.DB, orders []Order, due time.Time) (CapacityCheck, error) {
+	capacity, err := LoadCapacity(db)
+	if err != nil {
+		return CapacityCheck{}, err
+	}
+	hours, err := LoadBusinessHours(db)
+	if err != nil {
+		return CapacityCheck{}, err
+	}
+	open, err := LoadOrders(db)
+	if err != nil {
+		return CapacityCheck{}, err
+	}
+	return capacity.CheckOrders(orders, due, open, hours), nil
+}
+
+// Check checks order against the other open orders, see CheckCapacity
+func (c Capacity) Check(order Order, open []Order, hours BusinessHours) CapacityCheck {
+	return c.CheckOrders([]Order{order}, order.DueDate, open, hours)
+}
+
+// CheckOrders checks orders moved to the day of due against the other open
+// orders, see CheckCapacityOf. Completed and cancelled orders take no
+// capacity.
+func (c Capacity) CheckOrders(orders []Order, due time.Time, open []Order, hours BusinessHours) CapacityCheck {
+	check := CapacityCheck{Blocked: c.Block}
+
+	// The items of the checked orders, and their names
+	var added Usage
+	checked := make(map[int64]bool)
+	names := make(map[int64]string)
+	for _, order := range orders {
+		if order.Status() != StatusPending {
+			continue
+		}
+		added.add(order)
+		if order.ID != 0 {
+			checked[order.ID] = true
+		}
+		for _, item := range order.Items {
+			names[item.ProductID] = item.ProductName
+		}
+	}
+
+	// Usage by other orders per day. Edited orders only add what they have
+	// more than their saved versions on the day those are due.
+	usage := make(map[time.Time]*Usage)
+	saved := make(map[time.Time]*Usage)
+	for _, o := range open {
+		day := hours.DueDay(o.DueDate)
+		byDay := usage
+		if checked[o.ID] {
+			byDay = saved
+		}
+		if byDay[day] == nil {
+			byDay[day] = &Usage{}
+		}
+		byDay[day].add(o)
+	}
+
+	exceeded := func(day time.Time) []Overbooking {
+		var total Usage
+		if other := usage[day]; other != nil {
+			total = *other
+		}
+		mine := added
+		if before := saved[day]; before != nil {
+			mine = mine.minus(*before)
+		}
+		var over []Overbooking
+		for _, o := range c.Exceeded(total.plus(added), names) {
+			if o.addedBy(mine) {
+				over = append(over, o)
+			}
+		}
+		return over
+	}
+
+	day := hours.DueDay(due)
+	check.Overbooked = exceeded(day)
+	if check.OK() {
+		return check
+	}
+
+	for next := day.AddDate(0, 0, 1); next.Before(day.AddDate(0, 0, capacitySearchDays+1)); next = next.AddDate(0, 0, 1) {
+		if hours.ClosedReason(next) != "" || len(exceeded(next)) > 0 {
+			continue
+		}
+		check.Suggestions = append(check.Suggestions, next)
+		if len(check.Suggestions) == capacitySuggestions {
+			break
+		}
+	}
+	return check
+}
+
+// addedBy reports whether usage adds items to the limit
+func (o Overbooking) addedBy(usage Usage) bool {
+	if o.ProductIDs == nil {
+		return usage.Items > 0
+	}
+	for _, id := range o.ProductIDs {
+		if usage.Products[id] > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+// internal/capacity_test.go
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCapacity_Check(t *testing.T) {
+	hours := DefaultBusinessHours()
+	hours.TimeZone = "UTC"
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	muffins := func(n int) []OrderItem { return []OrderItem{{ProductID: 1, ProductName: "Muffin", Quantity: n}} }
+
+	open := []Order{
+		{ID: 1, DueDate: day(10), Items: muffins(8)},
+		{ID: 2, DueDate: day(10), Items: []OrderItem{{ProductID: 2, ProductName: "Chocolate Cake", Quantity: 3}}},
+		{ID: 3, DueDate: day(11), Items: muffins(11)},
+		{ID: 4, DueDate: day(15), Items: muffins(10)},
+	}
+	capacity := Capacity{
+		ItemsPerDay: 12,
+		Products:    map[int64]int{2: 3},
+		Categories:  []CapacityCategory{{Name: "Cakes", ProductIDs: []int64{2, 3}, ItemsPerDay: 3}},
+	}
+
+	// Monday is full and Tuesday has no room for 2 more
+	check := capacity.Check(Order{DueDate: day(10), Items: muffins(2)}, open, hours)
+	if len(check.Overbooked) != 1 || check.Overbooked[0].Limit != "all products" || check.Overbooked[0].Due != 13 {
+		t.Fatalf("Expected all products to be overbooked, got %+v", check.Overbooked)
+	}
+	if len(check.Suggestions) != 3 || !check.Suggestions[0].Equal(day(12)) || !check.Suggestions[2].Equal(day(14)) {
+		t.Errorf("Expected 12 to 14 June to be suggested, got %v", check.Suggestions)
+	}
+	if check.Err() != nil {
+		t.Errorf("Expected only a warning, got %v", check.Err())
+	}
+
+	// A carrot cake exceeds the cakes but not the chocolate cake limit
+	carrot := Order{DueDate: day(10), Items: []OrderItem{{ProductID: 3, ProductName: "Carrot Cake", Quantity: 1}}}
+	check = capacity.Check(carrot, open, hours)
+	if len(check.Overbooked) != 1 || check.Overbooked[0].Limit != "Cakes" {
+		t.Errorf("Expected the cakes to be overbooked, got %+v", check.Overbooked)
+	}
+
+	// Saturday is full and Sunday is closed
+	check = capacity.Check(Order{DueDate: day(15), Items: muffins(3)}, open, hours)
+	if len(check.Suggestions) == 0 || !check.Suggestions[0].Equal(day(17)) {
+		t.Errorf("Expected Monday 17 June first, got %v", check.Suggestions)
+	}
+
+	// An edited order replaces its saved version
+	check = capacity.Check(Order{ID: 1, DueDate: day(10), Items: muffins(9)}, open, hours)
+	if !check.OK() {
+		t.Errorf("Expected the edited order to fit, got %+v", check.Overbooked)
+	}
+
+	// Changing an order on a full day without adding items is fine
+	unchanged := Order{ID: 4, DueDate: day(15), Items: muffins(10), Comment: "Call first"}
+	check = Capacity{ItemsPerDay: 5}.Check(unchanged, open, hours)
+	if !check.OK() {
+		t.Errorf("Expected an unchanged order to fit, got %+v", check.Overbooked)
+	}
+
+	// Orders moved together count together, but not twice on their own day
+	check = capacity.CheckOrders(open[:2], day(12), open, hours)
+	if !check.OK() {
+		t.Errorf("Expected Monday's orders to fit on Wednesday, got %+v", check.Overbooked)
+	}
+	check = capacity.CheckOrders([]Order{open[0], open[2]}, day(12), open, hours)
+	if len(check.Overbooked) != 1 || check.Overbooked[0].Due != 19 {
+		t.Errorf("Expected 19 items on Wednesday, got %+v", check.Overbooked)
+	}
+	check = capacity.CheckOrders([]Order{open[0], open[1]}, day(10), open, hours)
+	if !check.OK() {
+		t.Errorf("Expected orders kept on their day to fit, got %+v", check.Overbooked)
+	}
+	cancelled := Order{ID: 9, Cancelled: true, Items: muffins(20)}
+	check = capacity.CheckOrders([]Order{cancelled}, day(11), open, hours)
+	if !check.OK() {
+		t.Errorf("Expected a cancelled order to take no capacity, got %+v", check.Overbooked)
+	}
+
+	capacity.Block = true
+	check = capacity.Check(Order{DueDate: day(10), Items: muffins(2)}, open, hours)
+	if err := check.Err(); !errors.Is(err, ErrOverbooked) {
+		t.Errorf("Expected overbooking to be blocked, got %v", err)
+	}
+}
+
+func TestSaveCapacity(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	for _, invalid := range []Capacity{
+		{ItemsPerDay: -1},
+		{Products: map[int64]int{1: -5}},
+		{Categories: []CapacityCategory{{Name: " "}}},
+		{Categories: []CapacityCategory{{Name: "Cakes"}, {Name: "cakes"}}},
+	} {
+		if err := SaveCapacity(database, invalid); err == nil {
+			t.Errorf("Expected %+v to be rejected", invalid)
+		}
+	}
+
+	saved := Capacity{
+		ItemsPerDay: 40,
+		Products:    map[int64]int{1: 20},
+		Categories:  []CapacityCategory{{Name: "Cakes", ProductIDs: []int64{1}, ItemsPerDay: 10}},
+		Block:       true,
+	}
+	if err := SaveCapacity(database, saved); err != nil {
+		t.Fatalf("SaveCapacity failed: %v", err)
+	}
+	capacity, err := LoadCapacity(database)
+	if err != nil || capacity.ItemsPerDay != 40 || capacity.Products[1] != 20 ||
+		len(capacity.Categories) != 1 || !capacity.Block {
+		t.Errorf("Unexpected capacity %+v (%v)", capacity, err)
+	}
+
+	// The test orders are due on a Monday with 3 muffins between them
+	check, err := CheckCapacity(database, Order{
+		DueDate: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC),
+		Items:   []OrderItem{{ProductID: 1, ProductName: "Muffin", Quantity: 8}},
+	})
+	if err != nil || len(check.Overbooked) != 1 || check.Overbooked[0].Limit != "Cakes" {
+		t.Errorf("Expected the cakes to be overbooked, got %+v (%v)", check, err)
+	}
+}
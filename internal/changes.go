@@ -0,0 +1,79 @@
+// internal/changes.go
+package internal
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+)
+
+// DefaultChangeInterval is how often ChangeWatcher checks for changes unless
+// configured otherwise
+const DefaultChangeInterval = 10 * time.Second
+
+// DataVersion returns the counter that the database increases with every
+// change to orders, order items, products and representatives
+func DataVersion(db *sql.DB) (int64, error) {
+	var version int64
+	err := db.QueryRow("SELECT version FROM data_version WHERE id = 1").Scan(&version)
+	return version, err
+}
+
+// ChangeWatcher polls the data version of a database shared by several
+// machines and publishes DataChanged when it moved
+type ChangeWatcher struct {
+	db       *sql.DB
+	bus      *Bus
+	Interval time.Duration
+
+	version int64
+	checked bool
+}
+
+// NewChangeWatcher creates a watcher publishing on DefaultBus
+func NewChangeWatcher(db *sql.DB, interval time.Duration) *ChangeWatcher {
+	return &ChangeWatcher{db: db, bus: DefaultBus, Interval: interval}
+}
+
+// Check reports whether the data changed since the previous check. The first
+// check only records the current version.
+func (w *ChangeWatcher) Check() (bool, error) {
+	version, err := DataVersion(w.db)
+	if err != nil {
+		return false, err
+	}
+	changed := w.checked && version != w.version
+	w.version, w.checked = version, true
+	return changed, nil
+}
+
+// Run checks for changes every Interval until ctx is done. Changes made on
+// this machine are seen as well; they cause one more reload, which is cheaper
+// than telling them apart.
+func (w *ChangeWatcher) Run(ctx context.Context) {
+	if w.Interval <= 0 {
+		return
+	}
+	if _, err := w.Check(); err != nil {
+		log.Printf("Error checking for changes: %v", err)
+	}
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := w.Check()
+			if err != nil {
+				log.Printf("Error checking for changes: %v", err)
+				continue
+			}
+			if changed {
+				w.bus.Publish(DataChanged{})
+			}
+		}
+	}
+}
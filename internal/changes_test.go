@@ -0,0 +1,27 @@
+// internal/changes_test.go
+package internal
+
+import "testing"
+
+func TestChangeWatcher_Check(t *testing.T) {
+	database := setupOrdersDB(t)
+	watcher := NewChangeWatcher(database, DefaultChangeInterval)
+
+	if changed, err := watcher.Check(); err != nil || changed {
+		t.Fatalf("Expected the first check to only record the version, got %v (%v)", changed, err)
+	}
+	if changed, err := watcher.Check(); err != nil || changed {
+		t.Errorf("Expected no change, got %v (%v)", changed, err)
+	}
+
+	// Any machine changing the shared data moves the version
+	if _, err := database.Exec("UPDATE representatives SET name = 'Anne' WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to rename representative: %v", err)
+	}
+	if changed, err := watcher.Check(); err != nil || !changed {
+		t.Errorf("Expected a change, got %v (%v)", changed, err)
+	}
+	if changed, err := watcher.Check(); err != nil || changed {
+		t.Errorf("Expected the change to be reported once, got %v (%v)", changed, err)
+	}
+}
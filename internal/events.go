@@ -0,0 +1,140 @@
+// internal/events.go
+package internal
+
+import (
+	"log"
+	"sync"
+)
+
+// Event is a change that has been committed to the database
+type Event interface {
+	EventName() string
+}
+
+// Events published by the write functions of this package
+type (
+	OrderCreated              struct{ OrderID int64 }
+	OrderEdited               struct{ OrderID int64 }
+	OrderCompleted            struct{ OrderID int64 }
+	OrderCancelled            struct{ OrderID int64 }
+	PaymentRecorded           struct{ OrderID, PaymentID int64 }
+	NoteAdded                 struct{ OrderID, NoteID int64 }
+	ProductAdded              struct{ ProductID int64 }
+	ProductUpdated            struct{ ProductID int64 }
+	ProductDeactivated        struct{ ProductID int64 }
+	RepresentativeAdded       struct{ RepresentativeID int64 }
+	RepresentativeDeactivated struct{ RepresentativeID int64 }
+	SettingChanged            struct{ Key string }
+
+	// DataImported is published after bulk changes, such as an import or an
+	// applied workbook, that touch many records at once. Orders holds the
+	// OrderCreated, OrderEdited, OrderCompleted and OrderCancelled events of
+	// the changed orders for subscribers that act on each order.
+	DataImported struct {
+		Source string
+		Orders []Event
+	}
+
+	// DataChanged is published when another machine changed the shared
+	// database, see ChangeWatcher
+	DataChanged struct{}
+)
+
+func (OrderCreated) EventName() string              { return "order.created" }
+func (OrderEdited) EventName() string               { return "order.edited" }
+func (OrderCompleted) EventName() string            { return "order.completed" }
+func (OrderCancelled) EventName() string            { return "order.cancelled" }
+func (PaymentRecorded) EventName() string           { return "payment.recorded" }
+func (NoteAdded) EventName() string                 { return "note.added" }
+func (ProductAdded) EventName() string              { return "product.added" }
+func (ProductUpdated) EventName() string            { return "product.updated" }
+func (ProductDeactivated) EventName() string        { return "product.deactivated" }
+func (RepresentativeAdded) EventName() string       { return "representative.added" }
+func (RepresentativeDeactivated) EventName() string { return "representative.deactivated" }
+func (SettingChanged) EventName() string            { return "setting.changed" }
+func (DataImported) EventName() string              { return "data.imported" }
+func (DataChanged) EventName() string               { return "data.changed" }
+
+// OrderChanged returns the event of saving after over before: OrderCompleted
+// or OrderCancelled when that is what changed, OrderEdited otherwise
+func OrderChanged(before, after Order) Event {
+	switch {
+	case after.Completed && !before.Completed:
+		return OrderCompleted{OrderID: after.ID}
+	case after.Cancelled && !before.Cancelled:
+		return OrderCancelled{OrderID: after.ID}
+	}
+	return OrderEdited{OrderID: after.ID}
+}
+
+// Bus delivers published events to subscribers synchronously, in the order
+// they subscribed
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(Event)
+	order    []int
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[int]func(Event))}
+}
+
+// DefaultBus receives the events of the write functions in this package
+var DefaultBus = NewBus()
+
+// Subscribe calls handler for every published event until the returned
+// function is called
+func (b *Bus) Subscribe(handler func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.order = append(b.order, id)
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+		for i, existing := range b.order {
+			if existing == id {
+				b.order = append(b.order[:i:i], b.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Publish calls every subscriber with e. A panicking subscriber is logged and
+// does not stop the others, since the change has already been committed.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.order))
+	for _, id := range b.order {
+		handlers = append(handlers, b.handlers[id])
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Event handler for %s panicked: %v", e.EventName(), r)
+				}
+			}()
+			handler(e)
+		}()
+	}
+}
+
+// SubscribeTo calls handler only for events of type T
+func SubscribeTo[T Event](b *Bus, handler func(T)) (unsubscribe func()) {
+	return b.Subscribe(func(e Event) {
+		if typed, ok := e.(T); ok {
+			handler(typed)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+// internal/events_test.go
+package internal
+
+import (
+	"testing"
+)
+
+func TestBus_SubscribeAndUnsubscribe(t *testing.T) {
+	bus := NewBus()
+
+	var calls []string
+	unsubscribeFirst := bus.Subscribe(func(e Event) { calls = append(calls, "first "+e.EventName()) })
+	bus.Subscribe(func(e Event) { panic("broken handler") })
+	bus.Subscribe(func(e Event) { calls = append(calls, "last "+e.EventName()) })
+
+	bus.Publish(OrderCreated{OrderID: 1})
+	if len(calls) != 2 || calls[0] != "first order.created" || calls[1] != "last order.created" {
+		t.Fatalf("Unexpected calls: %v", calls)
+	}
+
+	unsubscribeFirst()
+	calls = nil
+	bus.Publish(OrderCompleted{OrderID: 1})
+	if len(calls) != 1 || calls[0] != "last order.completed" {
+		t.Errorf("Unexpected calls after unsubscribe: %v", calls)
+	}
+}
+
+func TestSubscribeTo(t *testing.T) {
+	bus := NewBus()
+
+	var deactivated []int64
+	unsubscribe := SubscribeTo(bus, func(e ProductDeactivated) { deactivated = append(deactivated, e.ProductID) })
+	defer unsubscribe()
+
+	bus.Publish(ProductAdded{ProductID: 1})
+	bus.Publish(ProductDeactivated{ProductID: 2})
+	if len(deactivated) != 1 || deactivated[0] != 2 {
+		t.Errorf("Expected only ProductDeactivated{2}, got %v", deactivated)
+	}
+}
+
+func TestProductChangesPublishEvents(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var events []Event
+	unsubscribe := DefaultBus.Subscribe(func(e Event) { events = append(events, e) })
+	defer unsubscribe()
+
+	id, err := AddProduct(db, "Scone", 8)
+	if err != nil {
+		t.Fatalf("AddProduct failed: %v", err)
+	}
+	if err := DeactivateProduct(db, id); err != nil {
+		t.Fatalf("DeactivateProduct failed: %v", err)
+	}
+	// Failed changes publish nothing
+	if err := DeactivateProduct(db, id+100); err == nil {
+		t.Fatal("Expected error for unknown product")
+	}
+
+	if len(events) != 2 || events[0] != (ProductAdded{ProductID: id}) || events[1] != (ProductDeactivated{ProductID: id}) {
+		t.Errorf("Unexpected events: %v", events)
+	}
+}
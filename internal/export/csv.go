@@ -0,0 +1,54 @@
+// internal/export/csv.go
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// CSVExporter writes one row per order item with the same columns as the
+// Excel format. Amounts are plain decimals so that accounting software can
+// read them without stripping the currency symbol.
+type CSVExporter struct{}
+
+func (CSVExporter) Extension() string { return ".csv" }
+
+func (CSVExporter) Export(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Headers); err != nil {
+		return fmt.Errorf("error writing CSV header: %w", err)
+	}
+
+	for _, r := range rows {
+		record := []string{
+			strconv.FormatInt(r.OrderID, 10),
+			r.RepresentativeName,
+			r.Status,
+			r.CreatedAt.Format("2006-01-02 15:04"),
+			r.ClientName,
+			r.Contact,
+			formatDue(r.DueDate),
+			r.ProductName,
+			strconv.FormatInt(r.Quantity, 10),
+			formatAmount(r.UnitPrice),
+			formatAmount(r.LinePrice),
+			formatAmount(r.TotalPrice),
+			r.Comment,
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("error writing CSV row: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("error writing CSV file: %w", err)
+	}
+	return nil
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,101 @@
+// internal/export/excel.go
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Headers are the column headers of the flat Excel and CSV formats
+var Headers = []string{
+	"Order ID",
+	"Representative",
+	"Status",
+	"Date",
+	"Client Name",
+	"Contact",
+	"Due Date",
+	"Product Name",
+	"Product Quantity",
+	"Product Unit Price",
+	"Product Total",
+	"Total Order Price",
+	"Comment",
+}
+
+// ExcelExporter writes one row per order item to a single Orders sheet
+type ExcelExporter struct{}
+
+func (ExcelExporter) Extension() string { return ".xlsx" }
+
+func (ExcelExporter) Export(w io.Writer, rows []Row) error {
+	// Create a new Excel file
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheetName := "Orders"
+	f.SetSheetName("Sheet1", sheetName)
+
+	// Write headers
+	for i, header := range Headers {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		f.SetCellValue(sheetName, col+"1", header)
+
+		// Set column width based on content
+		f.SetColWidth(sheetName, col, col, 13)
+	}
+
+	// Write data rows
+	rowIndex := 2
+	for _, r := range rows {
+		rowData := []interface{}{
+			r.OrderID,
+			r.RepresentativeName,
+			r.Status,
+			r.CreatedAt.Format("2006-01-02 15:04"),
+			r.ClientName,
+			r.Contact,
+			formatDue(r.DueDate),
+			r.ProductName,
+			r.Quantity,
+			fmt.Sprintf("R%.2f", r.UnitPrice),
+			fmt.Sprintf("R%.2f", r.LinePrice),
+			fmt.Sprintf("R%.2f", r.TotalPrice),
+			r.Comment,
+		}
+
+		for i, value := range rowData {
+			col, _ := excelize.ColumnNumberToName(i + 1)
+			f.SetCellValue(sheetName, fmt.Sprintf("%s%d", col, rowIndex), value)
+		}
+		rowIndex++
+	}
+
+	// Apply styling
+	style, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#E0E0E0"},
+			Pattern: 1,
+		},
+	})
+	if err == nil {
+		// Apply style to header row
+		f.SetRowStyle(sheetName, 1, 1, style)
+	}
+
+	// Auto-filter for all columns
+	lastCol, _ := excelize.ColumnNumberToName(len(Headers))
+	ref := fmt.Sprintf("A1:%s%d", lastCol, rowIndex-1)
+	f.AutoFilter(sheetName, ref, []excelize.AutoFilterOptions{})
+
+	if err := f.Write(w); err != nil {
+		return fmt.Errorf("error saving Excel file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+// internal/export/export.go
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Row is one order item together with its order details. Orders without items
+// produce a single row with empty product fields.
+type Row struct {
+	OrderID            int64
+	RepresentativeName string
+	Status             string
+	CreatedAt          time.Time
+	ClientName         string
+	Contact            string
+	DueDate            time.Time // in the time zone of the business when it has a time of day
+	ProductName        string
+	Quantity           int64
+	UnitPrice          float64
+	LinePrice          float64
+	TotalPrice         float64
+	Comment            string
+}
+
+// Filter restricts which orders are exported. Zero values match everything.
+type Filter struct {
+	// From and To limit the order date to the half-open range [From, To)
+	From time.Time
+	To   time.Time
+
+	// Status is one of internal.Statuses
+	Status string
+
+	// OrderIDs limits the export to the listed orders, e.g. those selected in
+	// the order table
+	OrderIDs []int64
+}
+
+// where returns the SQL condition of the filter on the orders table, aliased
+// o, and its arguments
+func (f Filter) where() (string, []interface{}, error) {
+	var conditions []string
+	var args []interface{}
+	if !f.From.IsZero() {
+		conditions = append(conditions, "datetime(o.created_at) >= ?")
+		args = append(args, internal.SQLTime(f.From))
+	}
+	if !f.To.IsZero() {
+		conditions = append(conditions, "datetime(o.created_at) < ?")
+		args = append(args, internal.SQLTime(f.To))
+	}
+	condition, err := internal.StatusCondition(f.Status)
+	if err != nil {
+		return "", nil, err
+	}
+	if condition != "" {
+		conditions = append(conditions, condition)
+	}
+	if len(f.OrderIDs) > 0 {
+		placeholders := make([]string, len(f.OrderIDs))
+		for i, id := range f.OrderIDs {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		conditions = append(conditions, "o.id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// ParseFilter builds a filter from dates in YYYY-MM-DD form and a status name.
+// Empty values are ignored, both dates are inclusive and "All" matches every status.
+// Days start at midnight in loc, the time zone of the business.
+func ParseFilter(from, to, status string, loc *time.Location) (Filter, error) {
+	var filter Filter
+
+	if from = strings.TrimSpace(from); from != "" {
+		fromDate, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid start date format. Please use YYYY-MM-DD")
+		}
+		filter.From = fromDate
+	}
+	if to = strings.TrimSpace(to); to != "" {
+		toDate, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid end date format. Please use YYYY-MM-DD")
+		}
+		filter.To = toDate.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return filter, fmt.Errorf("End date must not be before start date")
+	}
+
+	status = strings.TrimSpace(status)
+	if status == "" || strings.EqualFold(status, "All") {
+		return filter, nil
+	}
+	for _, s := range internal.Statuses {
+		if strings.EqualFold(s, status) {
+			filter.Status = s
+			return filter, nil
+		}
+	}
+	return filter, fmt.Errorf("unknown status %q, use one of All, %s", status, strings.Join(internal.Statuses, ", "))
+}
+
+// Exporter writes rows in a particular file format
+type Exporter interface {
+	// Extension is the file extension including the dot, e.g. ".csv"
+	Extension() string
+	Export(w io.Writer, rows []Row) error
+}
+
+// Exporters lists the available formats, the first being the default
+var Exporters = []Exporter{
+	ExcelExporter{},
+	CSVExporter{},
+	JSONExporter{},
+	JSONLinesExporter{},
+}
+
+// Extensions returns the file extensions of all formats
+func Extensions() []string {
+	var extensions []string
+	for _, e := range Exporters {
+		extensions = append(extensions, e.Extension())
+	}
+	return extensions
+}
+
+// ForPath picks the exporter matching the file extension of path
+func ForPath(path string) (Exporter, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range Exporters {
+		if e.Extension() == ext {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported export format %q, use one of %s",
+		ext, strings.Join(Extensions(), ", "))
+}
+
+// EnsureExtension appends the default extension when path has no known one
+func EnsureExtension(path string) string {
+	if _, err := ForPath(path); err != nil {
+		return path + Exporters[0].Extension()
+	}
+	return path
+}
+
+// LoadRows returns the order items of the orders matching the filter
+func LoadRows(db *sql.DB, filter Filter) ([]Row, error) {
+	where, args, err := filter.where()
+	if err != nil {
+		return nil, err
+	}
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query orders with joined product and representative information
+	query := `
+        SELECT
+            o.id,
+            r.name as representative_name,
+            o.completed,
+            o.cancelled,
+            o.created_at,
+            o.client_name,
+            o.contact,
+            o.due_date,
+            p.name as product_name,
+            oi.quantity,
+            p.price as product_price,
+            oi.price as item_price,
+            o.total_price,
+            o.comment
+        FROM orders o
+        LEFT JOIN representatives r ON o.representative_id = r.id
+        LEFT JOIN order_items oi ON o.id = oi.order_id
+        LEFT JOIN products p ON oi.product_id = p.id
+        ` + where + `
+        ORDER BY o.created_at DESC, o.id, p.name
+    `
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying orders: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Row
+	for rows.Next() {
+		var (
+			r           Row
+			repName     sql.NullString
+			completed   bool
+			cancelled   bool
+			productName sql.NullString
+			quantity    sql.NullInt64
+			unitPrice   sql.NullFloat64
+			linePrice   sql.NullFloat64
+			comment     sql.NullString
+		)
+
+		err := rows.Scan(
+			&r.OrderID,
+			&repName,
+			&completed,
+			&cancelled,
+			&r.CreatedAt,
+			&r.ClientName,
+			&r.Contact,
+			&r.DueDate,
+			&productName,
+			&quantity,
+			&unitPrice,
+			&linePrice,
+			&r.TotalPrice,
+			&comment,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning row: %w", err)
+		}
+
+		if internal.HasDueTime(r.DueDate) {
+			r.DueDate = r.DueDate.In(hours.Location())
+		}
+		r.RepresentativeName = repName.String
+		r.Status = internal.Order{Completed: completed, Cancelled: cancelled}.Status()
+		r.ProductName = productName.String
+		r.Quantity = quantity.Int64
+		r.UnitPrice = unitPrice.Float64
+		r.LinePrice = linePrice.Float64
+		r.Comment = comment.String
+
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// formatDue formats a due date of a Row with its time of day when it has one
+func formatDue(due time.Time) string {
+	if !internal.HasDueTime(due) {
+		return due.Format("2006-01-02")
+	}
+	return due.Format("2006-01-02 15:04")
+}
+
+// formatDueRFC3339 formats a due date of a Row as RFC 3339 when it has a time
+// of day
+func formatDueRFC3339(due time.Time) string {
+	if !internal.HasDueTime(due) {
+		return due.Format("2006-01-02")
+	}
+	return due.Format(time.RFC3339)
+}
+
+// ExportFile loads the filtered rows and writes them to filePath in the format
+// given by its extension
+func ExportFile(db *sql.DB, filePath string, filter Filter) error {
+	exporter, err := ForPath(filePath)
+	if err != nil {
+		return err
+	}
+	return ExportFileWith(db, filePath, exporter, filter)
+}
+
+// ExportFileWith loads the filtered rows and writes them to filePath using the
+// given exporter regardless of the file extension
+func ExportFileWith(db *sql.DB, filePath string, exporter Exporter, filter Filter) error {
+	rows, err := LoadRows(db, filter)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("error creating export file: %w", err)
+	}
+
+	if err := exporter.Export(file, rows); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,344 @@
+// internal/export/export_test.go
+package export
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+	"github.com/xuri/excelize/v2"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			price REAL NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE representatives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at DATETIME,
+			due_date DATETIME,
+			client_name TEXT,
+			contact TEXT,
+			needs_delivery BOOLEAN,
+			delivery_address TEXT,
+			comment TEXT,
+			completed BOOLEAN,
+			cancelled BOOLEAN DEFAULT false,
+			version INTEGER NOT NULL DEFAULT 1,
+			representative_id INTEGER,
+			total_price REAL
+		);
+		CREATE TABLE order_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER,
+			product_id INTEGER,
+			quantity INTEGER,
+			price REAL
+		);
+		CREATE TABLE settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+		INSERT INTO products (name, price, active) VALUES ('Muffin', 10, true), ('Cake', 25.5, true);
+		INSERT INTO representatives (name, active) VALUES ('Anna', true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test tables: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	insertOrder(t, db, time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC), "Alice", false,
+		[][3]float64{{1, 2, 20}, {2, 1, 25.5}})
+	insertOrder(t, db, time.Date(2024, 5, 20, 14, 30, 0, 0, time.UTC), "Bob", true,
+		[][3]float64{{2, 2, 51}})
+	insertOrder(t, db, time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), "Carol", false, nil)
+	return db
+}
+
+// insertOrder adds an order with items given as {product ID, quantity, price}
+func insertOrder(t *testing.T, db *sql.DB, createdAt time.Time, client string, completed bool, items [][3]float64) {
+	var total float64
+	for _, item := range items {
+		total += item[2]
+	}
+
+	res, err := db.Exec(`
+		INSERT INTO orders (created_at, due_date, client_name, contact, needs_delivery,
+			delivery_address, comment, completed, representative_id, total_price)
+		VALUES (?, ?, ?, '0821234567', false, '', 'note', ?, 1, ?)`,
+		createdAt, time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day()+3, 0, 0, 0, 0, time.UTC),
+		client, completed, total)
+	if err != nil {
+		t.Fatalf("Failed to insert order: %v", err)
+	}
+	orderID, _ := res.LastInsertId()
+
+	for _, item := range items {
+		_, err := db.Exec("INSERT INTO order_items (order_id, product_id, quantity, price) VALUES (?, ?, ?, ?)",
+			orderID, int64(item[0]), int64(item[1]), item[2])
+		if err != nil {
+			t.Fatalf("Failed to insert order item: %v", err)
+		}
+	}
+}
+
+func TestLoadRows_Filter(t *testing.T) {
+	db := setupTestDB(t)
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		clients []string
+	}{
+		{"all", Filter{}, []string{"Carol", "Bob", "Alice", "Alice"}},
+		{"status", Filter{Status: internal.StatusCompleted}, []string{"Bob"}},
+		{"range", Filter{
+			From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		}, []string{"Bob", "Alice", "Alice"}},
+		{"range and status", Filter{
+			From:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			Status: internal.StatusPending,
+		}, []string{"Carol", "Alice", "Alice"}},
+		{"range in another zone", Filter{
+			From: time.Date(2024, 5, 20, 0, 0, 0, 0, time.FixedZone("SAST", 2*3600)),
+			To:   time.Date(2024, 6, 1, 9, 0, 0, 0, time.FixedZone("SAST", 2*3600)),
+		}, []string{"Bob"}},
+		{"selected orders", Filter{OrderIDs: []int64{1, 3}}, []string{"Carol", "Alice", "Alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := LoadRows(db, tt.filter)
+			if err != nil {
+				t.Fatalf("LoadRows failed: %v", err)
+			}
+			var clients []string
+			for _, r := range rows {
+				clients = append(clients, r.ClientName)
+			}
+			if strings.Join(clients, ",") != strings.Join(tt.clients, ",") {
+				t.Errorf("Expected clients %v, got %v", tt.clients, clients)
+			}
+		})
+	}
+}
+
+func TestForPath(t *testing.T) {
+	for path, want := range map[string]string{
+		"orders.xlsx":  ".xlsx",
+		"orders.CSV":   ".csv",
+		"orders.json":  ".json",
+		"orders.jsonl": ".jsonl",
+	} {
+		exporter, err := ForPath(path)
+		if err != nil {
+			t.Fatalf("ForPath(%q) failed: %v", path, err)
+		}
+		if exporter.Extension() != want {
+			t.Errorf("ForPath(%q) = %s, want %s", path, exporter.Extension(), want)
+		}
+	}
+
+	if _, err := ForPath("orders.pdf"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	if got := EnsureExtension("orders"); got != "orders.xlsx" {
+		t.Errorf("EnsureExtension = %s, want orders.xlsx", got)
+	}
+}
+
+func TestCSVExporter(t *testing.T) {
+	db := setupTestDB(t)
+	rows, err := LoadRows(db, Filter{Status: internal.StatusCompleted})
+	if err != nil {
+		t.Fatalf("LoadRows failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (CSVExporter{}).Export(&buf, rows); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and 1 row, got %d records", len(records))
+	}
+
+	expected := []string{"2", "Anna", "Completed", "2024-05-20 14:30", "Bob", "0821234567",
+		"2024-05-23", "Cake", "2", "25.50", "51.00", "51.00", "note"}
+	for i, want := range expected {
+		if records[1][i] != want {
+			t.Errorf("Column %s: expected %q, got %q", Headers[i], want, records[1][i])
+		}
+	}
+}
+
+func TestJSONExporters(t *testing.T) {
+	db := setupTestDB(t)
+	rows, err := LoadRows(db, Filter{})
+	if err != nil {
+		t.Fatalf("LoadRows failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (JSONExporter{}).Export(&buf, rows); err != nil {
+		t.Fatalf("JSON export failed: %v", err)
+	}
+
+	var orders []Order
+	if err := json.Unmarshal(buf.Bytes(), &orders); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if len(orders) != 3 {
+		t.Fatalf("Expected 3 orders, got %d", len(orders))
+	}
+
+	alice := orders[2]
+	if alice.ClientName != "Alice" || len(alice.Items) != 2 || alice.TotalPrice != 45.5 {
+		t.Errorf("Unexpected order: %+v", alice)
+	}
+	if len(orders[0].Items) != 0 {
+		t.Errorf("Expected order without items to have none, got %+v", orders[0].Items)
+	}
+
+	buf.Reset()
+	if err := (JSONLinesExporter{}).Export(&buf, rows); err != nil {
+		t.Fatalf("JSON lines export failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	var first Order
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Failed to decode JSON line: %v", err)
+	}
+	if first.ClientName != "Carol" {
+		t.Errorf("Expected first line for Carol, got %s", first.ClientName)
+	}
+}
+
+func TestExportFile(t *testing.T) {
+	db := setupTestDB(t)
+	dir := t.TempDir()
+
+	for _, ext := range Extensions() {
+		path := filepath.Join(dir, "orders"+ext)
+		if err := ExportFile(db, path, Filter{}); err != nil {
+			t.Fatalf("ExportFile(%s) failed: %v", ext, err)
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("Expected non-empty %s file", ext)
+		}
+	}
+
+	f, err := excelize.OpenFile(filepath.Join(dir, "orders.xlsx"))
+	if err != nil {
+		t.Fatalf("Failed to open Excel file: %v", err)
+	}
+	defer f.Close()
+
+	value, _ := f.GetCellValue("Orders", "J2")
+	if value != "R0.00" {
+		t.Errorf("Expected unit price R0.00 for order without items, got %s", value)
+	}
+	value, _ = f.GetCellValue("Orders", "E4")
+	if value != "Alice" {
+		t.Errorf("Expected Alice in E4, got %s", value)
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	johannesburg := time.FixedZone("SAST", 2*3600)
+	filter, err := ParseFilter("2024-05-01", "2024-05-31", "completed", johannesburg)
+	if err != nil {
+		t.Fatalf("ParseFilter failed: %v", err)
+	}
+	if filter.Status != internal.StatusCompleted {
+		t.Errorf("Expected status %s, got %s", internal.StatusCompleted, filter.Status)
+	}
+	if !filter.To.Equal(time.Date(2024, 5, 31, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected end date to include May 31, got %v", filter.To)
+	}
+
+	if filter, err := ParseFilter("", "", "All", time.UTC); err != nil || !reflect.DeepEqual(filter, Filter{}) {
+		t.Errorf("Expected empty filter, got %+v (%v)", filter, err)
+	}
+	for _, args := range [][3]string{
+		{"01/05/2024", "", ""},
+		{"2024-05-31", "2024-05-01", ""},
+		{"", "", "shipped"},
+	} {
+		if _, err := ParseFilter(args[0], args[1], args[2], time.UTC); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestExporters_DueTime(t *testing.T) {
+	db := setupTestDB(t)
+	hours := internal.DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	value, _ := json.Marshal(hours)
+	if _, err := db.Exec("INSERT INTO settings (key, value) VALUES (?, ?)", internal.SettingBusinessHours, string(value)); err != nil {
+		t.Fatalf("Failed to save business hours: %v", err)
+	}
+	due := time.Date(2024, 5, 23, 8, 30, 0, 0, time.UTC)
+	if _, err := db.Exec("UPDATE orders SET due_date = ? WHERE client_name = 'Bob'", due); err != nil {
+		t.Fatalf("Failed to set due time: %v", err)
+	}
+
+	rows, err := LoadRows(db, Filter{Status: internal.StatusCompleted})
+	if err != nil {
+		t.Fatalf("LoadRows failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (CSVExporter{}).Export(&buf, rows); err != nil {
+		t.Fatalf("CSV export failed: %v", err)
+	}
+	records, _ := csv.NewReader(&buf).ReadAll()
+	if records[1][6] != "2024-05-23 10:30" {
+		t.Errorf("Expected the due time of the business in CSV, got %q", records[1][6])
+	}
+
+	buf.Reset()
+	if err := (JSONExporter{}).Export(&buf, rows); err != nil {
+		t.Fatalf("JSON export failed: %v", err)
+	}
+	var orders []Order
+	if err := json.Unmarshal(buf.Bytes(), &orders); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if orders[0].DueDate != "2024-05-23T10:30:00+02:00" {
+		t.Errorf("Expected an RFC 3339 due time in JSON, got %q", orders[0].DueDate)
+	}
+}
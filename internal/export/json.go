@@ -0,0 +1,98 @@
+// internal/export/json.go
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Order is the JSON representation of an order with its items
+type Order struct {
+	ID             int64       `json:"id"`
+	Representative string      `json:"representative"`
+	Status         string      `json:"status"`
+	CreatedAt      time.Time   `json:"created_at"`
+	ClientName     string      `json:"client_name"`
+	Contact        string      `json:"contact"`
+	DueDate        string      `json:"due_date"`
+	TotalPrice     float64     `json:"total_price"`
+	Comment        string      `json:"comment"`
+	Items          []OrderItem `json:"items"`
+}
+
+// OrderItem is the JSON representation of an order item
+type OrderItem struct {
+	Product   string  `json:"product"`
+	Quantity  int64   `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+}
+
+// Orders groups item rows by order, keeping the order of the rows
+func Orders(rows []Row) []Order {
+	orders := []Order{}
+	index := make(map[int64]int)
+
+	for _, r := range rows {
+		i, ok := index[r.OrderID]
+		if !ok {
+			i = len(orders)
+			index[r.OrderID] = i
+			orders = append(orders, Order{
+				ID:             r.OrderID,
+				Representative: r.RepresentativeName,
+				Status:         r.Status,
+				CreatedAt:      r.CreatedAt,
+				ClientName:     r.ClientName,
+				Contact:        r.Contact,
+				DueDate:        formatDueRFC3339(r.DueDate),
+				TotalPrice:     r.TotalPrice,
+				Comment:        r.Comment,
+				Items:          []OrderItem{},
+			})
+		}
+
+		// Orders without items have a single row without a product
+		if r.ProductName == "" && r.Quantity == 0 {
+			continue
+		}
+		orders[i].Items = append(orders[i].Items, OrderItem{
+			Product:   r.ProductName,
+			Quantity:  r.Quantity,
+			UnitPrice: r.UnitPrice,
+			Total:     r.LinePrice,
+		})
+	}
+	return orders
+}
+
+// JSONExporter writes an array of orders with nested items
+type JSONExporter struct{}
+
+func (JSONExporter) Extension() string { return ".json" }
+
+func (JSONExporter) Export(w io.Writer, rows []Row) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(Orders(rows)); err != nil {
+		return fmt.Errorf("error writing JSON file: %w", err)
+	}
+	return nil
+}
+
+// JSONLinesExporter writes one order object per line
+type JSONLinesExporter struct{}
+
+func (JSONLinesExporter) Extension() string { return ".jsonl" }
+
+func (JSONLinesExporter) Export(w io.Writer, rows []Row) error {
+	encoder := json.NewEncoder(w)
+	for _, order := range Orders(rows) {
+		if err := encoder.Encode(order); err != nil {
+			return fmt.Errorf("error writing JSON line: %w", err)
+		}
+	}
+	return nil
+}
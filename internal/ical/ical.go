@@ -0,0 +1,136 @@
+// internal/ical/ical.go
+
+// Package ical writes open orders as an iCalendar (RFC 5545) feed, so that
+// due orders and deliveries show up in calendar apps.
+package ical
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// ContentType is the media type of the feed
+const ContentType = "text/calendar; charset=utf-8"
+
+// Extension is the file extension of the feed
+const Extension = ".ics"
+
+// maxLineOctets is the longest content line allowed before it must be folded
+const maxLineOctets = 75
+
+// WriteFeed writes the open orders in db to w
+func WriteFeed(db *sql.DB, w io.Writer) error {
+	orders, err := internal.LoadOrders(db)
+	if err != nil {
+		return err
+	}
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		return err
+	}
+	return Write(w, orders, hours, time.Now())
+}
+
+// Write writes a calendar with one event per order. Orders due at a time of
+// day last one time slot; others are all-day events. stamp is when the feed
+// was made.
+func Write(w io.Writer, orders []internal.Order, hours internal.BusinessHours, stamp time.Time) error {
+	b := bufio.NewWriter(w)
+	line := func(name, value string) {
+		fold(b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//OrderFlow Manager//Orders//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("X-WR-CALNAME", "OrderFlow Orders")
+	for _, order := range orders {
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("order-%d@orderflow", order.ID))
+		line("DTSTAMP", formatUTC(stamp))
+		// Calendar apps replace an event when its sequence increases
+		line("SEQUENCE", fmt.Sprint(order.Version))
+		if internal.HasDueTime(order.DueDate) {
+			line("DTSTART", formatUTC(order.DueDate))
+			line("DTEND", formatUTC(order.DueDate.Add(time.Duration(hours.SlotMinutes)*time.Minute)))
+		} else {
+			day := hours.DueDay(order.DueDate)
+			fold(b, "DTSTART;VALUE=DATE:"+day.Format("20060102"))
+			fold(b, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+		}
+		line("SUMMARY", escape(summary(order)))
+		line("DESCRIPTION", escape(description(order)))
+		if order.NeedsDelivery && order.DeliveryAddress != "" {
+			line("LOCATION", escape(order.DeliveryAddress))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return b.Flush()
+}
+
+func summary(order internal.Order) string {
+	if order.NeedsDelivery {
+		return "Deliver order " + fmt.Sprint(order.ID) + " to " + order.ClientName
+	}
+	return "Order " + fmt.Sprint(order.ID) + " for " + order.ClientName
+}
+
+func description(order internal.Order) string {
+	var lines []string
+	for _, item := range order.Items {
+		lines = append(lines, fmt.Sprintf("%d x %s", item.Quantity, item.ProductName))
+	}
+	lines = append(lines, fmt.Sprintf("Total: R%.2f", order.TotalPrice))
+	if order.Contact != "" {
+		lines = append(lines, "Contact: "+order.Contact)
+	}
+	if order.RepresentativeName != "" {
+		lines = append(lines, "Representative: "+order.RepresentativeName)
+	}
+	if order.Comment != "" {
+		lines = append(lines, "Comment: "+order.Comment)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escape escapes a TEXT value
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+// fold writes a content line, folding it into lines of at most 75 octets
+// without splitting a UTF-8 character. Continuation lines start with a space.
+func fold(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the length of the next line
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(line + "\r\n")
+}
@@ -0,0 +1,124 @@
+// internal/ical/ical_test.go
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+)
+
+func TestWrite(t *testing.T) {
+	hours := internal.DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	orders := []internal.Order{
+		{
+			ID:         1,
+			ClientName: "Alice",
+			DueDate:    time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC),
+			Items:      []internal.OrderItem{{ProductName: "Muffin", Quantity: 4}},
+			TotalPrice: 50,
+			Comment:    "No nuts, please; thanks",
+			Version:    3,
+		},
+		{
+			ID:              2,
+			ClientName:      "Bob",
+			DueDate:         time.Date(2024, 6, 11, 9, 30, 0, 0, hours.Location()),
+			NeedsDelivery:   true,
+			DeliveryAddress: "12 Long Street, Cape Town, a very long address that needs folding",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, orders, hours, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	feed := buf.String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(feed, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("Line longer than %d octets: %q", maxLineOctets, line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(feed, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:order-1@orderflow\r\n",
+		"DTSTAMP:20240601T120000Z\r\n",
+		"SEQUENCE:3\r\n",
+		"DTSTART;VALUE=DATE:20240610\r\nDTEND;VALUE=DATE:20240611\r\n",
+		"SUMMARY:Order 1 for Alice\r\n",
+		`DESCRIPTION:4 x Muffin\nTotal: R50.00\nComment: No nuts\, please\; thanks` + "\r\n",
+		// 09:30 in Johannesburg is 07:30 UTC, and slots are 30 minutes
+		"DTSTART:20240611T073000Z\r\nDTEND:20240611T080000Z\r\n",
+		"SUMMARY:Deliver order 2 to Bob\r\n",
+		`LOCATION:12 Long Street\, Cape Town\, a very long address that needs folding` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("Expected feed to contain %q, got\n%s", want, unfolded)
+		}
+	}
+	if strings.Count(feed, "BEGIN:VEVENT") != 2 {
+		t.Errorf("Expected 2 events, got\n%s", feed)
+	}
+}
+
+func TestFold_KeepsCharacters(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	line := "SUMMARY:" + strings.Repeat("é", 60)
+	fold(w, line)
+	w.Flush()
+
+	for _, part := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(part) > maxLineOctets || !utf8.ValidString(part) {
+			t.Errorf("Unexpected folded line %q", part)
+		}
+	}
+	if got := strings.ReplaceAll(buf.String(), "\r\n ", ""); got != line+"\r\n" {
+		t.Errorf("Expected unfolding to restore the line, got %q", got)
+	}
+}
+
+func TestWriteFeed(t *testing.T) {
+	database, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer database.Close()
+	if err := db.CreateSchema(database); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	if _, err := database.Exec("INSERT INTO representatives (name, active) VALUES ('Anna', true)"); err != nil {
+		t.Fatalf("Failed to insert representative: %v", err)
+	}
+	due := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	for _, client := range []string{"Alice", "Done"} {
+		if _, err := internal.CreateOrder(database, internal.Order{ClientName: client, DueDate: due, RepresentativeID: 1}); err != nil {
+			t.Fatalf("CreateOrder failed: %v", err)
+		}
+	}
+	if err := internal.CompleteOrder(database, 2); err != nil {
+		t.Fatalf("CompleteOrder failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteFeed(database, &buf); err != nil {
+		t.Fatalf("WriteFeed failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Alice") || strings.Contains(buf.String(), "Done") {
+		t.Errorf("Expected only the open order, got\n%s", buf.String())
+	}
+}
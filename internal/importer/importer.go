@@ -0,0 +1,510 @@
+// internal/importer/importer.go
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Kind is the type of record a file is imported as
+type Kind string
+
+const (
+	Products        Kind = "Products"
+	Representatives Kind = "Representatives"
+	Orders          Kind = "Orders"
+)
+
+// Kinds lists the supported import kinds in display order
+var Kinds = []Kind{Products, Representatives, Orders}
+
+// Field names that columns can be mapped to
+const (
+	FieldName            = "Name"
+	FieldPrice           = "Price"
+	FieldActive          = "Active"
+	FieldOrderRef        = "Order Reference"
+	FieldClientName      = "Client Name"
+	FieldContact         = "Contact"
+	FieldRepresentative  = "Representative"
+	FieldDueDate         = "Due Date"
+	FieldProduct         = "Product"
+	FieldQuantity        = "Quantity"
+	FieldLinePrice       = "Line Price"
+	FieldComment         = "Comment"
+	FieldNeedsDelivery   = "Needs Delivery"
+	FieldDeliveryAddress = "Delivery Address"
+)
+
+// Field describes a target column of an import
+type Field struct {
+	Name     string
+	Required bool
+
+	// Aliases are alternative header names recognised by GuessMapping
+	Aliases []string
+}
+
+// Fields returns the fields that can be mapped for a kind
+func Fields(kind Kind) []Field {
+	switch kind {
+	case Products:
+		return []Field{
+			{Name: FieldName, Required: true, Aliases: []string{"product", "product name"}},
+			{Name: FieldPrice, Required: true, Aliases: []string{"unit price", "product price"}},
+			{Name: FieldActive},
+		}
+	case Representatives:
+		return []Field{
+			{Name: FieldName, Required: true, Aliases: []string{"representative", "rep"}},
+			{Name: FieldActive},
+		}
+	case Orders:
+		return []Field{
+			{Name: FieldOrderRef, Aliases: []string{"order id", "order", "ref", "reference"}},
+			{Name: FieldClientName, Required: true, Aliases: []string{"client", "customer"}},
+			{Name: FieldContact, Aliases: []string{"phone", "email"}},
+			{Name: FieldRepresentative, Aliases: []string{"rep"}},
+			{Name: FieldDueDate, Required: true, Aliases: []string{"due"}},
+			{Name: FieldProduct, Required: true, Aliases: []string{"product name"}},
+			{Name: FieldQuantity, Required: true, Aliases: []string{"qty", "product quantity"}},
+			{Name: FieldLinePrice, Aliases: []string{"product total", "item price"}},
+			{Name: FieldComment, Aliases: []string{"comments", "notes"}},
+			{Name: FieldNeedsDelivery, Aliases: []string{"delivery"}},
+			{Name: FieldDeliveryAddress, Aliases: []string{"address"}},
+		}
+	default:
+		return nil
+	}
+}
+
+// Mapping maps a field name to a column index in the table. Unmapped fields are absent.
+type Mapping map[string]int
+
+// GuessMapping matches table headers to fields by name or alias, ignoring case
+func GuessMapping(kind Kind, headers []string) Mapping {
+	mapping := make(Mapping)
+	for _, field := range Fields(kind) {
+		names := append([]string{field.Name}, field.Aliases...)
+	headers:
+		for col, header := range headers {
+			for _, name := range names {
+				if strings.EqualFold(strings.TrimSpace(header), name) {
+					mapping[field.Name] = col
+					break headers
+				}
+			}
+		}
+	}
+	return mapping
+}
+
+// RowError describes a problem with one row of the file
+type RowError struct {
+	Line    int
+	Field   string
+	Message string
+}
+
+func (e RowError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d, %s: %s", e.Line, e.Field, e.Message)
+}
+
+// Plan is the validated result of reading a table, ready to be committed
+type Plan struct {
+	Kind            Kind
+	Products        []internal.Product
+	Representatives []internal.Representative
+	Orders          []internal.Order
+	Errors          []RowError
+}
+
+// Valid reports whether the plan can be committed
+func (p *Plan) Valid() bool {
+	return len(p.Errors) == 0
+}
+
+// Count returns the number of records that will be imported
+func (p *Plan) Count() int {
+	return len(p.Products) + len(p.Representatives) + len(p.Orders)
+}
+
+// Validate parses every row of the table using the mapping and collects all
+// errors, so that the whole file can be previewed before anything is written.
+func Validate(db *sql.DB, kind Kind, table Table, mapping Mapping) (*Plan, error) {
+	plan := &Plan{Kind: kind}
+
+	for _, field := range Fields(kind) {
+		if _, ok := mapping[field.Name]; field.Required && !ok {
+			plan.Errors = append(plan.Errors, RowError{
+				Line:    1,
+				Field:   field.Name,
+				Message: "required field is not mapped to a column",
+			})
+		}
+	}
+	if !plan.Valid() {
+		return plan, nil
+	}
+
+	switch kind {
+	case Products:
+		validateProducts(plan, table, mapping)
+	case Representatives:
+		validateRepresentatives(plan, table, mapping)
+	case Orders:
+		if err := validateOrders(db, plan, table, mapping); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown import kind %q", kind)
+	}
+	return plan, nil
+}
+
+// rowReader reads mapped fields from one row and records errors against it
+type rowReader struct {
+	plan    *Plan
+	table   Table
+	mapping Mapping
+	row     int
+}
+
+func (r rowReader) line() int {
+	if r.row < len(r.table.Lines) {
+		return r.table.Lines[r.row]
+	}
+	return r.row + 2
+}
+
+func (r rowReader) fail(field, format string, args ...interface{}) {
+	r.plan.Errors = append(r.plan.Errors, RowError{
+		Line:    r.line(),
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r rowReader) text(field string) string {
+	col, ok := r.mapping[field]
+	if !ok {
+		return ""
+	}
+	return r.table.Cell(r.row, col)
+}
+
+func (r rowReader) required(field string) string {
+	value := r.text(field)
+	if value == "" {
+		r.fail(field, "value is required")
+	}
+	return value
+}
+
+func (r rowReader) amount(field string, required bool) float64 {
+	value := r.text(field)
+	if value == "" {
+		if required {
+			r.fail(field, "value is required")
+		}
+		return 0
+	}
+	amount, err := ParseAmount(value)
+	if err != nil {
+		r.fail(field, "%q is not a valid amount", value)
+	}
+	return amount
+}
+
+func (r rowReader) boolean(field string, fallback bool) bool {
+	value := r.text(field)
+	if value == "" {
+		return fallback
+	}
+	b, err := ParseBool(value)
+	if err != nil {
+		r.fail(field, "%q is not yes or no", value)
+	}
+	return b
+}
+
+func validateProducts(plan *Plan, table Table, mapping Mapping) {
+	for row := range table.Rows {
+		r := rowReader{plan: plan, table: table, mapping: mapping, row: row}
+		p := internal.Product{
+			Name:   r.required(FieldName),
+			Price:  r.amount(FieldPrice, true),
+			Active: r.boolean(FieldActive, true),
+		}
+		if p.Price < 0 {
+			r.fail(FieldPrice, "price cannot be negative")
+		}
+		plan.Products = append(plan.Products, p)
+	}
+}
+
+func validateRepresentatives(plan *Plan, table Table, mapping Mapping) {
+	for row := range table.Rows {
+		r := rowReader{plan: plan, table: table, mapping: mapping, row: row}
+		plan.Representatives = append(plan.Representatives, internal.Representative{
+			Name:   r.required(FieldName),
+			Active: r.boolean(FieldActive, true),
+		})
+	}
+}
+
+func validateOrders(db *sql.DB, plan *Plan, table Table, mapping Mapping) error {
+	products, err := internal.LoadProducts(db)
+	if err != nil {
+		return fmt.Errorf("error loading products: %w", err)
+	}
+	representatives, err := internal.LoadRepresentatives(db)
+	if err != nil {
+		return fmt.Errorf("error loading representatives: %w", err)
+	}
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		return err
+	}
+
+	productsByName := make(map[string]internal.Product, len(products))
+	for _, p := range products {
+		productsByName[strings.ToLower(p.Name)] = p
+	}
+	repsByName := make(map[string]internal.Representative, len(representatives))
+	for _, rep := range representatives {
+		repsByName[strings.ToLower(rep.Name)] = rep
+	}
+
+	// Rows sharing an order reference become items of the same order
+	byRef := make(map[string]int)
+
+	for row := range table.Rows {
+		r := rowReader{plan: plan, table: table, mapping: mapping, row: row}
+
+		var item internal.OrderItem
+		productName := r.required(FieldProduct)
+		if product, ok := productsByName[strings.ToLower(productName)]; ok {
+			item.ProductID = product.ID
+			item.ProductName = product.Name
+			item.Price = product.Price
+		} else if productName != "" {
+			r.fail(FieldProduct, "no active product named %q", productName)
+		}
+
+		quantityText := r.required(FieldQuantity)
+		if quantityText != "" {
+			quantity, err := strconv.Atoi(quantityText)
+			if err != nil || quantity <= 0 {
+				r.fail(FieldQuantity, "%q is not a positive whole number", quantityText)
+			}
+			item.Quantity = quantity
+		}
+		item.Price *= float64(item.Quantity)
+		if _, ok := mapping[FieldLinePrice]; ok && r.text(FieldLinePrice) != "" {
+			item.Price = r.amount(FieldLinePrice, false)
+		}
+
+		ref := r.text(FieldOrderRef)
+		if i, ok := byRef[ref]; ok && ref != "" {
+			plan.Orders[i].Items = append(plan.Orders[i].Items, item)
+			plan.Orders[i].TotalPrice += item.Price
+			continue
+		}
+
+		order := internal.Order{
+			ClientName:      r.required(FieldClientName),
+			Contact:         r.text(FieldContact),
+			Comment:         r.text(FieldComment),
+			NeedsDelivery:   r.boolean(FieldNeedsDelivery, false),
+			DeliveryAddress: r.text(FieldDeliveryAddress),
+			Items:           []internal.OrderItem{item},
+			TotalPrice:      item.Price,
+		}
+
+		if dueText := r.required(FieldDueDate); dueText != "" {
+			due, err := ParseDate(dueText, hours.Location())
+			if err != nil {
+				r.fail(FieldDueDate, "%q is not a date, use YYYY-MM-DD", dueText)
+			}
+			order.DueDate = due
+		}
+
+		if repName := r.text(FieldRepresentative); repName != "" {
+			rep, ok := repsByName[strings.ToLower(repName)]
+			if !ok {
+				r.fail(FieldRepresentative, "no active representative named %q", repName)
+			}
+			order.RepresentativeID = rep.ID
+			order.RepresentativeName = rep.Name
+		}
+
+		if ref != "" {
+			byRef[ref] = len(plan.Orders)
+		}
+		plan.Orders = append(plan.Orders, order)
+	}
+	return nil
+}
+
+// Commit writes every record of a valid plan in a single transaction
+func Commit(db *sql.DB, plan *Plan) error {
+	if !plan.Valid() {
+		return fmt.Errorf("import has %d errors, nothing was imported", len(plan.Errors))
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, p := range plan.Products {
+		result, err := tx.Exec("INSERT INTO products (name, price, active) VALUES (?, ?, ?)",
+			p.Name, p.Price, p.Active)
+		if err != nil {
+			return fmt.Errorf("error importing product %q: %w", p.Name, err)
+		}
+		if p.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		if err := internal.RecordAudit(tx, internal.EntityProduct, p.ID, internal.ActionCreate, nil, p); err != nil {
+			return err
+		}
+	}
+
+	for _, r := range plan.Representatives {
+		result, err := tx.Exec("INSERT INTO representatives (name, active) VALUES (?, ?)",
+			r.Name, r.Active)
+		if err != nil {
+			return fmt.Errorf("error importing representative %q: %w", r.Name, err)
+		}
+		if r.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+		if err := internal.RecordAudit(tx, internal.EntityRepresentative, r.ID, internal.ActionCreate, nil, r); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	var created []internal.Event
+	for _, o := range plan.Orders {
+		result, err := tx.Exec(`
+            INSERT INTO orders (
+                created_at, due_date, client_name, contact,
+                representative_id, needs_delivery, delivery_address,
+                comment, completed, total_price
+            ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			now, o.DueDate, o.ClientName, o.Contact,
+			o.RepresentativeID, o.NeedsDelivery, o.DeliveryAddress,
+			o.Comment, false, o.TotalPrice,
+		)
+		if err != nil {
+			return fmt.Errorf("error importing order for %q: %w", o.ClientName, err)
+		}
+
+		orderID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for _, item := range o.Items {
+			_, err = tx.Exec(`
+                INSERT INTO order_items (order_id, product_id, quantity, price)
+                VALUES (?, ?, ?, ?)`,
+				orderID, item.ProductID, item.Quantity, item.Price)
+			if err != nil {
+				return fmt.Errorf("error importing items for %q: %w", o.ClientName, err)
+			}
+		}
+
+		o.ID, o.CreatedAt = orderID, now
+		if err := internal.RecordAudit(tx, internal.EntityOrder, orderID, internal.ActionCreate, nil, o); err != nil {
+			return err
+		}
+		created = append(created, internal.OrderCreated{OrderID: orderID})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	internal.DefaultBus.Publish(internal.DataImported{Source: "import", Orders: created})
+	return nil
+}
+
+// Amounts with a comma: either a decimal comma, as in "25,50", or commas
+// grouping thousands, as in "1,234.50"
+var (
+	decimalCommaPattern = regexp.MustCompile(`^-?\d*,\d{1,2}$`)
+	thousandsPattern    = regexp.MustCompile(`^-?\d{1,3}(,\d{3})+(\.\d*)?$`)
+)
+
+// ParseAmount parses a price, accepting the "R25.50" format used by the Excel
+// export as well as "R25,50". A comma is a decimal separator when it is the
+// only one and is followed by one or two digits, and separates thousands
+// when it groups digits in threes; other amounts with commas are rejected
+// rather than guessed.
+func ParseAmount(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	value = strings.TrimPrefix(value, "R")
+	value = strings.ReplaceAll(value, " ", "")
+	if strings.Contains(value, ",") {
+		switch {
+		case decimalCommaPattern.MatchString(value):
+			value = strings.Replace(value, ",", ".", 1)
+		case thousandsPattern.MatchString(value):
+			value = strings.ReplaceAll(value, ",", "")
+		default:
+			return 0, fmt.Errorf("ambiguous amount %q, write it as 1234.50 or 1234,50", value)
+		}
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// ParseBool parses yes/no style spreadsheet values
+func ParseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "y", "yes", "true", "active":
+		return true, nil
+	case "0", "n", "no", "false", "inactive":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", value)
+}
+
+// dateLayouts are the date formats accepted in imported files
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+	"02/01/2006",
+	"2 Jan 2006",
+}
+
+// ParseDate parses a due date in one of the common spreadsheet formats. A
+// time of day is read as the wall-clock time in loc, the time zone of the
+// business; dates without a time stay at midnight UTC, as they are stored.
+func ParseDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, strings.TrimSpace(value), loc)
+		if err != nil {
+			continue
+		}
+		if !internal.HasDueTime(t) {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
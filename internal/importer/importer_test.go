@@ -0,0 +1,320 @@
+// internal/importer/importer_test.go
+package importer
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+	"github.com/xuri/excelize/v2"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			price REAL NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE representatives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at DATETIME,
+			due_date DATETIME,
+			client_name TEXT,
+			contact TEXT,
+			needs_delivery BOOLEAN,
+			delivery_address TEXT,
+			comment TEXT,
+			completed BOOLEAN,
+			cancelled BOOLEAN DEFAULT false,
+			version INTEGER NOT NULL DEFAULT 1,
+			representative_id INTEGER,
+			total_price REAL
+		);
+		CREATE TABLE order_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER,
+			product_id INTEGER,
+			quantity INTEGER,
+			price REAL
+		);
+		CREATE TABLE audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			entity TEXT NOT NULL,
+			entity_id INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			before_json TEXT,
+			after_json TEXT,
+			user TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test tables: %v", err)
+	}
+	return db
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	return path
+}
+
+func countRows(t *testing.T, db *sql.DB, table string) int {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+		t.Fatalf("Failed to count %s: %v", table, err)
+	}
+	return count
+}
+
+func TestImportProductsFromCSV(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	path := writeFile(t, "products.csv", "Product Name,Unit Price,Active\nMuffin,R12.50,yes\nCake,\"1,250.00\",no\n\n")
+
+	table, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if len(table.Rows) != 2 {
+		t.Fatalf("Expected blank line to be skipped, got %d rows", len(table.Rows))
+	}
+
+	mapping := GuessMapping(Products, table.Headers)
+	if mapping[FieldName] != 0 || mapping[FieldPrice] != 1 || mapping[FieldActive] != 2 {
+		t.Fatalf("Unexpected mapping: %v", mapping)
+	}
+
+	plan, err := Validate(db, Products, table, mapping)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !plan.Valid() {
+		t.Fatalf("Expected valid plan, got errors: %v", plan.Errors)
+	}
+	if plan.Products[1].Price != 1250 || plan.Products[1].Active {
+		t.Errorf("Unexpected parsed product: %+v", plan.Products[1])
+	}
+
+	if err := Commit(db, plan); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if n := countRows(t, db, "products"); n != 2 {
+		t.Errorf("Expected 2 products, got %d", n)
+	}
+}
+
+func TestValidateReportsRowErrors(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	path := writeFile(t, "products.csv", "Name,Price\nMuffin,abc\n,10\n")
+	table, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	plan, err := Validate(db, Products, table, GuessMapping(Products, table.Headers))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if len(plan.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", plan.Errors)
+	}
+	if plan.Errors[0].Line != 2 || plan.Errors[0].Field != FieldPrice {
+		t.Errorf("Unexpected first error: %v", plan.Errors[0])
+	}
+	if plan.Errors[1].Line != 3 || plan.Errors[1].Field != FieldName {
+		t.Errorf("Unexpected second error: %v", plan.Errors[1])
+	}
+
+	if err := Commit(db, plan); err == nil {
+		t.Error("Expected commit of invalid plan to fail")
+	}
+	if n := countRows(t, db, "products"); n != 0 {
+		t.Errorf("Expected nothing to be imported, got %d products", n)
+	}
+}
+
+func TestImportOrdersFromExcel(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		INSERT INTO products (name, price) VALUES ('Muffin', 10), ('Cake', 25);
+		INSERT INTO representatives (name) VALUES ('Anna');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	f := excelize.NewFile()
+	rows := [][]interface{}{
+		{"Order", "Client", "Rep", "Due Date", "Product", "Qty"},
+		{"A1", "Alice", "Anna", "2024-06-01", "Muffin", 3},
+		{"A1", "", "", "", "cake", 1},
+		{"B2", "Bob", "", "2024-06-02", "Cake", 2},
+	}
+	for i, row := range rows {
+		f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", i+1), &row)
+	}
+	path := filepath.Join(t.TempDir(), "orders.xlsx")
+	if err := f.SaveAs(path); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+
+	table, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	plan, err := Validate(db, Orders, table, GuessMapping(Orders, table.Headers))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !plan.Valid() {
+		t.Fatalf("Expected valid plan, got errors: %v", plan.Errors)
+	}
+	if len(plan.Orders) != 2 {
+		t.Fatalf("Expected rows to be grouped into 2 orders, got %d", len(plan.Orders))
+	}
+	if plan.Orders[0].TotalPrice != 55 || len(plan.Orders[0].Items) != 2 {
+		t.Errorf("Unexpected first order: %+v", plan.Orders[0])
+	}
+
+	var imported []internal.DataImported
+	unsubscribe := internal.SubscribeTo(internal.DefaultBus, func(e internal.DataImported) {
+		imported = append(imported, e)
+	})
+	defer unsubscribe()
+
+	if err := Commit(db, plan); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	expected := []internal.Event{internal.OrderCreated{OrderID: 1}, internal.OrderCreated{OrderID: 2}}
+	if len(imported) != 1 || fmt.Sprint(imported[0].Orders) != fmt.Sprint(expected) {
+		t.Errorf("Expected one DataImported event with %v, got %+v", expected, imported)
+	}
+	if n := countRows(t, db, "orders"); n != 2 {
+		t.Errorf("Expected 2 orders, got %d", n)
+	}
+	if n := countRows(t, db, "order_items"); n != 3 {
+		t.Errorf("Expected 3 order items, got %d", n)
+	}
+}
+
+func TestValidateOrdersUnknownProduct(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	path := writeFile(t, "orders.csv", "Client Name,Due Date,Product,Quantity\nAlice,tomorrow,Scone,0\n")
+	table, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	plan, err := Validate(db, Orders, table, GuessMapping(Orders, table.Headers))
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	fields := map[string]bool{}
+	for _, e := range plan.Errors {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{FieldProduct, FieldQuantity, FieldDueDate} {
+		if !fields[field] {
+			t.Errorf("Expected an error for %s, got %v", field, plan.Errors)
+		}
+	}
+}
+
+func TestReadFile_UnsupportedType(t *testing.T) {
+	if _, err := ReadFile("orders.txt"); err == nil {
+		t.Error("Expected error for unsupported file type")
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected float64
+	}{
+		{"25.50", 25.5},
+		{"R25.50", 25.5},
+		{"R25,50", 25.5},
+		{"0,5", 0.5},
+		{"R1,234.50", 1234.5},
+		{"1,234", 1234},
+		{"R 1 234,50", 1234.5},
+	}
+	for _, tt := range tests {
+		if amount, err := ParseAmount(tt.value); err != nil || amount != tt.expected {
+			t.Errorf("ParseAmount(%q) = %v, %v; expected %v", tt.value, amount, err, tt.expected)
+		}
+	}
+
+	for _, value := range []string{"1.234,50", "12,345,6", "1,2345", "abc"} {
+		if amount, err := ParseAmount(value); err == nil {
+			t.Errorf("Expected ParseAmount(%q) to fail, got %v", value, amount)
+		}
+	}
+}
+
+func TestValidateOrders_DueTimeInBusinessTimeZone(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if _, err := db.Exec("INSERT INTO products (name, price) VALUES ('Muffin', 10)"); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	hours := internal.DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	if err := internal.SaveBusinessHours(db, hours); err != nil {
+		t.Fatalf("SaveBusinessHours failed: %v", err)
+	}
+
+	path := writeFile(t, "orders.csv", "Client Name,Due Date,Product,Quantity\n"+
+		"Alice,2024-06-01 09:00,Muffin,2\nBob,2024-06-02,Muffin,1\n")
+	table, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	plan, err := Validate(db, Orders, table, GuessMapping(Orders, table.Headers))
+	if err != nil || !plan.Valid() {
+		t.Fatalf("Expected a valid plan, got %v (%v)", plan, err)
+	}
+
+	if due := plan.Orders[0].DueDate; !due.Equal(time.Date(2024, 6, 1, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 09:00 in Johannesburg, got %v", due)
+	}
+	if due := plan.Orders[1].DueDate; due != time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Expected a date without a time at midnight UTC, got %v", due)
+	}
+}
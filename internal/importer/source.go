@@ -0,0 +1,101 @@
+// internal/importer/source.go
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Table is the raw content of an imported file. The first row of the file
+// becomes the headers.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+
+	// Lines holds the 1-based line in the file of each row, for error messages
+	Lines []int
+}
+
+// Cell returns the trimmed value at the given row and column, or "" when the
+// column is not mapped or the row is shorter than the header.
+func (t Table) Cell(row, col int) string {
+	if col < 0 || row < 0 || row >= len(t.Rows) || col >= len(t.Rows[row]) {
+		return ""
+	}
+	return strings.TrimSpace(t.Rows[row][col])
+}
+
+// ReadFile reads an .xlsx or .csv file into a table
+func ReadFile(path string) (Table, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx":
+		return readExcel(path)
+	case ".csv":
+		return readCSV(path)
+	default:
+		return Table{}, fmt.Errorf("unsupported file type %q, expected .xlsx or .csv", filepath.Ext(path))
+	}
+}
+
+func readExcel(path string) (Table, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return Table{}, fmt.Errorf("error opening Excel file: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return Table{}, fmt.Errorf("workbook has no sheets")
+	}
+
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return Table{}, fmt.Errorf("error reading sheet %s: %w", sheets[0], err)
+	}
+	return newTable(rows)
+}
+
+func readCSV(path string) (Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Table{}, fmt.Errorf("error opening CSV file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // allow ragged rows, missing cells are empty
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return Table{}, fmt.Errorf("error reading CSV file: %w", err)
+	}
+	return newTable(rows)
+}
+
+func newTable(rows [][]string) (Table, error) {
+	if len(rows) == 0 {
+		return Table{}, fmt.Errorf("file is empty")
+	}
+
+	t := Table{Headers: make([]string, len(rows[0]))}
+	for i, h := range rows[0] {
+		t.Headers[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+	}
+
+	// Skip completely blank rows, which spreadsheets often leave at the end
+	for i, row := range rows[1:] {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		t.Rows = append(t.Rows, row)
+		t.Lines = append(t.Lines, i+2)
+	}
+	return t, nil
+}
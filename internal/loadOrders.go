@@ -3,6 +3,8 @@ package internal
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"time"
 )
 
@@ -26,64 +28,190 @@ type Order struct {
 	DeliveryAddress    string
 	Comment            string
 	Completed          bool
+	Cancelled          bool
 	TotalPrice         float64
 	Items              []OrderItem
+
+	// Version increases with every change to the order, so that an edit can
+	// tell whether the order changed since it was loaded
+	Version int64
 }
 
+// ErrNotFound is returned when a record with the requested ID does not exist
+var ErrNotFound = errors.New("not found")
+
+// LoadOrders returns the pending orders, newest first
 func LoadOrders(db *sql.DB) ([]Order, error) {
-	rows, err := db.Query(`
-        SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact,
-               o.representative_id, r.name, o.needs_delivery, o.delivery_address,
-               o.comment, o.completed, o.total_price
+	return queryOrders(db, "WHERE "+statusConditions[StatusPending])
+}
+
+// LoadAllOrders returns pending, completed and cancelled orders, newest first
+func LoadAllOrders(db *sql.DB) ([]Order, error) {
+	return queryOrders(db, "")
+}
+
+// LoadOrdersByStatus returns the orders with status, newest first. An empty
+// status matches every order.
+func LoadOrdersByStatus(db *sql.DB, status string) ([]Order, error) {
+	condition, err := StatusCondition(status)
+	if err != nil {
+		return nil, err
+	}
+	if condition == "" {
+		return queryOrders(db, "")
+	}
+	return queryOrders(db, "WHERE "+condition)
+}
+
+// statusConditions are the SQL conditions matching each Order.Status
+var statusConditions = map[string]string{
+	StatusPending:   "o.completed = false AND o.cancelled = false",
+	StatusCompleted: "o.completed = true",
+	StatusCancelled: "o.completed = false AND o.cancelled = true",
+}
+
+// StatusCondition returns the SQL condition on the orders table, aliased o,
+// that matches orders with status. An empty status matches every order.
+func StatusCondition(status string) (string, error) {
+	if status == "" {
+		return "", nil
+	}
+	condition, ok := statusConditions[status]
+	if !ok {
+		return "", fmt.Errorf("unknown order status %q", status)
+	}
+	return condition, nil
+}
+
+// SQLTime formats t the way SQLite's datetime() normalises stored times, in
+// UTC, so that times saved with different offsets compare correctly in
+// conditions such as datetime(o.created_at) >= ?
+func SQLTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+// LoadOrderPage returns limit orders with status, newest first, skipping the
+// first offset, together with the number of orders with status. An empty
+// status matches every order. Only the items of the returned orders are
+// loaded.
+func LoadOrderPage(db *sql.DB, status string, limit, offset int) ([]Order, int, error) {
+	condition, err := StatusCondition(status)
+	if err != nil {
+		return nil, 0, err
+	}
+	var where string
+	if condition != "" {
+		where = "WHERE " + condition
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*) FROM orders o " + where).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("error counting orders: %w", err)
+	}
+	orders, err := selectOrders(db, where, "LIMIT ? OFFSET ?", limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// LoadOrder returns a single order with its items
+func LoadOrder(db *sql.DB, id int64) (Order, error) {
+	return loadOrder(db, id)
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func loadOrder(q queryer, id int64) (Order, error) {
+	orders, err := queryOrders(q, "WHERE o.id = ?", id)
+	if err != nil {
+		return Order{}, err
+	}
+	if len(orders) == 0 {
+		return Order{}, fmt.Errorf("order %d %w", id, ErrNotFound)
+	}
+	return orders[0], nil
+}
+
+func queryOrders(q queryer, where string, args ...interface{}) ([]Order, error) {
+	return selectOrders(q, where, "", args...)
+}
+
+// selectOrders loads the orders matching where, newest first, with limit
+// appended after the ordering
+func selectOrders(q queryer, where, limit string, args ...interface{}) ([]Order, error) {
+	selected := `
         FROM orders o
         LEFT JOIN representatives r ON o.representative_id = r.id
-        WHERE o.completed = false
-        ORDER BY o.created_at DESC
-    `)
+        ` + where + `
+        ORDER BY o.created_at DESC, o.id DESC
+        ` + limit
+	rows, err := q.Query(`
+        SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact,
+               o.representative_id, r.name, o.needs_delivery, o.delivery_address,
+               o.comment, o.completed, o.cancelled, o.total_price, o.version
+        `+selected, args...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	var orders []Order
+	index := make(map[int64]int)
 	for rows.Next() {
 		var o Order
 		err := rows.Scan(
 			&o.ID, &o.CreatedAt, &o.DueDate, &o.ClientName, &o.Contact,
 			&o.RepresentativeID, &o.RepresentativeName, &o.NeedsDelivery,
-			&o.DeliveryAddress, &o.Comment, &o.Completed, &o.TotalPrice,
+			&o.DeliveryAddress, &o.Comment, &o.Completed, &o.Cancelled, &o.TotalPrice,
+			&o.Version,
 		)
 		if err != nil {
+			rows.Close()
 			return nil, err
 		}
+		index[o.ID] = len(orders)
+		orders = append(orders, o)
+	}
+	rows.Close()
+	if len(orders) == 0 {
+		return orders, nil
+	}
 
-		// Load order items
-		itemRows, err := db.Query(`
-            SELECT oi.id, oi.product_id, p.name, oi.quantity, oi.price
-            FROM order_items oi
-            JOIN products p ON oi.product_id = p.id
-            WHERE oi.order_id = ?
-        `, o.ID)
+	// Load the items of all selected orders in one query once the orders are
+	// read, so a transaction never has two result sets open
+	itemRows, err := q.Query(`
+        SELECT oi.order_id, oi.id, oi.product_id, p.name, oi.quantity, oi.price
+        FROM order_items oi
+        JOIN products p ON oi.product_id = p.id
+        WHERE oi.order_id IN (SELECT o.id `+selected+`)
+        ORDER BY oi.order_id, oi.id
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var orderID int64
+		var item OrderItem
+		err := itemRows.Scan(&orderID, &item.ID, &item.ProductID, &item.ProductName,
+			&item.Quantity, &item.Price)
 		if err != nil {
 			return nil, err
 		}
-		defer itemRows.Close()
-
-		for itemRows.Next() {
-			var item OrderItem
-			err := itemRows.Scan(&item.ID, &item.ProductID, &item.ProductName,
-				&item.Quantity, &item.Price)
-			if err != nil {
-				return nil, err
-			}
-			o.Items = append(o.Items, item)
+		if i, ok := index[orderID]; ok {
+			orders[i].Items = append(orders[i].Items, item)
 		}
-
-		orders = append(orders, o)
 	}
-	return orders, nil
+	return orders, itemRows.Err()
 }
 
+// EditOrder saves the changes to an order and its items. order.Version must
+// be the version the changes were made to; when the order was changed since,
+// nothing is saved and a *ConflictError is returned.
 func EditOrder(db *sql.DB, order Order) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -91,20 +219,38 @@ func EditOrder(db *sql.DB, order Order) error {
 	}
 	defer tx.Rollback()
 
-	// Update main order
-	_, err = tx.Exec(`
+	before, err := loadOrder(tx, order.ID)
+	if err != nil {
+		return err
+	}
+	if before.Version != order.Version {
+		return &ConflictError{Mine: order, Theirs: before}
+	}
+
+	// Update main order, unless another edit was saved in the meantime
+	result, err := tx.Exec(`
         UPDATE orders
         SET due_date = ?, client_name = ?, contact = ?,
             representative_id = ?, needs_delivery = ?,
-            delivery_address = ?, comment = ?, total_price = ?
-        WHERE id = ?`,
+            delivery_address = ?, comment = ?, total_price = ?,
+            version = version + 1
+        WHERE id = ? AND version = ?`,
 		order.DueDate, order.ClientName, order.Contact,
 		order.RepresentativeID, order.NeedsDelivery,
 		order.DeliveryAddress, order.Comment, order.TotalPrice,
-		order.ID)
+		order.ID, order.Version)
 	if err != nil {
 		return err
 	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		current, err := loadOrder(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		return &ConflictError{Mine: order, Theirs: current}
+	}
 
 	// Delete existing order items
 	_, err = tx.Exec("DELETE FROM order_items WHERE order_id = ?", order.ID)
@@ -123,5 +269,93 @@ func EditOrder(db *sql.DB, order Order) error {
 		}
 	}
 
-	return tx.Commit()
+	order.CreatedAt, order.Completed, order.Cancelled = before.CreatedAt, before.Completed, before.Cancelled
+	order.Version = before.Version + 1
+	if err := RecordAudit(tx, EntityOrder, order.ID, ActionUpdate, before, order); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	DefaultBus.Publish(OrderEdited{OrderID: order.ID})
+	return nil
+}
+
+// CreateOrder inserts a pending order with its items and returns the new order ID.
+// The total price is calculated from the items.
+func CreateOrder(db *sql.DB, order Order) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = time.Now()
+	}
+
+	var total float64
+	for _, item := range order.Items {
+		total += item.Price
+	}
+
+	// Insert main order
+	result, err := tx.Exec(`
+        INSERT INTO orders (
+            created_at, due_date, client_name, contact,
+            representative_id, needs_delivery, delivery_address,
+            comment, completed, total_price
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		order.CreatedAt, order.DueDate, order.ClientName, order.Contact,
+		order.RepresentativeID, order.NeedsDelivery, order.DeliveryAddress,
+		order.Comment, false, total,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	orderID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	// Insert order items
+	for _, item := range order.Items {
+		_, err = tx.Exec(`
+            INSERT INTO order_items (order_id, product_id, quantity, price)
+            VALUES (?, ?, ?, ?)`,
+			orderID, item.ProductID, item.Quantity, item.Price)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	order.ID, order.TotalPrice = orderID, total
+	if err := RecordAudit(tx, EntityOrder, orderID, ActionCreate, nil, order); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	DefaultBus.Publish(OrderCreated{OrderID: orderID})
+	return orderID, nil
+}
+
+// CompleteOrder marks an order as completed
+func CompleteOrder(db *sql.DB, id int64) error {
+	return CompleteOrders(db, []int64{id})
+}
+
+// expectOneRow returns an error when an update by ID did not match a row
+func expectOneRow(result sql.Result, kind string, id int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%s %d %w", kind, id, ErrNotFound)
+	}
+	return nil
 }
@@ -21,23 +21,22 @@ func TestLoadOrders(t *testing.T) {
 	dueDate := now.AddDate(0, 0, 7)
 
 	// Expected orders query
-	mock.ExpectQuery("SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact, o.representative_id, r.name, o.needs_delivery, o.delivery_address, o.comment, o.completed, o.total_price FROM orders o LEFT JOIN representatives r ON o.representative_id = r.id WHERE o.completed = false ORDER BY o.created_at DESC").
+	mock.ExpectQuery("SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact, o.representative_id, r.name, o.needs_delivery, o.delivery_address, o.comment, o.completed, o.cancelled, o.total_price, o.version FROM orders o LEFT JOIN representatives r ON o.representative_id = r.id WHERE o.completed = false AND o.cancelled = false ORDER BY o.created_at DESC").
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "created_at", "due_date", "client_name", "contact",
 			"representative_id", "rep_name", "needs_delivery", "delivery_address",
-			"comment", "completed", "total_price",
+			"comment", "completed", "cancelled", "total_price", "version",
 		}).
 		AddRow(1, now, dueDate, "Test Client", "123-456-7890",
 			2, "John Doe", false, "",
-			"Test comment", false, 25.50))
+			"Test comment", false, false, 25.50, 1))
 
 	// Expected order items query
-	mock.ExpectQuery("SELECT oi.id, oi.product_id, p.name, oi.quantity, oi.price FROM order_items oi JOIN products p ON oi.product_id = p.id WHERE oi.order_id = ?").
-		WithArgs(1).
+	mock.ExpectQuery("SELECT oi.order_id, oi.id, oi.product_id, p.name, oi.quantity, oi.price FROM order_items oi JOIN products p ON oi.product_id = p.id WHERE oi.order_id IN \\(SELECT o.id FROM orders o .* WHERE o.completed = false AND o.cancelled = false").
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "product_id", "name", "quantity", "price",
+			"order_id", "id", "product_id", "name", "quantity", "price",
 		}).
-		AddRow(1, 1, "Test Product", 2, 25.50))
+		AddRow(1, 1, 1, "Test Product", 2, 25.50))
 
 	// Call the function being tested
 	orders, err := LoadOrders(db)
@@ -99,11 +98,11 @@ func TestLoadOrders_NoOrders(t *testing.T) {
 	defer db.Close()
 
 	// Expect query but return empty result
-	mock.ExpectQuery("SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact, o.representative_id, r.name, o.needs_delivery, o.delivery_address, o.comment, o.completed, o.total_price FROM orders o LEFT JOIN representatives r ON o.representative_id = r.id WHERE o.completed = false ORDER BY o.created_at DESC").
+	mock.ExpectQuery("SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact, o.representative_id, r.name, o.needs_delivery, o.delivery_address, o.comment, o.completed, o.cancelled, o.total_price, o.version FROM orders o LEFT JOIN representatives r ON o.representative_id = r.id WHERE o.completed = false AND o.cancelled = false ORDER BY o.created_at DESC").
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "created_at", "due_date", "client_name", "contact",
 			"representative_id", "rep_name", "needs_delivery", "delivery_address",
-			"comment", "completed", "total_price",
+			"comment", "completed", "cancelled", "total_price", "version",
 		}))
 
 	// Call the function being tested
@@ -143,6 +142,7 @@ func TestEditOrder(t *testing.T) {
 		DeliveryAddress:  "123 Main St",
 		Comment:          "Updated comment",
 		TotalPrice:       35.75,
+		Version:          1,
 		Items: []OrderItem{
 			{
 				ProductID: 2,
@@ -152,11 +152,12 @@ func TestEditOrder(t *testing.T) {
 		},
 	}
 
-	// Expect transaction to begin
+	// Expect transaction to begin and the order to be loaded for the audit log
 	mock.ExpectBegin()
+	expectLoadOrder(mock, order.ID)
 
 	// Expect update query
-	mock.ExpectExec("UPDATE orders SET due_date = \\?, client_name = \\?, contact = \\?, representative_id = \\?, needs_delivery = \\?, delivery_address = \\?, comment = \\?, total_price = \\? WHERE id = \\?").
+	mock.ExpectExec("UPDATE orders SET due_date = \\?, client_name = \\?, contact = \\?, representative_id = \\?, needs_delivery = \\?, delivery_address = \\?, comment = \\?, total_price = \\?, version = version \\+ 1 WHERE id = \\? AND version = \\?").
 		WithArgs(
 			order.DueDate,
 			order.ClientName,
@@ -167,6 +168,7 @@ func TestEditOrder(t *testing.T) {
 			order.Comment,
 			order.TotalPrice,
 			order.ID,
+			order.Version,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -180,6 +182,9 @@ func TestEditOrder(t *testing.T) {
 		WithArgs(order.ID, order.Items[0].ProductID, order.Items[0].Quantity, order.Items[0].Price).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	// Expect the change to be audited
+	expectAudit(mock, EntityOrder, order.ID, ActionUpdate)
+
 	// Expect commit
 	mock.ExpectCommit()
 
@@ -240,10 +245,12 @@ func TestEditOrder_UpdateError(t *testing.T) {
 		ID:         1,
 		DueDate:    time.Now(),
 		ClientName: "Test Client",
+		Version:    1,
 	}
 
 	// Expect transaction to begin
 	mock.ExpectBegin()
+	expectLoadOrder(mock, order.ID)
 
 	// Expect update query to fail
 	mock.ExpectExec("UPDATE orders").
@@ -257,6 +264,7 @@ func TestEditOrder_UpdateError(t *testing.T) {
 			order.Comment,
 			order.TotalPrice,
 			order.ID,
+			order.Version,
 		).
 		WillReturnError(fmt.Errorf("update error"))
 
@@ -281,3 +289,179 @@ func TestEditOrder_UpdateError(t *testing.T) {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
+
+func TestCreateOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Now()
+	order := Order{
+		CreatedAt:        createdAt,
+		DueDate:          createdAt.AddDate(0, 0, 3),
+		ClientName:       "New Client",
+		Contact:          "123-456-7890",
+		RepresentativeID: 2,
+		Comment:          "No nuts",
+		Items: []OrderItem{
+			{ProductID: 1, Quantity: 2, Price: 20},
+			{ProductID: 3, Quantity: 1, Price: 15.5},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO orders").
+		WithArgs(order.CreatedAt, order.DueDate, order.ClientName, order.Contact,
+			order.RepresentativeID, false, "", order.Comment, false, 35.5).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	for _, item := range order.Items {
+		mock.ExpectExec("INSERT INTO order_items").
+			WithArgs(int64(7), item.ProductID, item.Quantity, item.Price).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+	expectAudit(mock, EntityOrder, 7, ActionCreate)
+	mock.ExpectCommit()
+
+	id, err := CreateOrder(db, order)
+	if err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	if id != 7 {
+		t.Errorf("Expected order ID 7, got %d", id)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCompleteOrder_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT o.id, .* FROM orders o .* WHERE o.id = \\?").
+		WithArgs(int64(42)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "created_at", "due_date", "client_name", "contact",
+			"representative_id", "rep_name", "needs_delivery", "delivery_address",
+			"comment", "completed", "cancelled", "total_price", "version",
+		}))
+	mock.ExpectRollback()
+
+	err = CompleteOrder(db, 42)
+	if err == nil || err.Error() != "order 42 not found" {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCompleteOrder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock database: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	expectLoadOrder(mock, 5)
+	mock.ExpectExec("UPDATE orders SET completed = true, version = version \\+ 1 WHERE id = \\?").
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectAudit(mock, EntityOrder, 5, ActionComplete)
+	mock.ExpectCommit()
+
+	if err := CompleteOrder(db, 5); err != nil {
+		t.Fatalf("Failed to complete order: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+// expectLoadOrder expects the queries that load order id inside a transaction
+func expectLoadOrder(mock sqlmock.Sqlmock, id int64) {
+	mock.ExpectQuery("SELECT o.id, .* FROM orders o LEFT JOIN representatives r ON o.representative_id = r.id WHERE o.id = \\?").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "created_at", "due_date", "client_name", "contact",
+			"representative_id", "rep_name", "needs_delivery", "delivery_address",
+			"comment", "completed", "cancelled", "total_price", "version",
+		}).
+			AddRow(id, time.Now(), time.Now(), "Old Client", "", 2, "John Doe", false, "", "", false, false, 10.0, 1))
+	mock.ExpectQuery("SELECT oi.order_id, oi.id, oi.product_id, p.name, oi.quantity, oi.price FROM order_items oi").
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"order_id", "id", "product_id", "name", "quantity", "price"}).
+			AddRow(id, 1, 1, "Test Product", 1, 10.0))
+}
+
+// expectAudit expects an audit entry for the entity and action
+func expectAudit(mock sqlmock.Sqlmock, entity string, id int64, action string) {
+	mock.ExpectExec("INSERT INTO audit_log").
+		WithArgs(entity, id, action, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestLoadOrderPage(t *testing.T) {
+	database := setupOrdersDB(t)
+	if err := CancelOrders(database, []int64{1}); err != nil {
+		t.Fatalf("CancelOrders failed: %v", err)
+	}
+	if err := CompleteOrder(database, 2); err != nil {
+		t.Fatalf("CompleteOrder failed: %v", err)
+	}
+
+	orders, total, err := LoadOrderPage(database, "", 2, 0)
+	if err != nil || total != 3 || len(orders) != 2 || orders[0].ID != 3 || orders[1].ID != 2 {
+		t.Fatalf("Expected orders 3 and 2 of 3, got %+v of %d (%v)", orders, total, err)
+	}
+	orders, _, err = LoadOrderPage(database, "", 2, 2)
+	if err != nil || len(orders) != 1 || orders[0].ID != 1 || len(orders[0].Items) != 1 {
+		t.Errorf("Expected order 1 with its item on the second page, got %+v (%v)", orders, err)
+	}
+
+	for status, id := range map[string]int64{StatusPending: 3, StatusCompleted: 2, StatusCancelled: 1} {
+		orders, total, err := LoadOrderPage(database, status, 10, 0)
+		if err != nil || total != 1 || len(orders) != 1 || orders[0].ID != id {
+			t.Errorf("%s: expected order %d, got %+v (%v)", status, id, orders, err)
+		}
+	}
+	if _, _, err := LoadOrderPage(database, "Open", 10, 0); err == nil {
+		t.Error("Expected an error for an unknown status")
+	}
+}
+
+func TestLoadOrdersByStatus(t *testing.T) {
+	database := setupOrdersDB(t)
+	if _, err := database.Exec(`INSERT INTO order_items (order_id, product_id, quantity, price) VALUES (2, 1, 3, 37.5)`); err != nil {
+		t.Fatalf("Failed to insert item: %v", err)
+	}
+	if err := CompleteOrder(database, 3); err != nil {
+		t.Fatalf("CompleteOrder failed: %v", err)
+	}
+
+	orders, err := LoadOrdersByStatus(database, StatusPending)
+	if err != nil || len(orders) != 2 || orders[0].ID != 2 || orders[1].ID != 1 {
+		t.Fatalf("Expected pending orders 2 and 1, got %+v (%v)", orders, err)
+	}
+	if len(orders[0].Items) != 2 || len(orders[1].Items) != 1 || orders[0].Items[1].Quantity != 3 {
+		t.Errorf("Expected each order with its own items, got %+v and %+v", orders[0].Items, orders[1].Items)
+	}
+
+	orders, err = LoadOrdersByStatus(database, "")
+	if err != nil || len(orders) != 3 {
+		t.Errorf("Expected all 3 orders, got %d (%v)", len(orders), err)
+	}
+	if _, err := LoadOrdersByStatus(database, "Open"); err == nil {
+		t.Error("Expected an error for an unknown status")
+	}
+}
@@ -0,0 +1,119 @@
+// internal/orderConflict.go
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrConflict is matched by errors.Is for every *ConflictError
+var ErrConflict = errors.New("changed by someone else")
+
+// ConflictError is returned by EditOrder when the order was changed by
+// someone else after it was loaded for editing. Mine holds the unsaved
+// changes and Theirs the order as it is now.
+type ConflictError struct {
+	Mine   Order
+	Theirs Order
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("order %d was %s since it was opened", e.Mine.ID, ErrConflict)
+}
+
+// Is makes errors.Is(err, ErrConflict) report true
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// OrderField is a part of an order that can be merged separately
+type OrderField string
+
+// Order fields that EditOrder changes
+const (
+	FieldDueDate        OrderField = "Due Date"
+	FieldClient         OrderField = "Client"
+	FieldContact        OrderField = "Contact"
+	FieldRepresentative OrderField = "Representative"
+	FieldDelivery       OrderField = "Delivery"
+	FieldComment        OrderField = "Comment"
+	FieldItems          OrderField = "Items"
+)
+
+// OrderFields lists the fields in the order they are shown
+var OrderFields = []OrderField{
+	FieldDueDate, FieldClient, FieldContact, FieldRepresentative,
+	FieldDelivery, FieldComment, FieldItems,
+}
+
+// FieldValue formats a field of an order for display
+func FieldValue(o Order, field OrderField) string {
+	switch field {
+	case FieldDueDate:
+		if HasDueTime(o.DueDate) {
+			return o.DueDate.Format("2006-01-02 15:04")
+		}
+		return o.DueDate.Format("2006-01-02")
+	case FieldClient:
+		return o.ClientName
+	case FieldContact:
+		return o.Contact
+	case FieldRepresentative:
+		return o.RepresentativeName
+	case FieldDelivery:
+		if !o.NeedsDelivery {
+			return "No delivery"
+		}
+		return "Deliver to " + o.DeliveryAddress
+	case FieldComment:
+		return o.Comment
+	case FieldItems:
+		var items []string
+		for _, item := range o.Items {
+			items = append(items, fmt.Sprintf("%d x %s", item.Quantity, item.ProductName))
+		}
+		return fmt.Sprintf("%s\nTotal R%.2f", strings.Join(items, "\n"), o.TotalPrice)
+	}
+	return ""
+}
+
+// Differences returns the fields that differ between the two versions
+func (e *ConflictError) Differences() []OrderField {
+	var fields []OrderField
+	for _, field := range OrderFields {
+		if FieldValue(e.Mine, field) != FieldValue(e.Theirs, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Merge returns their version of the order with the fields in keepMine taken
+// from mine. The result can be saved with EditOrder, unless the order changed
+// yet again.
+func (e *ConflictError) Merge(keepMine map[OrderField]bool) Order {
+	merged, mine := e.Theirs, e.Mine
+	for field := range keepMine {
+		if !keepMine[field] {
+			continue
+		}
+		switch field {
+		case FieldDueDate:
+			merged.DueDate = mine.DueDate
+		case FieldClient:
+			merged.ClientName = mine.ClientName
+		case FieldContact:
+			merged.Contact = mine.Contact
+		case FieldRepresentative:
+			merged.RepresentativeID, merged.RepresentativeName = mine.RepresentativeID, mine.RepresentativeName
+		case FieldDelivery:
+			merged.NeedsDelivery, merged.DeliveryAddress = mine.NeedsDelivery, mine.DeliveryAddress
+		case FieldComment:
+			merged.Comment = mine.Comment
+		case FieldItems:
+			merged.Items, merged.TotalPrice = mine.Items, mine.TotalPrice
+		}
+	}
+	return merged
+}
@@ -0,0 +1,60 @@
+// internal/orderConflict_test.go
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEditOrder_Conflict(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	opened, err := LoadOrder(database, 1)
+	if err != nil {
+		t.Fatalf("LoadOrder failed: %v", err)
+	}
+
+	// Someone else changes the comment first
+	theirs := opened
+	theirs.Comment = "Ring the bell"
+	if err := EditOrder(database, theirs); err != nil {
+		t.Fatalf("EditOrder failed: %v", err)
+	}
+
+	// Saving changes made to the opened version conflicts
+	mine := opened
+	mine.ClientName = "Alice Smith"
+	mine.Comment = "Leave at the door"
+	err = EditOrder(database, mine)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if conflict.Theirs.Comment != "Ring the bell" || conflict.Theirs.Version != opened.Version+1 {
+		t.Errorf("Expected their version of the order, got %+v", conflict.Theirs)
+	}
+
+	differences := conflict.Differences()
+	if len(differences) != 2 || differences[0] != FieldClient || differences[1] != FieldComment {
+		t.Errorf("Expected client and comment to differ, got %v", differences)
+	}
+
+	// Keep my client name and their comment
+	merged := conflict.Merge(map[OrderField]bool{FieldClient: true, FieldComment: false})
+	if err := EditOrder(database, merged); err != nil {
+		t.Fatalf("Saving the merged order failed: %v", err)
+	}
+
+	saved, err := LoadOrder(database, 1)
+	if err != nil {
+		t.Fatalf("LoadOrder failed: %v", err)
+	}
+	if saved.ClientName != "Alice Smith" || saved.Comment != "Ring the bell" || saved.Version != opened.Version+2 {
+		t.Errorf("Unexpected merged order: %+v", saved)
+	}
+
+	// The merged version is stale now too
+	if err := EditOrder(database, merged); !errors.Is(err, ErrConflict) {
+		t.Errorf("Expected a conflict saving twice, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+// internal/orderDetails.go
+package internal
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Payment is money received for an order
+type Payment struct {
+	ID        int64
+	OrderID   int64
+	Amount    float64
+	Method    string
+	Reference string
+	PaidAt    time.Time
+	User      string
+}
+
+// Note is a remark attached to an order, such as a call with the client
+type Note struct {
+	ID        int64
+	OrderID   int64
+	Body      string
+	User      string
+	CreatedAt time.Time
+}
+
+// UnitPrice returns the price of one item; Price is the price of the line
+func (item OrderItem) UnitPrice() float64 {
+	if item.Quantity == 0 {
+		return 0
+	}
+	return item.Price / float64(item.Quantity)
+}
+
+// OrderDetails is an order with everything recorded about it
+type OrderDetails struct {
+	Order    Order
+	Payments []Payment
+	Notes    []Note
+
+	// History holds the audit entries of the order, newest first
+	History []AuditEntry
+}
+
+// LoadOrderDetails returns an order with its payments, notes and history
+func LoadOrderDetails(db *sql.DB, id int64) (OrderDetails, error) {
+	var d OrderDetails
+	var err error
+	if d.Order, err = loadOrder(db, id); err != nil {
+		return d, err
+	}
+	if d.Payments, err = LoadPayments(db, id); err != nil {
+		return d, err
+	}
+	if d.Notes, err = LoadNotes(db, id); err != nil {
+		return d, err
+	}
+	if d.History, err = LoadAuditLog(db, AuditFilter{Entity: EntityOrder, EntityID: id}); err != nil {
+		return d, err
+	}
+	return d, nil
+}
+
+// Paid returns the sum of the payments
+func (d OrderDetails) Paid() float64 {
+	var paid float64
+	for _, p := range d.Payments {
+		paid += p.Amount
+	}
+	return paid
+}
+
+// Balance returns the amount still to be paid, negative when overpaid
+func (d OrderDetails) Balance() float64 {
+	return d.Order.TotalPrice - d.Paid()
+}
+
+// StatusChange is a step in the life of an order
+type StatusChange struct {
+	Time   time.Time
+	Status string
+	User   string
+}
+
+// Timeline returns the status changes of the order, oldest first, taken from
+// its history. Orders created before the audit log only show their creation
+// time and current status.
+func (d OrderDetails) Timeline() []StatusChange {
+	var timeline []StatusChange
+	for i := len(d.History) - 1; i >= 0; i-- {
+		e := d.History[i]
+		var status string
+		switch e.Action {
+		case ActionCreate:
+			status = "Created"
+		case ActionComplete:
+			status = StatusCompleted
+		case ActionCancel:
+			status = StatusCancelled
+		default:
+			continue
+		}
+		timeline = append(timeline, StatusChange{Time: e.CreatedAt, Status: status, User: e.User})
+	}
+
+	if len(timeline) == 0 || timeline[0].Status != "Created" {
+		timeline = append([]StatusChange{{Time: d.Order.CreatedAt, Status: "Created"}}, timeline...)
+	}
+	if last := timeline[len(timeline)-1]; d.Order.Status() != StatusPending && last.Status != d.Order.Status() {
+		timeline = append(timeline, StatusChange{Status: d.Order.Status()})
+	}
+	return timeline
+}
+
+// LoadPayments returns the payments of an order, oldest first
+func LoadPayments(db *sql.DB, orderID int64) ([]Payment, error) {
+	rows, err := db.Query(`
+        SELECT id, order_id, amount, method, reference, paid_at, user
+        FROM order_payments
+        WHERE order_id = ?
+        ORDER BY paid_at, id
+    `, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading payments: %w", err)
+	}
+	defer rows.Close()
+
+	var payments []Payment
+	for rows.Next() {
+		var p Payment
+		if err := rows.Scan(&p.ID, &p.OrderID, &p.Amount, &p.Method, &p.Reference, &p.PaidAt, &p.User); err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+// RecordPayment stores a payment for an order and returns its ID. PaidAt
+// defaults to now.
+func RecordPayment(db *sql.DB, p Payment) (int64, error) {
+	if p.Amount <= 0 {
+		return 0, fmt.Errorf("payment amount must be positive")
+	}
+	if p.PaidAt.IsZero() {
+		p.PaidAt = time.Now()
+	}
+	p.Method, p.Reference, p.User = strings.TrimSpace(p.Method), strings.TrimSpace(p.Reference), AuditUser()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := loadOrder(tx, p.OrderID); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`
+        INSERT INTO order_payments (order_id, amount, method, reference, paid_at, user)
+        VALUES (?, ?, ?, ?, ?, ?)`,
+		p.OrderID, p.Amount, p.Method, p.Reference, p.PaidAt, p.User)
+	if err != nil {
+		return 0, err
+	}
+	if p.ID, err = result.LastInsertId(); err != nil {
+		return 0, err
+	}
+	if err := RecordAudit(tx, EntityPayment, p.ID, ActionCreate, nil, p); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	DefaultBus.Publish(PaymentRecorded{OrderID: p.OrderID, PaymentID: p.ID})
+	return p.ID, nil
+}
+
+// LoadNotes returns the notes of an order, oldest first
+func LoadNotes(db *sql.DB, orderID int64) ([]Note, error) {
+	rows, err := db.Query(`
+        SELECT id, order_id, body, user, created_at
+        FROM order_notes
+        WHERE order_id = ?
+        ORDER BY created_at, id
+    `, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading notes: %w", err)
+	}
+	defer rows.Close()
+
+	var notes []Note
+	for rows.Next() {
+		var n Note
+		if err := rows.Scan(&n.ID, &n.OrderID, &n.Body, &n.User, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, n)
+	}
+	return notes, rows.Err()
+}
+
+// AddNote attaches a note to an order and returns its ID
+func AddNote(db *sql.DB, orderID int64, body string) (int64, error) {
+	n := Note{OrderID: orderID, Body: strings.TrimSpace(body), User: AuditUser(), CreatedAt: time.Now()}
+	if n.Body == "" {
+		return 0, fmt.Errorf("note is empty")
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := loadOrder(tx, orderID); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`
+        INSERT INTO order_notes (order_id, body, user, created_at)
+        VALUES (?, ?, ?, ?)`,
+		n.OrderID, n.Body, n.User, n.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	if n.ID, err = result.LastInsertId(); err != nil {
+		return 0, err
+	}
+	if err := RecordAudit(tx, EntityNote, n.ID, ActionCreate, nil, n); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	DefaultBus.Publish(NoteAdded{OrderID: orderID, NoteID: n.ID})
+	return n.ID, nil
+}
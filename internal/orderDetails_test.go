@@ -0,0 +1,65 @@
+// internal/orderDetails_test.go
+package internal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadOrderDetails(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	if _, err := RecordPayment(database, Payment{OrderID: 1, Amount: 5, Method: " Cash "}); err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	if _, err := RecordPayment(database, Payment{OrderID: 1, Amount: 2.5, Method: "Card", Reference: "1234"}); err != nil {
+		t.Fatalf("RecordPayment failed: %v", err)
+	}
+	if _, err := AddNote(database, 1, "  Client called to confirm  "); err != nil {
+		t.Fatalf("AddNote failed: %v", err)
+	}
+	if err := CompleteOrder(database, 1); err != nil {
+		t.Fatalf("CompleteOrder failed: %v", err)
+	}
+
+	d, err := LoadOrderDetails(database, 1)
+	if err != nil {
+		t.Fatalf("LoadOrderDetails failed: %v", err)
+	}
+	if len(d.Payments) != 2 || d.Payments[0].Method != "Cash" || d.Paid() != 7.5 || d.Balance() != 5 {
+		t.Errorf("Unexpected payments: %+v (paid %.2f, balance %.2f)", d.Payments, d.Paid(), d.Balance())
+	}
+	if len(d.Notes) != 1 || d.Notes[0].Body != "Client called to confirm" || d.Notes[0].User == "" {
+		t.Errorf("Unexpected notes: %+v", d.Notes)
+	}
+	if len(d.History) != 2 || d.History[0].Action != ActionComplete {
+		t.Errorf("Expected the create and complete entries, got %+v", d.History)
+	}
+
+	timeline := d.Timeline()
+	if len(timeline) != 2 || timeline[0].Status != "Created" || timeline[1].Status != StatusCompleted {
+		t.Errorf("Unexpected timeline: %+v", timeline)
+	}
+	if unit := d.Order.Items[0].UnitPrice(); unit != 12.5 {
+		t.Errorf("Expected unit price 12.50, got %.2f", unit)
+	}
+}
+
+func TestRecordPayment_Invalid(t *testing.T) {
+	database := setupOrdersDB(t)
+
+	if _, err := RecordPayment(database, Payment{OrderID: 1}); err == nil {
+		t.Error("Expected error for a payment without amount")
+	}
+	if _, err := RecordPayment(database, Payment{OrderID: 42, Amount: 10}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+	if _, err := AddNote(database, 1, "  "); err == nil {
+		t.Error("Expected error for an empty note")
+	}
+
+	payments, err := LoadPayments(database, 1)
+	if err != nil || len(payments) != 0 {
+		t.Errorf("Expected no payments, got %v (%v)", payments, err)
+	}
+}
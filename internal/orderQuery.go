@@ -0,0 +1,154 @@
+// internal/orderQuery.go
+package internal
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Order statuses
+const (
+	StatusPending   = "Pending"
+	StatusCompleted = "Completed"
+	StatusCancelled = "Cancelled"
+)
+
+// Statuses lists the order statuses
+var Statuses = []string{StatusPending, StatusCompleted, StatusCancelled}
+
+// Status returns StatusPending, StatusCompleted or StatusCancelled
+func (o Order) Status() string {
+	if o.Completed {
+		return StatusCompleted
+	}
+	if o.Cancelled {
+		return StatusCancelled
+	}
+	return StatusPending
+}
+
+// Columns orders can be sorted by
+const (
+	SortCreated        = "created"
+	SortClient         = "client"
+	SortTotal          = "total"
+	SortRepresentative = "representative"
+	SortDueDate        = "due_date"
+	SortStatus         = "status"
+)
+
+// OrderQuery filters and sorts loaded orders. Zero values match everything
+// and sort by creation time.
+type OrderQuery struct {
+	// Search matches orders containing every word in the client name,
+	// contact, product names or comment, ignoring case
+	Search string `json:"search,omitempty"`
+
+	RepresentativeID int64 `json:"representative_id,omitempty"`
+
+	// DueFrom and DueTo limit the due date to the half-open range
+	// [DueFrom, DueTo)
+	DueFrom time.Time `json:"due_from,omitempty"`
+	DueTo   time.Time `json:"due_to,omitempty"`
+
+	// Status is StatusPending, StatusCompleted, StatusCancelled or empty for
+	// all of them
+	Status string `json:"status,omitempty"`
+
+	SortBy     string `json:"sort_by,omitempty"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// Match reports whether an order passes the filters of the query
+func (q OrderQuery) Match(o Order) bool {
+	if q.RepresentativeID != 0 && o.RepresentativeID != q.RepresentativeID {
+		return false
+	}
+	due := o.DueDate
+	if !HasDueTime(due) {
+		// Dates without a time are due from the start of that day in the
+		// time zone the range was given in
+		loc := q.DueFrom.Location()
+		if q.DueFrom.IsZero() {
+			loc = q.DueTo.Location()
+		}
+		due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, loc)
+	}
+	if !q.DueFrom.IsZero() && due.Before(q.DueFrom) {
+		return false
+	}
+	if !q.DueTo.IsZero() && !due.Before(q.DueTo) {
+		return false
+	}
+	if q.Status != "" && o.Status() != q.Status {
+		return false
+	}
+
+	words := strings.Fields(strings.ToLower(q.Search))
+	if len(words) == 0 {
+		return true
+	}
+	text := []string{o.ClientName, o.Contact, o.Comment}
+	for _, item := range o.Items {
+		text = append(text, item.ProductName)
+	}
+	haystack := strings.ToLower(strings.Join(text, "\n"))
+	for _, word := range words {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the matching orders in the order of the query. Orders that
+// compare equal keep their relative order.
+func (q OrderQuery) Apply(orders []Order) []Order {
+	matched := []Order{}
+	for _, o := range orders {
+		if q.Match(o) {
+			matched = append(matched, o)
+		}
+	}
+
+	less := orderLess(q.SortBy)
+	sort.SliceStable(matched, func(i, j int) bool {
+		if q.Descending {
+			return less(matched[j], matched[i])
+		}
+		return less(matched[i], matched[j])
+	})
+	return matched
+}
+
+func orderLess(sortBy string) func(a, b Order) bool {
+	switch sortBy {
+	case SortClient:
+		return func(a, b Order) bool { return strings.ToLower(a.ClientName) < strings.ToLower(b.ClientName) }
+	case SortTotal:
+		return func(a, b Order) bool { return a.TotalPrice < b.TotalPrice }
+	case SortRepresentative:
+		return func(a, b Order) bool {
+			return strings.ToLower(a.RepresentativeName) < strings.ToLower(b.RepresentativeName)
+		}
+	case SortDueDate:
+		return func(a, b Order) bool { return a.DueDate.Before(b.DueDate) }
+	case SortStatus:
+		return func(a, b Order) bool { return statusRank(a) < statusRank(b) }
+	default:
+		return func(a, b Order) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	}
+}
+
+// statusRank sorts pending orders before completed and cancelled ones
+func statusRank(o Order) int {
+	switch o.Status() {
+	case StatusCompleted:
+		return 1
+	case StatusCancelled:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,75 @@
+// internal/orderQuery_test.go
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func testOrders() []Order {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	return []Order{
+		{ID: 1, CreatedAt: day(1), DueDate: day(10), ClientName: "Bob", Contact: "082 123 4567",
+			RepresentativeID: 1, RepresentativeName: "Anna", TotalPrice: 50,
+			Items: []OrderItem{{ProductName: "Chocolate Cake"}}},
+		{ID: 2, CreatedAt: day(2), DueDate: day(5), ClientName: "alice", Comment: "Gluten free please",
+			RepresentativeID: 2, RepresentativeName: "Ben", TotalPrice: 120, Completed: true,
+			Items: []OrderItem{{ProductName: "Muffin"}}},
+		{ID: 3, CreatedAt: day(3), DueDate: day(7), ClientName: "Carol", Contact: "carol@example.com",
+			RepresentativeID: 1, RepresentativeName: "Anna", TotalPrice: 80,
+			Items: []OrderItem{{ProductName: "Muffin"}, {ProductName: "Cake"}}},
+	}
+}
+
+func orderIDs(orders []Order) []int64 {
+	ids := []int64{}
+	for _, o := range orders {
+		ids = append(ids, o.ID)
+	}
+	return ids
+}
+
+func TestOrderQuery_Apply(t *testing.T) {
+	tests := []struct {
+		name  string
+		query OrderQuery
+		want  []int64
+	}{
+		{"default sort", OrderQuery{}, []int64{1, 2, 3}},
+		{"newest first", OrderQuery{Descending: true}, []int64{3, 2, 1}},
+		{"search client", OrderQuery{Search: "ALICE"}, []int64{2}},
+		{"search contact", OrderQuery{Search: "example.com"}, []int64{3}},
+		{"search product", OrderQuery{Search: "cake"}, []int64{1, 3}},
+		{"search comment", OrderQuery{Search: "gluten"}, []int64{2}},
+		{"search every word", OrderQuery{Search: "muffin cake"}, []int64{3}},
+		{"representative", OrderQuery{RepresentativeID: 1}, []int64{1, 3}},
+		{"status", OrderQuery{Status: StatusPending}, []int64{1, 3}},
+		{"due range", OrderQuery{
+			DueFrom: time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC),
+			DueTo:   time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC),
+		}, []int64{2, 3}},
+		{"due range in another zone", OrderQuery{
+			DueFrom: time.Date(2024, 6, 5, 0, 0, 0, 0, time.FixedZone("EDT", -4*3600)),
+			DueTo:   time.Date(2024, 6, 8, 0, 0, 0, 0, time.FixedZone("EDT", -4*3600)),
+		}, []int64{2, 3}},
+		{"sort client", OrderQuery{SortBy: SortClient}, []int64{2, 1, 3}},
+		{"sort total descending", OrderQuery{SortBy: SortTotal, Descending: true}, []int64{2, 3, 1}},
+		{"sort due date", OrderQuery{SortBy: SortDueDate}, []int64{2, 3, 1}},
+		{"sort representative keeps order", OrderQuery{SortBy: SortRepresentative}, []int64{1, 3, 2}},
+		{"sort status", OrderQuery{SortBy: SortStatus}, []int64{1, 3, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderIDs(tt.query.Apply(testOrders()))
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
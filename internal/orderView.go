@@ -0,0 +1,180 @@
+// internal/orderView.go
+package internal
+
+import "reflect"
+
+// OrderView holds the orders shown in a table and which of them are selected.
+// The selection is kept by order ID, so that reloading the orders, for
+// example after another machine added one, never moves it to another order.
+//
+// The view also remembers which orders were added or changed by a reload,
+// until they are marked as seen.
+type OrderView struct {
+	query    OrderQuery
+	orders   []Order
+	selected map[int64]bool
+
+	// known holds every order loaded before, by ID, and maxID the highest ID
+	// among them. It is nil until the first load.
+	known   map[int64]Order
+	maxID   int64
+	changed map[int64]bool
+}
+
+// NewOrderView creates an empty view showing orders matching query
+func NewOrderView(query OrderQuery) *OrderView {
+	return &OrderView{query: query, selected: make(map[int64]bool), changed: make(map[int64]bool)}
+}
+
+// SetQuery changes the filters and sort of the view. Call SetOrders
+// afterwards to apply it.
+func (v *OrderView) SetQuery(query OrderQuery) {
+	v.query = query
+}
+
+// SetOrders replaces the orders with the loaded ones that match the query.
+// Selected orders that are no longer shown are unselected.
+//
+// Orders that differ from the previous load are marked as changed, and so are
+// orders with an ID above every loaded one, which were added since. Older
+// orders loaded for the first time, for example after the status filter was
+// widened, are not.
+func (v *OrderView) SetOrders(loaded []Order) {
+	if v.known != nil {
+		for _, o := range loaded {
+			before, ok := v.known[o.ID]
+			if (ok && !sameOrder(before, o)) || (!ok && o.ID > v.maxID) {
+				v.changed[o.ID] = true
+			}
+		}
+	} else {
+		v.known = make(map[int64]Order, len(loaded))
+	}
+	for _, o := range loaded {
+		v.known[o.ID] = o
+		if o.ID > v.maxID {
+			v.maxID = o.ID
+		}
+	}
+
+	v.orders = v.query.Apply(loaded)
+
+	shown := make(map[int64]bool, len(v.orders))
+	for _, o := range v.orders {
+		shown[o.ID] = true
+	}
+	for id := range v.selected {
+		if !shown[id] {
+			delete(v.selected, id)
+		}
+	}
+}
+
+// Len returns the number of orders shown
+func (v *OrderView) Len() int {
+	return len(v.orders)
+}
+
+// Order returns the order shown in a row
+func (v *OrderView) Order(row int) (Order, bool) {
+	if row < 0 || row >= len(v.orders) {
+		return Order{}, false
+	}
+	return v.orders[row], true
+}
+
+// Row returns the row showing an order, or -1 when it is not shown
+func (v *OrderView) Row(id int64) int {
+	for i, o := range v.orders {
+		if o.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Toggle selects the order in a row, or unselects it when it was selected
+func (v *OrderView) Toggle(row int) {
+	o, ok := v.Order(row)
+	if !ok {
+		return
+	}
+	if v.selected[o.ID] {
+		delete(v.selected, o.ID)
+	} else {
+		v.selected[o.ID] = true
+	}
+}
+
+// SelectAll selects every order shown
+func (v *OrderView) SelectAll() {
+	for _, o := range v.orders {
+		v.selected[o.ID] = true
+	}
+}
+
+// ClearSelection unselects all orders
+func (v *OrderView) ClearSelection() {
+	v.selected = make(map[int64]bool)
+}
+
+// IsSelected reports whether the order in a row is selected
+func (v *OrderView) IsSelected(row int) bool {
+	o, ok := v.Order(row)
+	return ok && v.selected[o.ID]
+}
+
+// Selected returns the selected orders in the order they are shown
+func (v *OrderView) Selected() []Order {
+	var selected []Order
+	for _, o := range v.orders {
+		if v.selected[o.ID] {
+			selected = append(selected, o)
+		}
+	}
+	return selected
+}
+
+// IsChanged reports whether the order in a row was added or changed by a
+// reload and not seen yet
+func (v *OrderView) IsChanged(row int) bool {
+	o, ok := v.Order(row)
+	return ok && v.changed[o.ID]
+}
+
+// Changed returns the number of changed orders shown
+func (v *OrderView) Changed() int {
+	n := 0
+	for _, o := range v.orders {
+		if v.changed[o.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// MarkSeen clears the change mark of the order in a row
+func (v *OrderView) MarkSeen(row int) {
+	if o, ok := v.Order(row); ok {
+		delete(v.changed, o.ID)
+	}
+}
+
+// MarkAllSeen clears every change mark
+func (v *OrderView) MarkAllSeen() {
+	v.changed = make(map[int64]bool)
+}
+
+// sameOrder reports whether two loads of an order are equal
+func sameOrder(a, b Order) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) || !a.DueDate.Equal(b.DueDate) || len(a.Items) != len(b.Items) {
+		return false
+	}
+	for i := range a.Items {
+		if a.Items[i] != b.Items[i] {
+			return false
+		}
+	}
+	a.CreatedAt, a.DueDate, a.Items = b.CreatedAt, b.DueDate, b.Items
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,86 @@
+// internal/orderView_test.go
+package internal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOrderView_SelectionFollowsOrders(t *testing.T) {
+	view := NewOrderView(OrderQuery{Descending: true})
+	view.SetOrders(testOrders())
+
+	// Newest first: 3, 2, 1
+	view.Toggle(1)
+	view.Toggle(2)
+	if got := orderIDs(view.Selected()); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Fatalf("Expected orders 2 and 1 selected, got %v", got)
+	}
+
+	// Another machine adds a newer order, shifting every row down
+	orders := append(testOrders(), Order{ID: 4, CreatedAt: time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)})
+	view.SetOrders(orders)
+	if got := orderIDs(view.Selected()); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("Expected orders 2 and 1 to stay selected, got %v", got)
+	}
+	if !view.IsSelected(2) || view.IsSelected(1) || view.Row(2) != 2 {
+		t.Errorf("Expected the selection to move with the orders")
+	}
+
+	// Orders no longer shown are unselected
+	view.SetQuery(OrderQuery{Status: StatusPending, Descending: true})
+	view.SetOrders(orders)
+	if got := orderIDs(view.Selected()); len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected only order 1 selected, got %v", got)
+	}
+
+	view.Toggle(view.Row(1))
+	if len(view.Selected()) != 0 {
+		t.Errorf("Expected toggling to unselect, got %v", orderIDs(view.Selected()))
+	}
+	view.SelectAll()
+	if view.Len() != 3 || len(view.Selected()) != 3 {
+		t.Errorf("Expected all 3 shown orders selected, got %v", orderIDs(view.Selected()))
+	}
+	view.ClearSelection()
+	if len(view.Selected()) != 0 {
+		t.Errorf("Expected no selection, got %v", orderIDs(view.Selected()))
+	}
+
+	if _, ok := view.Order(view.Len()); ok {
+		t.Error("Expected no order past the last row")
+	}
+}
+
+func TestOrderView_MarksChanges(t *testing.T) {
+	view := NewOrderView(OrderQuery{Status: StatusPending})
+	view.SetOrders(testOrders())
+	if view.Changed() != 0 {
+		t.Fatalf("Expected no changes after the first load, got %d", view.Changed())
+	}
+
+	// Another machine edits order 3 and adds order 4
+	orders := testOrders()
+	orders[2].Items[0].Quantity = 2
+	orders = append(orders, Order{ID: 4, CreatedAt: time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC)})
+	view.SetOrders(orders)
+	if !view.IsChanged(view.Row(3)) || !view.IsChanged(view.Row(4)) || view.IsChanged(view.Row(1)) {
+		t.Errorf("Expected orders 3 and 4 to be marked as changed")
+	}
+
+	// Widening the filter shows order 2 for the first time, unchanged
+	view.SetQuery(OrderQuery{})
+	view.SetOrders(orders)
+	if view.IsChanged(view.Row(2)) || view.Changed() != 2 {
+		t.Errorf("Expected only orders 3 and 4 changed, got %d", view.Changed())
+	}
+
+	view.MarkSeen(view.Row(3))
+	if view.IsChanged(view.Row(3)) || view.Changed() != 1 {
+		t.Errorf("Expected order 3 to be seen")
+	}
+	view.MarkAllSeen()
+	if view.Changed() != 0 {
+		t.Errorf("Expected all orders seen, got %d changed", view.Changed())
+	}
+}
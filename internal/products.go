@@ -3,6 +3,7 @@ package internal
 
 import (
 	"database/sql"
+	"fmt"
 )
 
 type Product struct {
@@ -35,3 +36,114 @@ func LoadProducts(db *sql.DB) ([]Product, error) {
 	}
 	return products, nil
 }
+
+// loadProduct returns a product by ID, including inactive ones
+func loadProduct(q queryer, id int64) (Product, error) {
+	rows, err := q.Query("SELECT id, name, price, active FROM products WHERE id = ?", id)
+	if err != nil {
+		return Product{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return Product{}, err
+		}
+		return Product{}, fmt.Errorf("product %d %w", id, ErrNotFound)
+	}
+	var p Product
+	err = rows.Scan(&p.ID, &p.Name, &p.Price, &p.Active)
+	return p, err
+}
+
+// AddProduct inserts an active product and returns its ID
+func AddProduct(db *sql.DB, name string, price float64) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO products (name, price, active) VALUES (?, ?, true)",
+		name, price)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	after := Product{ID: id, Name: name, Price: price, Active: true}
+	if err := RecordAudit(tx, EntityProduct, id, ActionCreate, nil, after); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	DefaultBus.Publish(ProductAdded{ProductID: id})
+	return id, nil
+}
+
+// UpdateProduct changes the name and price of a product
+func UpdateProduct(db *sql.DB, product Product) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := loadProduct(tx, product.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE products SET name = ?, price = ? WHERE id = ?",
+		product.Name, product.Price, product.ID)
+	if err != nil {
+		return err
+	}
+
+	after := before
+	after.Name, after.Price = product.Name, product.Price
+	if err := RecordAudit(tx, EntityProduct, product.ID, ActionUpdate, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	DefaultBus.Publish(ProductUpdated{ProductID: product.ID})
+	return nil
+}
+
+// DeactivateProduct hides a product from new orders
+func DeactivateProduct(db *sql.DB, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := loadProduct(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE products SET active = false WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	after := before
+	after.Active = false
+	if err := RecordAudit(tx, EntityProduct, id, ActionUpdate, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	DefaultBus.Publish(ProductDeactivated{ProductID: id})
+	return nil
+}
@@ -21,6 +21,25 @@ func setupTestDB(t *testing.T) *sql.DB {
 			name TEXT NOT NULL,
 			price REAL NOT NULL,
 			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			username TEXT NOT NULL UNIQUE COLLATE NOCASE,
+			password_hash TEXT NOT NULL,
+			role TEXT NOT NULL,
+			representative_id INTEGER,
+			active BOOLEAN DEFAULT true,
+			created_at DATETIME
+		);
+		CREATE TABLE audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			entity TEXT NOT NULL,
+			entity_id INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			before_json TEXT,
+			after_json TEXT,
+			user TEXT NOT NULL,
+			created_at DATETIME NOT NULL
 		)
 	`)
 	if err != nil {
@@ -64,3 +83,37 @@ func TestLoadProducts(t *testing.T) {
 		t.Errorf("Second product data incorrect: %+v", products[1])
 	}
 }
+
+func TestAddUpdateDeactivateProduct(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	id, err := AddProduct(db, "Muffin", 12.5)
+	if err != nil {
+		t.Fatalf("AddProduct failed: %v", err)
+	}
+
+	if err := UpdateProduct(db, Product{ID: id, Name: "Large Muffin", Price: 15}); err != nil {
+		t.Fatalf("UpdateProduct failed: %v", err)
+	}
+
+	products, err := LoadProducts(db)
+	if err != nil {
+		t.Fatalf("LoadProducts failed: %v", err)
+	}
+	if len(products) != 1 || products[0].Name != "Large Muffin" || products[0].Price != 15 {
+		t.Errorf("Unexpected products after update: %+v", products)
+	}
+
+	if err := DeactivateProduct(db, id); err != nil {
+		t.Fatalf("DeactivateProduct failed: %v", err)
+	}
+	products, _ = LoadProducts(db)
+	if len(products) != 0 {
+		t.Errorf("Expected no active products, got %+v", products)
+	}
+
+	if err := DeactivateProduct(db, id+1); err == nil {
+		t.Error("Expected error for unknown product")
+	}
+}
@@ -0,0 +1,132 @@
+// internal/reports/dashboard.go
+package reports
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// dashboardTopN is the number of products and representatives shown on the dashboard
+const dashboardTopN = 5
+
+// dashboardTrendDays is the number of days covered by the revenue trend
+const dashboardTrendDays = 30
+
+// Dashboard holds the key figures shown on the home screen
+type Dashboard struct {
+	DueToday    []internal.Order
+	DueThisWeek []internal.Order
+	Overdue     []internal.Order
+
+	RevenueThisMonth float64
+	RevenueLastMonth float64
+
+	TopProducts        []Line
+	TopRepresentatives []Line
+
+	// DailyRevenue has one line per day for the last 30 days, including days without sales
+	DailyRevenue []Line
+}
+
+// BuildDashboard collects the dashboard figures relative to now, with days
+// and months in the location of now
+func BuildDashboard(db *sql.DB, now time.Time) (Dashboard, error) {
+	var d Dashboard
+
+	orders, err := internal.LoadOrders(db)
+	if err != nil {
+		return d, err
+	}
+	d.DueToday, d.DueThisWeek, d.Overdue = classifyDueOrders(orders, now)
+
+	thisMonth := ThisMonth(now)
+	lastMonth := LastMonth(now)
+	trend := Range{From: Today(now).From.AddDate(0, 0, 1-dashboardTrendDays), To: Today(now).To}
+
+	from := lastMonth.From
+	if trend.From.Before(from) {
+		from = trend.From
+	}
+	sales, err := LoadSales(db, Range{From: from, To: thisMonth.To})
+	if err != nil {
+		return d, err
+	}
+
+	var monthSales, trendSales []Sale
+	for _, s := range sales {
+		switch {
+		case thisMonth.Contains(s.CreatedAt):
+			d.RevenueThisMonth += s.Amount
+			monthSales = append(monthSales, s)
+		case lastMonth.Contains(s.CreatedAt):
+			d.RevenueLastMonth += s.Amount
+		}
+		if trend.Contains(s.CreatedAt) {
+			trendSales = append(trendSales, s)
+		}
+	}
+
+	d.TopProducts = top(ByProduct(monthSales), dashboardTopN)
+	d.TopRepresentatives = top(ByRepresentative(monthSales), dashboardTopN)
+	d.DailyRevenue = fillDays(ByPeriod(trendSales, Daily, now.Location()), trend)
+
+	return d, nil
+}
+
+// classifyDueOrders splits open orders by their due day. Due dates are compared
+// by calendar day so that orders stored at midnight UTC are not shifted, and
+// due times count on their day in the location of now.
+func classifyDueOrders(orders []internal.Order, now time.Time) (today, week, overdue []internal.Order) {
+	todayRange := Today(now)
+	weekRange := ThisWeek(now)
+
+	for _, o := range orders {
+		if o.Completed {
+			continue
+		}
+		day := o.DueDate
+		if internal.HasDueTime(day) {
+			day = day.In(now.Location())
+		}
+		due := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, now.Location())
+		if due.Before(todayRange.From) {
+			overdue = append(overdue, o)
+			continue
+		}
+		if todayRange.Contains(due) {
+			today = append(today, o)
+		}
+		if weekRange.Contains(due) {
+			week = append(week, o)
+		}
+	}
+	return today, week, overdue
+}
+
+// fillDays returns one line per day of the range, using zero lines for days without sales
+func fillDays(lines []Line, r Range) []Line {
+	byLabel := make(map[string]Line, len(lines))
+	for _, l := range lines {
+		byLabel[l.Label] = l
+	}
+
+	var days []Line
+	for day := r.From; day.Before(r.To); day = day.AddDate(0, 0, 1) {
+		label := PeriodLabel(day, Daily)
+		line, ok := byLabel[label]
+		if !ok {
+			line = Line{Label: label}
+		}
+		days = append(days, line)
+	}
+	return days
+}
+
+func top(lines []Line, n int) []Line {
+	if len(lines) > n {
+		return lines[:n]
+	}
+	return lines
+}
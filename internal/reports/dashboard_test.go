@@ -0,0 +1,61 @@
+// internal/reports/dashboard_test.go
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+func TestClassifyDueOrders(t *testing.T) {
+	now := time.Date(2024, 5, 8, 15, 0, 0, 0, time.UTC) // Wednesday
+	orders := []internal.Order{
+		{ID: 1, DueDate: time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, DueDate: time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, DueDate: time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)},
+		{ID: 4, DueDate: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)},
+		{ID: 5, DueDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Completed: true},
+	}
+
+	today, week, overdue := classifyDueOrders(orders, now)
+
+	if len(today) != 1 || today[0].ID != 2 {
+		t.Errorf("Expected order 2 due today, got %+v", today)
+	}
+	if len(week) != 2 || week[0].ID != 2 || week[1].ID != 3 {
+		t.Errorf("Expected orders 2 and 3 due this week, got %+v", week)
+	}
+	if len(overdue) != 1 || overdue[0].ID != 1 {
+		t.Errorf("Expected order 1 to be overdue, got %+v", overdue)
+	}
+}
+
+func TestBuildDashboard(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedSales(t, db)
+
+	now := time.Date(2024, 5, 25, 12, 0, 0, 0, time.UTC)
+	d, err := BuildDashboard(db, now)
+	if err != nil {
+		t.Fatalf("BuildDashboard failed: %v", err)
+	}
+
+	if d.RevenueThisMonth != 90 || d.RevenueLastMonth != 25 {
+		t.Errorf("Unexpected revenue: this month %.2f, last month %.2f",
+			d.RevenueThisMonth, d.RevenueLastMonth)
+	}
+	if len(d.TopProducts) != 2 || d.TopProducts[0].Label != "Cake" {
+		t.Errorf("Unexpected top products: %+v", d.TopProducts)
+	}
+	if len(d.DailyRevenue) != 30 {
+		t.Fatalf("Expected 30 days of revenue, got %d", len(d.DailyRevenue))
+	}
+	if last := d.DailyRevenue[len(d.DailyRevenue)-1]; last.Label != "2024-05-25" {
+		t.Errorf("Expected trend to end today, got %s", last.Label)
+	}
+	if len(d.Overdue) != 3 {
+		t.Errorf("Expected all 3 seeded orders to be overdue, got %d", len(d.Overdue))
+	}
+}
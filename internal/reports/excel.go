@@ -0,0 +1,152 @@
+// internal/reports/excel.go
+package reports
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// currencyFormat displays numeric cells as Rand amounts while keeping them numbers
+const currencyFormat = `"R"#,##0.00`
+
+// ExportToExcel writes the report as a workbook with one sheet per breakdown
+func ExportToExcel(report Report, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#E0E0E0"},
+			Pattern: 1,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating header style: %w", err)
+	}
+
+	format := currencyFormat
+	moneyStyle, err := f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	if err != nil {
+		return fmt.Errorf("error creating currency style: %w", err)
+	}
+
+	// Built-in format 10 is 0.00%
+	percentStyle, err := f.NewStyle(&excelize.Style{NumFmt: 10})
+	if err != nil {
+		return fmt.Errorf("error creating percentage style: %w", err)
+	}
+
+	w := sheetWriter{
+		f:            f,
+		headerStyle:  headerStyle,
+		moneyStyle:   moneyStyle,
+		percentStyle: percentStyle,
+	}
+
+	// Summary sheet replaces the default sheet
+	f.SetSheetName("Sheet1", "Summary")
+	w.writeComparisons("Summary", []Comparison{report.Total}, "")
+	f.SetCellValue("Summary", "A4", "Current period")
+	f.SetCellValue("Summary", "B4", report.Range.String())
+	f.SetCellValue("Summary", "A5", "Previous period")
+	f.SetCellValue("Summary", "B5", report.Previous.String())
+
+	sheet := "By " + report.Granularity.String()
+	if _, err := f.NewSheet(sheet); err != nil {
+		return fmt.Errorf("error creating sheet %s: %w", sheet, err)
+	}
+	w.writeLines(sheet, report.Periods, report.Granularity.String())
+
+	breakdowns := []struct {
+		sheet       string
+		label       string
+		comparisons []Comparison
+	}{
+		{"By Product", "Product", report.Products},
+		{"By Representative", "Representative", report.Representatives},
+		{"By Customer", "Customer", report.Customers},
+	}
+	for _, b := range breakdowns {
+		if _, err := f.NewSheet(b.sheet); err != nil {
+			return fmt.Errorf("error creating sheet %s: %w", b.sheet, err)
+		}
+		w.writeComparisons(b.sheet, b.comparisons, b.label)
+	}
+
+	if err := f.SaveAs(filePath); err != nil {
+		return fmt.Errorf("error saving Excel file: %w", err)
+	}
+	return nil
+}
+
+type sheetWriter struct {
+	f            *excelize.File
+	headerStyle  int
+	moneyStyle   int
+	percentStyle int
+}
+
+func (w sheetWriter) writeHeader(sheet string, headers []string) {
+	for i, header := range headers {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		w.f.SetCellValue(sheet, col+"1", header)
+		w.f.SetColWidth(sheet, col, col, 16)
+	}
+	w.f.SetRowStyle(sheet, 1, 1, w.headerStyle)
+}
+
+func (w sheetWriter) writeLines(sheet string, lines []Line, label string) {
+	w.writeHeader(sheet, []string{label, "Orders", "Quantity", "Revenue"})
+	for i, l := range lines {
+		row := i + 2
+		w.f.SetSheetRow(sheet, fmt.Sprintf("A%d", row),
+			&[]interface{}{l.Label, l.Orders, l.Quantity, l.Revenue})
+		w.f.SetCellStyle(sheet, fmt.Sprintf("D%d", row), fmt.Sprintf("D%d", row), w.moneyStyle)
+	}
+}
+
+func (w sheetWriter) writeComparisons(sheet string, comparisons []Comparison, label string) {
+	headers := []string{
+		"Orders", "Quantity", "Revenue",
+		"Previous Orders", "Previous Quantity", "Previous Revenue",
+		"Change", "Change %",
+	}
+	if label != "" {
+		headers = append([]string{label}, headers...)
+	}
+	w.writeHeader(sheet, headers)
+
+	for i, c := range comparisons {
+		row := i + 2
+		values := []interface{}{
+			c.Current.Orders, c.Current.Quantity, c.Current.Revenue,
+			c.Previous.Orders, c.Previous.Quantity, c.Previous.Revenue,
+			c.Change(),
+		}
+		if pct, ok := c.ChangePercent(); ok {
+			values = append(values, pct/100)
+		} else {
+			values = append(values, "")
+		}
+		if label != "" {
+			values = append([]interface{}{c.Label}, values...)
+		}
+		w.f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &values)
+
+		// Revenue, previous revenue and change columns hold currency amounts
+		offset := len(headers) - 8
+		for _, col := range []int{3, 6, 7} {
+			name, _ := excelize.ColumnNumberToName(col + offset)
+			cell := fmt.Sprintf("%s%d", name, row)
+			w.f.SetCellStyle(sheet, cell, cell, w.moneyStyle)
+		}
+		name, _ := excelize.ColumnNumberToName(8 + offset)
+		cell := fmt.Sprintf("%s%d", name, row)
+		w.f.SetCellStyle(sheet, cell, cell, w.percentStyle)
+	}
+}
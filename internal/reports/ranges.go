@@ -0,0 +1,74 @@
+// internal/reports/ranges.go
+package reports
+
+import "time"
+
+// Range is a half-open time interval [From, To)
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// Contains reports whether t falls within the range
+func (r Range) Contains(t time.Time) bool {
+	return !t.Before(r.From) && t.Before(r.To)
+}
+
+// Previous returns the range of the same length immediately before r.
+// Ranges covering whole calendar months step back by whole months.
+func (r Range) Previous() Range {
+	if isMonthStart(r.From) && isMonthStart(r.To) {
+		months := (r.To.Year()-r.From.Year())*12 + int(r.To.Month()-r.From.Month())
+		return Range{From: r.From.AddDate(0, -months, 0), To: r.From}
+	}
+	return Range{From: r.From.Add(-r.To.Sub(r.From)), To: r.From}
+}
+
+// String formats the range for display, showing the inclusive last day
+func (r Range) String() string {
+	return r.From.Format("2006-01-02") + " to " + r.To.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+// Today returns the range covering the calendar day of now
+func Today(now time.Time) Range {
+	start := startOfDay(now)
+	return Range{From: start, To: start.AddDate(0, 0, 1)}
+}
+
+// ThisWeek returns the Monday-to-Sunday week containing now
+func ThisWeek(now time.Time) Range {
+	start := startOfDay(now)
+	offset := (int(start.Weekday()) + 6) % 7 // days since Monday
+	start = start.AddDate(0, 0, -offset)
+	return Range{From: start, To: start.AddDate(0, 0, 7)}
+}
+
+// ThisMonth returns the calendar month containing now
+func ThisMonth(now time.Time) Range {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return Range{From: start, To: start.AddDate(0, 1, 0)}
+}
+
+// LastMonth returns the calendar month before the one containing now
+func LastMonth(now time.Time) Range {
+	return ThisMonth(now).Previous()
+}
+
+// ThisYear returns the calendar year containing now
+func ThisYear(now time.Time) Range {
+	start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+	return Range{From: start, To: start.AddDate(1, 0, 0)}
+}
+
+// Days returns the range from the start of from's day to the end of to's day
+func Days(from, to time.Time) Range {
+	return Range{From: startOfDay(from), To: startOfDay(to).AddDate(0, 0, 1)}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func isMonthStart(t time.Time) bool {
+	return t.Day() == 1 && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
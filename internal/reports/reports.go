@@ -0,0 +1,301 @@
+// internal/reports/reports.go
+package reports
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Granularity controls how sales are bucketed over time
+type Granularity int
+
+const (
+	Daily Granularity = iota
+	Weekly
+	Monthly
+)
+
+func (g Granularity) String() string {
+	switch g {
+	case Weekly:
+		return "Week"
+	case Monthly:
+		return "Month"
+	default:
+		return "Day"
+	}
+}
+
+// Sale is a single order item together with the order details needed for reporting
+type Sale struct {
+	OrderID            int64
+	CreatedAt          time.Time
+	ClientName         string
+	RepresentativeName string
+	ProductName        string
+	Quantity           int
+	Amount             float64
+}
+
+// Line is one row of a sales summary
+type Line struct {
+	Label    string
+	Orders   int
+	Quantity int
+	Revenue  float64
+}
+
+// Comparison holds the same line for the current and the previous period
+type Comparison struct {
+	Label    string
+	Current  Line
+	Previous Line
+}
+
+// Change returns the revenue difference between the current and previous period
+func (c Comparison) Change() float64 {
+	return c.Current.Revenue - c.Previous.Revenue
+}
+
+// ChangePercent returns the revenue change as a percentage of the previous period.
+// ok is false when there was no revenue in the previous period.
+func (c Comparison) ChangePercent() (pct float64, ok bool) {
+	if c.Previous.Revenue == 0 {
+		return 0, false
+	}
+	return c.Change() / c.Previous.Revenue * 100, true
+}
+
+// Report is a complete sales summary for a date range
+type Report struct {
+	Range           Range
+	Previous        Range
+	Granularity     Granularity
+	Total           Comparison
+	Periods         []Line
+	Products        []Comparison
+	Representatives []Comparison
+	Customers       []Comparison
+}
+
+// LoadSales loads every order item created within the given range, leaving
+// out cancelled orders
+func LoadSales(db *sql.DB, r Range) ([]Sale, error) {
+	rows, err := db.Query(`
+        SELECT o.id, o.created_at, o.client_name, r.name,
+               p.name, oi.quantity, oi.price
+        FROM orders o
+        LEFT JOIN representatives r ON o.representative_id = r.id
+        LEFT JOIN order_items oi ON o.id = oi.order_id
+        LEFT JOIN products p ON oi.product_id = p.id
+        WHERE o.cancelled = false
+          AND datetime(o.created_at) >= ? AND datetime(o.created_at) < ?
+        ORDER BY o.created_at, o.id
+    `, internal.SQLTime(r.From), internal.SQLTime(r.To))
+	if err != nil {
+		return nil, fmt.Errorf("error querying sales: %w", err)
+	}
+	defer rows.Close()
+
+	var sales []Sale
+	for rows.Next() {
+		var (
+			s           Sale
+			clientName  sql.NullString
+			repName     sql.NullString
+			productName sql.NullString
+			quantity    sql.NullInt64
+			amount      sql.NullFloat64
+		)
+		err := rows.Scan(&s.OrderID, &s.CreatedAt, &clientName, &repName,
+			&productName, &quantity, &amount)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning sale: %w", err)
+		}
+		s.ClientName = clientName.String
+		s.RepresentativeName = repName.String
+		s.ProductName = productName.String
+		s.Quantity = int(quantity.Int64)
+		s.Amount = amount.Float64
+		sales = append(sales, s)
+	}
+	return sales, rows.Err()
+}
+
+// Build loads the sales for the range and the period before it and summarises them
+func Build(db *sql.DB, r Range, g Granularity) (Report, error) {
+	previous := r.Previous()
+
+	// Load both periods in one pass so the comparison uses a consistent snapshot
+	sales, err := LoadSales(db, Range{From: previous.From, To: r.To})
+	if err != nil {
+		return Report{}, err
+	}
+
+	var current, before []Sale
+	for _, s := range sales {
+		switch {
+		case r.Contains(s.CreatedAt):
+			current = append(current, s)
+		case previous.Contains(s.CreatedAt):
+			before = append(before, s)
+		}
+	}
+
+	return Summarise(r, g, current, before), nil
+}
+
+// Summarise builds a report from already loaded sales for the current and previous period
+func Summarise(r Range, g Granularity, current, previous []Sale) Report {
+	return Report{
+		Range:       r,
+		Previous:    r.Previous(),
+		Granularity: g,
+		Total: Comparison{
+			Label:    "Total",
+			Current:  total(current),
+			Previous: total(previous),
+		},
+		Periods:         ByPeriod(current, g, r.From.Location()),
+		Products:        Compare(ByProduct(current), ByProduct(previous)),
+		Representatives: Compare(ByRepresentative(current), ByRepresentative(previous)),
+		Customers:       Compare(ByCustomer(current), ByCustomer(previous)),
+	}
+}
+
+// ByPeriod groups sales by day, week or month of loc in chronological order.
+// Sales are stored in UTC, so they are moved to loc first to land on the day
+// the business made them.
+func ByPeriod(sales []Sale, g Granularity, loc *time.Location) []Line {
+	lines := group(sales, func(s Sale) string {
+		return PeriodLabel(s.CreatedAt.In(loc), g)
+	})
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Label < lines[j].Label
+	})
+	return lines
+}
+
+// ByProduct groups sales by product, highest revenue first
+func ByProduct(sales []Sale) []Line {
+	return byRevenue(group(sales, func(s Sale) string {
+		return s.ProductName
+	}))
+}
+
+// ByRepresentative groups sales by representative, highest revenue first
+func ByRepresentative(sales []Sale) []Line {
+	return byRevenue(group(sales, func(s Sale) string {
+		if s.RepresentativeName == "" {
+			return "Unassigned"
+		}
+		return s.RepresentativeName
+	}))
+}
+
+// ByCustomer groups sales by client name, highest revenue first
+func ByCustomer(sales []Sale) []Line {
+	return byRevenue(group(sales, func(s Sale) string {
+		return s.ClientName
+	}))
+}
+
+// Compare matches current and previous lines by label. Lines only present in
+// the previous period are kept so that lost sales remain visible.
+func Compare(current, previous []Line) []Comparison {
+	prev := make(map[string]Line, len(previous))
+	for _, l := range previous {
+		prev[l.Label] = l
+	}
+
+	var comparisons []Comparison
+	seen := make(map[string]bool, len(current))
+	for _, l := range current {
+		seen[l.Label] = true
+		comparisons = append(comparisons, Comparison{
+			Label:    l.Label,
+			Current:  l,
+			Previous: prev[l.Label],
+		})
+	}
+	for _, l := range previous {
+		if seen[l.Label] {
+			continue
+		}
+		comparisons = append(comparisons, Comparison{
+			Label:    l.Label,
+			Current:  Line{Label: l.Label},
+			Previous: l,
+		})
+	}
+	return comparisons
+}
+
+// PeriodLabel returns the sortable bucket label for t
+func PeriodLabel(t time.Time, g Granularity) string {
+	switch g {
+	case Weekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case Monthly:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// group sums sales per key, skipping sales without a key
+func group(sales []Sale, key func(Sale) string) []Line {
+	index := make(map[string]int)
+	orders := make(map[string]map[int64]bool)
+
+	var lines []Line
+	for _, s := range sales {
+		k := key(s)
+		if k == "" {
+			continue
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(lines)
+			index[k] = i
+			orders[k] = make(map[int64]bool)
+			lines = append(lines, Line{Label: k})
+		}
+		if !orders[k][s.OrderID] {
+			orders[k][s.OrderID] = true
+			lines[i].Orders++
+		}
+		lines[i].Quantity += s.Quantity
+		lines[i].Revenue += s.Amount
+	}
+	return lines
+}
+
+func total(sales []Sale) Line {
+	line := Line{Label: "Total"}
+	orders := make(map[int64]bool)
+	for _, s := range sales {
+		if !orders[s.OrderID] {
+			orders[s.OrderID] = true
+			line.Orders++
+		}
+		line.Quantity += s.Quantity
+		line.Revenue += s.Amount
+	}
+	return line
+}
+
+func byRevenue(lines []Line) []Line {
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i].Revenue != lines[j].Revenue {
+			return lines[i].Revenue > lines[j].Revenue
+		}
+		return lines[i].Label < lines[j].Label
+	})
+	return lines
+}
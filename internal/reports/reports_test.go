@@ -0,0 +1,262 @@
+// internal/reports/reports_test.go
+package reports
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+	"github.com/xuri/excelize/v2"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	// A named shared-cache database lets nested queries use a second connection
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			price REAL NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE representatives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at DATETIME,
+			due_date DATETIME,
+			client_name TEXT,
+			contact TEXT,
+			needs_delivery BOOLEAN,
+			delivery_address TEXT,
+			comment TEXT,
+			completed BOOLEAN,
+			cancelled BOOLEAN DEFAULT false,
+			version INTEGER NOT NULL DEFAULT 1,
+			representative_id INTEGER,
+			total_price REAL
+		);
+		CREATE TABLE order_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER,
+			product_id INTEGER,
+			quantity INTEGER,
+			price REAL
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test tables: %v", err)
+	}
+
+	return db
+}
+
+func insertOrder(t *testing.T, db *sql.DB, createdAt time.Time, client string, repID int64, items map[int64]int) {
+	prices := map[int64]float64{1: 10, 2: 25}
+
+	var total float64
+	for productID, qty := range items {
+		total += prices[productID] * float64(qty)
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO orders (created_at, due_date, client_name, contact, representative_id,
+			needs_delivery, delivery_address, comment, completed, total_price)
+		VALUES (?, ?, ?, '', ?, false, '', '', false, ?)`,
+		createdAt, createdAt, client, repID, total)
+	if err != nil {
+		t.Fatalf("Failed to insert order: %v", err)
+	}
+	orderID, _ := result.LastInsertId()
+
+	for productID, qty := range items {
+		_, err := db.Exec(`INSERT INTO order_items (order_id, product_id, quantity, price) VALUES (?, ?, ?, ?)`,
+			orderID, productID, qty, prices[productID]*float64(qty))
+		if err != nil {
+			t.Fatalf("Failed to insert order item: %v", err)
+		}
+	}
+}
+
+func seedSales(t *testing.T, db *sql.DB) {
+	_, err := db.Exec(`
+		INSERT INTO products (name, price) VALUES ('Muffin', 10), ('Cake', 25);
+		INSERT INTO representatives (name) VALUES ('Anna'), ('Ben');
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	// Two orders in May and one in April
+	insertOrder(t, db, time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), "Alice", 1, map[int64]int{1: 3})
+	insertOrder(t, db, time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC), "Bob", 2, map[int64]int{1: 1, 2: 2})
+	insertOrder(t, db, time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC), "Alice", 1, map[int64]int{2: 1})
+}
+
+func TestBuild(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedSales(t, db)
+
+	may := ThisMonth(time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC))
+	report, err := Build(db, may, Weekly)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if report.Total.Current.Orders != 2 || report.Total.Current.Revenue != 90 {
+		t.Errorf("Unexpected current total: %+v", report.Total.Current)
+	}
+	if report.Total.Previous.Orders != 1 || report.Total.Previous.Revenue != 25 {
+		t.Errorf("Unexpected previous total: %+v", report.Total.Previous)
+	}
+
+	if len(report.Periods) != 2 || report.Periods[0].Label != "2024-W18" {
+		t.Errorf("Unexpected weekly periods: %+v", report.Periods)
+	}
+
+	if len(report.Products) != 2 || report.Products[0].Label != "Cake" {
+		t.Fatalf("Expected Cake to be the top product, got %+v", report.Products)
+	}
+	if report.Products[0].Current.Revenue != 50 || report.Products[0].Previous.Revenue != 25 {
+		t.Errorf("Unexpected cake comparison: %+v", report.Products[0])
+	}
+	if pct, ok := report.Products[0].ChangePercent(); !ok || pct != 100 {
+		t.Errorf("Expected 100%% growth for cake, got %v (%v)", pct, ok)
+	}
+
+	if len(report.Customers) != 2 || report.Customers[0].Label != "Bob" {
+		t.Errorf("Unexpected customer breakdown: %+v", report.Customers)
+	}
+
+	if len(report.Representatives) != 2 || report.Representatives[1].Label != "Anna" {
+		t.Errorf("Unexpected representative breakdown: %+v", report.Representatives)
+	}
+}
+
+func TestByPeriod_BusinessTimeZone(t *testing.T) {
+	loc := time.FixedZone("SAST", 2*60*60)
+	sales := []Sale{
+		{OrderID: 1, CreatedAt: time.Date(2024, 4, 30, 22, 30, 0, 0, time.UTC), Quantity: 1, Amount: 10},
+		{OrderID: 2, CreatedAt: time.Date(2024, 4, 30, 21, 30, 0, 0, time.UTC), Quantity: 1, Amount: 20},
+	}
+
+	// 00:30 on 1 May in the business time zone is still April in UTC
+	days := ByPeriod(sales, Daily, loc)
+	if len(days) != 2 || days[0].Label != "2024-04-30" || days[1].Label != "2024-05-01" || days[1].Revenue != 10 {
+		t.Errorf("Expected one sale on each day, got %+v", days)
+	}
+	months := ByPeriod(sales, Monthly, loc)
+	if len(months) != 2 || months[1].Label != "2024-05" {
+		t.Errorf("Expected the late sale in May, got %+v", months)
+	}
+}
+
+func TestLoadSales_Range(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedSales(t, db)
+
+	// 01:00 in Johannesburg on 1 June is still May in UTC
+	johannesburg := time.FixedZone("SAST", 2*60*60)
+	insertOrder(t, db, time.Date(2024, 6, 1, 1, 0, 0, 0, johannesburg), "Carol", 1, map[int64]int{1: 1})
+
+	sales, err := LoadSales(db, Range{
+		From: time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("LoadSales failed: %v", err)
+	}
+	var clients []string
+	for _, sale := range sales {
+		clients = append(clients, sale.ClientName)
+	}
+	if fmt.Sprint(clients) != "[Bob Bob Carol]" {
+		t.Errorf("Expected Bob's items and Carol's, got %v", clients)
+	}
+}
+
+func TestCompare_KeepsPreviousOnlyLines(t *testing.T) {
+	current := []Line{{Label: "Muffin", Revenue: 10}}
+	previous := []Line{{Label: "Muffin", Revenue: 20}, {Label: "Cake", Revenue: 5}}
+
+	comparisons := Compare(current, previous)
+	if len(comparisons) != 2 {
+		t.Fatalf("Expected 2 comparisons, got %d", len(comparisons))
+	}
+	if comparisons[1].Label != "Cake" || comparisons[1].Current.Revenue != 0 {
+		t.Errorf("Expected lost product to be kept, got %+v", comparisons[1])
+	}
+	if comparisons[0].Change() != -10 {
+		t.Errorf("Expected change of -10, got %.2f", comparisons[0].Change())
+	}
+}
+
+func TestRangePrevious(t *testing.T) {
+	march := ThisMonth(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	prev := march.Previous()
+	if !prev.From.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) || !prev.To.Equal(march.From) {
+		t.Errorf("Expected February as previous month, got %s", prev)
+	}
+
+	week := ThisWeek(time.Date(2024, 5, 8, 15, 0, 0, 0, time.UTC)) // Wednesday
+	if week.From.Weekday() != time.Monday || week.From.Day() != 6 {
+		t.Errorf("Expected week to start on Monday 6 May, got %s", week.From)
+	}
+	if got := week.Previous().From; got.Day() != 29 || got.Month() != time.April {
+		t.Errorf("Expected previous week to start 29 April, got %s", got)
+	}
+}
+
+func TestExportToExcel(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedSales(t, db)
+
+	report, err := Build(db, ThisMonth(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), Daily)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := ExportToExcel(report, path); err != nil {
+		t.Fatalf("ExportToExcel failed: %v", err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open Excel file: %v", err)
+	}
+	defer f.Close()
+
+	expectedSheets := []string{"Summary", "By Day", "By Product", "By Representative", "By Customer"}
+	sheets := f.GetSheetList()
+	if len(sheets) != len(expectedSheets) {
+		t.Fatalf("Expected sheets %v, got %v", expectedSheets, sheets)
+	}
+	for i, name := range expectedSheets {
+		if sheets[i] != name {
+			t.Errorf("Expected sheet %d to be %s, got %s", i, name, sheets[i])
+		}
+	}
+
+	// Revenue must be stored as a number, not a formatted string
+	raw, err := f.GetCellValue("By Product", "D2", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatalf("Failed to read cell: %v", err)
+	}
+	if raw != "50" {
+		t.Errorf("Expected raw revenue 50, got %q", raw)
+	}
+}
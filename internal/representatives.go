@@ -1,7 +1,10 @@
 // internal/representatives.go
 package internal
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+)
 
 type Representative struct {
 	ID     int64
@@ -32,3 +35,81 @@ func LoadRepresentatives(db *sql.DB) ([]Representative, error) {
 	}
 	return representatives, nil
 }
+
+// loadRepresentative returns a representative by ID, including inactive ones
+func loadRepresentative(q queryer, id int64) (Representative, error) {
+	rows, err := q.Query("SELECT id, name, active FROM representatives WHERE id = ?", id)
+	if err != nil {
+		return Representative{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return Representative{}, err
+		}
+		return Representative{}, fmt.Errorf("representative %d %w", id, ErrNotFound)
+	}
+	var r Representative
+	err = rows.Scan(&r.ID, &r.Name, &r.Active)
+	return r, err
+}
+
+// AddRepresentative inserts an active representative and returns its ID
+func AddRepresentative(db *sql.DB, name string) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO representatives (name, active) VALUES (?, true)", name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	after := Representative{ID: id, Name: name, Active: true}
+	if err := RecordAudit(tx, EntityRepresentative, id, ActionCreate, nil, after); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	DefaultBus.Publish(RepresentativeAdded{RepresentativeID: id})
+	return id, nil
+}
+
+// DeactivateRepresentative hides a representative from new orders
+func DeactivateRepresentative(db *sql.DB, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := loadRepresentative(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE representatives SET active = false WHERE id = ?", id); err != nil {
+		return err
+	}
+
+	after := before
+	after.Active = false
+	if err := RecordAudit(tx, EntityRepresentative, id, ActionUpdate, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	DefaultBus.Publish(RepresentativeDeactivated{RepresentativeID: id})
+	return nil
+}
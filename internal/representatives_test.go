@@ -51,3 +51,39 @@ func TestLoadRepresentatives(t *testing.T) {
 		t.Errorf("Second representative data incorrect: %+v", reps[1])
 	}
 }
+
+func TestAddDeactivateRepresentative(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	_, err := db.Exec(`
+		CREATE TABLE representatives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			active BOOLEAN DEFAULT true
+		)
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create representatives table: %v", err)
+	}
+
+	id, err := AddRepresentative(db, "John Doe")
+	if err != nil {
+		t.Fatalf("AddRepresentative failed: %v", err)
+	}
+	if err := DeactivateRepresentative(db, id); err != nil {
+		t.Fatalf("DeactivateRepresentative failed: %v", err)
+	}
+
+	reps, err := LoadRepresentatives(db)
+	if err != nil {
+		t.Fatalf("LoadRepresentatives failed: %v", err)
+	}
+	if len(reps) != 0 {
+		t.Errorf("Expected no active representatives, got %+v", reps)
+	}
+
+	if err := DeactivateRepresentative(db, id+1); err == nil {
+		t.Error("Expected error for unknown representative")
+	}
+}
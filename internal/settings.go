@@ -0,0 +1,77 @@
+// internal/settings.go
+package internal
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// LoadSetting decodes the JSON setting stored under key into v and reports
+// whether it was set. Settings are kept in the database, so that every
+// machine using it shares them.
+func LoadSetting(db *sql.DB, key string, v interface{}) (bool, error) {
+	var value string
+	err := db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error loading setting %s: %w", key, err)
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return false, fmt.Errorf("error reading setting %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// SaveSetting stores v as JSON under key
+func SaveSetting(db *sql.DB, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error encoding setting %s: %w", key, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var before interface{}
+	var old string
+	err = tx.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&old)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("error loading setting %s: %w", key, err)
+	default:
+		before = json.RawMessage(old)
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO settings (key, value) VALUES (?, ?)
+        ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, string(data))
+	if err != nil {
+		return fmt.Errorf("error saving setting %s: %w", key, err)
+	}
+
+	action := ActionUpdate
+	if before == nil {
+		action = ActionCreate
+	}
+	after := map[string]interface{}{"Key": key, "Value": json.RawMessage(data)}
+	if before != nil {
+		before = map[string]interface{}{"Key": key, "Value": before}
+	}
+	if err := RecordAudit(tx, EntitySetting, 0, action, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	DefaultBus.Publish(SettingChanged{Key: key})
+	return nil
+}
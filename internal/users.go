@@ -0,0 +1,325 @@
+// internal/users.go
+package internal
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Role decides what a user may do
+type Role string
+
+const (
+	RoleAdmin          Role = "admin"
+	RoleManager        Role = "manager"
+	RoleRepresentative Role = "representative"
+	RoleKitchen        Role = "kitchen"
+)
+
+// Roles lists the roles from most to least privileged
+var Roles = []Role{RoleAdmin, RoleManager, RoleRepresentative, RoleKitchen}
+
+// Permission is an action that only some roles may take
+type Permission string
+
+const (
+	PermManageUsers           Permission = "manage_users"
+	PermManageSettings        Permission = "manage_settings"
+	PermManageProducts        Permission = "manage_products"
+	PermManageRepresentatives Permission = "manage_representatives"
+	PermImportData            Permission = "import_data"
+	PermViewReports           Permission = "view_reports"
+	PermViewAuditLog          Permission = "view_audit_log"
+	PermExportOrders          Permission = "export_orders"
+	PermCreateOrders          Permission = "create_orders"
+	PermEditAllOrders         Permission = "edit_all_orders"
+	PermEditOwnOrders         Permission = "edit_own_orders"
+	PermCompleteOrders        Permission = "complete_orders"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin: {
+		PermManageUsers, PermManageSettings, PermManageProducts, PermManageRepresentatives,
+		PermImportData, PermViewReports, PermViewAuditLog, PermExportOrders,
+		PermCreateOrders, PermEditAllOrders, PermCompleteOrders,
+	},
+	RoleManager: {
+		PermManageProducts, PermManageRepresentatives, PermImportData, PermViewReports,
+		PermViewAuditLog, PermExportOrders, PermCreateOrders, PermEditAllOrders, PermCompleteOrders,
+	},
+	RoleRepresentative: {PermCreateOrders, PermEditOwnOrders},
+	RoleKitchen:        {PermCompleteOrders},
+}
+
+// MinPasswordLength is the shortest password accepted for an account
+const MinPasswordLength = 8
+
+// ErrInvalidCredentials is returned for an unknown user, a wrong password or a
+// deactivated account, without telling which
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// User is a local account. Representatives are linked to the representative
+// whose orders they may edit.
+type User struct {
+	ID               int64
+	Username         string
+	Role             Role
+	RepresentativeID int64
+	Active           bool
+	CreatedAt        time.Time
+}
+
+// Can reports whether the user's role grants p
+func (u User) Can(p Permission) bool {
+	for _, granted := range rolePermissions[u.Role] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// CanEditOrder reports whether the user may edit o
+func (u User) CanEditOrder(o Order) bool {
+	if u.Can(PermEditAllOrders) {
+		return true
+	}
+	return u.Can(PermEditOwnOrders) && u.RepresentativeID != 0 && o.RepresentativeID == u.RepresentativeID
+}
+
+func (u User) validate() error {
+	if strings.TrimSpace(u.Username) == "" {
+		return fmt.Errorf("username is required")
+	}
+	if _, ok := rolePermissions[u.Role]; !ok {
+		return fmt.Errorf("unknown role %q", u.Role)
+	}
+	if u.Role == RoleRepresentative && u.RepresentativeID == 0 {
+		return fmt.Errorf("representative accounts must be linked to a representative")
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// HasUsers reports whether any account exists, so the first one can be set up
+func HasUsers(db *sql.DB) (bool, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count); err != nil {
+		return false, fmt.Errorf("error loading users: %w", err)
+	}
+	return count > 0, nil
+}
+
+// LoadUsers returns every account ordered by username
+func LoadUsers(db *sql.DB) ([]User, error) {
+	rows, err := db.Query(`
+        SELECT id, username, role, representative_id, active, created_at
+        FROM users
+        ORDER BY username
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("error loading users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUser reads the user columns followed by any extra columns
+func scanUser(row scanner, extra ...interface{}) (User, error) {
+	var u User
+	var repID sql.NullInt64
+	dest := append([]interface{}{&u.ID, &u.Username, &u.Role, &repID, &u.Active, &u.CreatedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return User{}, err
+	}
+	u.RepresentativeID = repID.Int64
+	return u, nil
+}
+
+func loadUser(q queryer, id int64) (User, error) {
+	rows, err := q.Query(`
+        SELECT id, username, role, representative_id, active, created_at
+        FROM users WHERE id = ?`, id)
+	if err != nil {
+		return User{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return User{}, err
+		}
+		return User{}, fmt.Errorf("user %d %w", id, ErrNotFound)
+	}
+	u, err := scanUser(rows)
+	return u, err
+}
+
+// CreateUser adds an active account with the given password and returns its ID
+func CreateUser(db *sql.DB, user User, password string) (int64, error) {
+	user.Username = strings.TrimSpace(user.Username)
+	if err := user.validate(); err != nil {
+		return 0, err
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	user.Active, user.CreatedAt = true, time.Now()
+	result, err := tx.Exec(`
+        INSERT INTO users (username, password_hash, role, representative_id, active, created_at)
+        VALUES (?, ?, ?, ?, ?, ?)`,
+		user.Username, hash, user.Role, nullID(user.RepresentativeID), user.Active, user.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("error adding user %q: %w", user.Username, err)
+	}
+	if user.ID, err = result.LastInsertId(); err != nil {
+		return 0, err
+	}
+
+	if err := RecordAudit(tx, EntityUser, user.ID, ActionCreate, nil, user); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+// UpdateUser changes the role, representative and active state of an account.
+// The last active admin cannot be demoted or deactivated.
+func UpdateUser(db *sql.DB, user User) error {
+	if err := user.validate(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := loadUser(tx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if before.Role == RoleAdmin && before.Active && (user.Role != RoleAdmin || !user.Active) {
+		var admins int
+		err := tx.QueryRow("SELECT COUNT(*) FROM users WHERE role = ? AND active = true", RoleAdmin).Scan(&admins)
+		if err != nil {
+			return err
+		}
+		if admins <= 1 {
+			return fmt.Errorf("%s is the last active admin", before.Username)
+		}
+	}
+
+	_, err = tx.Exec("UPDATE users SET role = ?, representative_id = ?, active = ? WHERE id = ?",
+		user.Role, nullID(user.RepresentativeID), user.Active, user.ID)
+	if err != nil {
+		return err
+	}
+
+	after := before
+	after.Role, after.RepresentativeID, after.Active = user.Role, user.RepresentativeID, user.Active
+	if err := RecordAudit(tx, EntityUser, user.ID, ActionUpdate, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetPassword replaces the password of an account
+func SetPassword(db *sql.DB, id int64, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	user, err := loadUser(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE users SET password_hash = ? WHERE id = ?", hash, id); err != nil {
+		return err
+	}
+
+	// The hash itself is never written to the audit log
+	change := map[string]string{"Password": "changed"}
+	if err := RecordAudit(tx, EntityUser, user.ID, ActionUpdate, nil, change); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// dummyHash is compared against when the user does not exist, so unknown
+// usernames take as long to reject as wrong passwords
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("orderflow-dummy-password"), bcrypt.DefaultCost)
+
+// Authenticate returns the active account matching username and password
+func Authenticate(db *sql.DB, username, password string) (User, error) {
+	row := db.QueryRow(`
+        SELECT id, username, role, representative_id, active, created_at, password_hash
+        FROM users WHERE username = ?`, strings.TrimSpace(username))
+
+	var hash string
+	user, err := scanUser(row, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, fmt.Errorf("error loading user: %w", err)
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil || !user.Active {
+		return User{}, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// nullID stores an unset ID as NULL
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -0,0 +1,112 @@
+// internal/users_test.go
+package internal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateUserAndAuthenticate(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if has, err := HasUsers(db); err != nil || has {
+		t.Fatalf("Expected no users, got %v (%v)", has, err)
+	}
+
+	for _, tc := range []struct {
+		user     User
+		password string
+	}{
+		{User{Username: "", Role: RoleAdmin}, "long enough"},
+		{User{Username: "anna", Role: "owner"}, "long enough"},
+		{User{Username: "anna", Role: RoleRepresentative}, "long enough"},
+		{User{Username: "anna", Role: RoleAdmin}, "short"},
+	} {
+		if _, err := CreateUser(db, tc.user, tc.password); err == nil {
+			t.Errorf("Expected error for %+v", tc.user)
+		}
+	}
+
+	id, err := CreateUser(db, User{Username: " Anna ", Role: RoleAdmin}, "correct horse")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if _, err := CreateUser(db, User{Username: "anna", Role: RoleKitchen}, "correct horse"); err == nil {
+		t.Error("Expected duplicate username to fail")
+	}
+
+	user, err := Authenticate(db, "Anna", "correct horse")
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if user.ID != id || user.Role != RoleAdmin || !user.Active {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	for _, login := range [][2]string{{"Anna", "wrong password"}, {"bob", "correct horse"}} {
+		if _, err := Authenticate(db, login[0], login[1]); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Expected invalid credentials for %s, got %v", login[0], err)
+		}
+	}
+
+	// The password hash never reaches the audit log
+	entries, err := LoadAuditLog(db, AuditFilter{Entity: EntityUser})
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected 1 user audit entry, got %d (%v)", len(entries), err)
+	}
+	if strings.Contains(entries[0].After, "$2a$") {
+		t.Errorf("Audit entry contains the password hash: %s", entries[0].After)
+	}
+}
+
+func TestUpdateUser_KeepsLastAdmin(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	adminID, err := CreateUser(db, User{Username: "admin", Role: RoleAdmin}, "admin password")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if err := UpdateUser(db, User{ID: adminID, Username: "admin", Role: RoleManager, Active: true}); err == nil {
+		t.Error("Expected demoting the last admin to fail")
+	}
+
+	cookID, err := CreateUser(db, User{Username: "cook", Role: RoleKitchen}, "kitchen password")
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if err := UpdateUser(db, User{ID: cookID, Username: "cook", Role: RoleKitchen}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+	if _, err := Authenticate(db, "cook", "kitchen password"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected deactivated user to be rejected, got %v", err)
+	}
+
+	if err := SetPassword(db, adminID, "new admin password"); err != nil {
+		t.Fatalf("SetPassword failed: %v", err)
+	}
+	if _, err := Authenticate(db, "admin", "new admin password"); err != nil {
+		t.Errorf("Expected new password to work: %v", err)
+	}
+}
+
+func TestUserPermissions(t *testing.T) {
+	rep := User{Role: RoleRepresentative, RepresentativeID: 2}
+	kitchen := User{Role: RoleKitchen}
+	manager := User{Role: RoleManager}
+
+	if rep.Can(PermManageProducts) || !rep.Can(PermCreateOrders) {
+		t.Error("Representatives create orders but cannot change products")
+	}
+	if !rep.CanEditOrder(Order{RepresentativeID: 2}) || rep.CanEditOrder(Order{RepresentativeID: 3}) {
+		t.Error("Representatives edit only their own orders")
+	}
+	if kitchen.CanEditOrder(Order{}) || !kitchen.Can(PermCompleteOrders) {
+		t.Error("Kitchen completes orders but cannot edit them")
+	}
+	if !manager.Can(PermManageProducts) || manager.Can(PermManageUsers) || manager.Can(PermManageSettings) {
+		t.Error("Managers manage products but not users or settings")
+	}
+}
@@ -0,0 +1,296 @@
+// internal/webhooks/dispatcher.go
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSending   = "sending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Retry policy. The delay doubles after every failed attempt.
+const (
+	MaxAttempts  = 8
+	InitialDelay = 30 * time.Second
+	MaxDelay     = 6 * time.Hour
+
+	// ClaimTimeout is how long a delivery stays claimed by the dispatcher
+	// sending it. A delivery still sending after that, e.g. because the
+	// computer sending it was switched off, is sent again.
+	ClaimTimeout = 2 * time.Minute
+)
+
+// Delivery is one queued or sent webhook call
+type Delivery struct {
+	ID             int64
+	EndpointID     int64
+	EndpointURL    string
+	Event          string
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+}
+
+// Backoff returns the delay before the next attempt after attempts failures
+func Backoff(attempts int) time.Duration {
+	delay := InitialDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= MaxDelay {
+			return MaxDelay
+		}
+	}
+	return delay
+}
+
+// Dispatcher sends queued deliveries
+type Dispatcher struct {
+	db     *sql.DB
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewDispatcher creates a dispatcher sending with a 10 second timeout
+func NewDispatcher(db *sql.DB) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+}
+
+// Run delivers due deliveries every interval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil {
+			log.Printf("Error delivering webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends every pending delivery whose next attempt is due and
+// returns how many were delivered successfully
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	due, err := d.dueDeliveries()
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		ok, err := d.attempt(ctx, delivery)
+		if err != nil {
+			return delivered, err
+		}
+		if ok {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+type dueDelivery struct {
+	Delivery
+	secret string
+}
+
+func (d *Dispatcher) dueDeliveries() ([]dueDelivery, error) {
+	rows, err := d.db.Query(`
+        SELECT wd.id, wd.endpoint_id, we.url, we.secret, wd.event, wd.payload, wd.status, wd.attempts
+        FROM webhook_deliveries wd
+        JOIN webhook_endpoints we ON wd.endpoint_id = we.id
+        WHERE wd.status IN (?, ?) AND datetime(wd.next_attempt_at) <= ?
+        ORDER BY wd.id
+    `, StatusPending, StatusSending, internal.SQLTime(d.now()))
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var due []dueDelivery
+	for rows.Next() {
+		var dd dueDelivery
+		err := rows.Scan(&dd.ID, &dd.EndpointID, &dd.EndpointURL, &dd.secret,
+			&dd.Event, &dd.Payload, &dd.Status, &dd.Attempts)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+		due = append(due, dd)
+	}
+	return due, rows.Err()
+}
+
+// claim marks a delivery as being sent and counts the attempt. Every computer
+// sharing the database runs a dispatcher, so only the one whose update still
+// finds the delivery as it was read may send it. Deliveries claimed by another
+// dispatcher report false.
+func (d *Dispatcher) claim(delivery dueDelivery) (bool, error) {
+	res, err := d.db.Exec(`
+        UPDATE webhook_deliveries
+        SET status = ?, attempts = attempts + 1, next_attempt_at = ?
+        WHERE id = ? AND status = ? AND attempts = ?`,
+		StatusSending, d.now().Add(ClaimTimeout), delivery.ID, delivery.Status, delivery.Attempts)
+	if err != nil {
+		return false, fmt.Errorf("error claiming webhook delivery: %w", err)
+	}
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error claiming webhook delivery: %w", err)
+	}
+	return claimed == 1, nil
+}
+
+// attempt claims and sends one delivery and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery dueDelivery) (bool, error) {
+	claimed, err := d.claim(delivery)
+	if err != nil || !claimed {
+		return false, err
+	}
+	attempts := delivery.Attempts + 1
+
+	var statusCode int
+	var sendErr error
+	if attempts > MaxAttempts {
+		// The last attempt was claimed but never finished
+		sendErr = fmt.Errorf("sending did not finish within %s", ClaimTimeout)
+	} else {
+		statusCode, sendErr = d.send(ctx, delivery)
+	}
+	now := d.now()
+
+	if sendErr == nil {
+		_, err := d.db.Exec(`
+            UPDATE webhook_deliveries
+            SET status = ?, last_status_code = ?, last_error = '', delivered_at = ?
+            WHERE id = ? AND status = ? AND attempts = ?`,
+			StatusDelivered, statusCode, now, delivery.ID, StatusSending, attempts)
+		if err != nil {
+			return false, fmt.Errorf("error recording webhook delivery: %w", err)
+		}
+		return true, nil
+	}
+
+	status := StatusPending
+	if attempts >= MaxAttempts {
+		status = StatusFailed
+	}
+	_, err = d.db.Exec(`
+        UPDATE webhook_deliveries
+        SET status = ?, last_status_code = ?, last_error = ?, next_attempt_at = ?
+        WHERE id = ? AND status = ? AND attempts = ?`,
+		status, statusCode, sendErr.Error(), now.Add(Backoff(attempts)), delivery.ID, StatusSending, attempts)
+	if err != nil {
+		return false, fmt.Errorf("error recording webhook failure: %w", err)
+	}
+	return false, nil
+}
+
+// send posts the payload and treats any 2xx response as success
+func (d *Dispatcher) send(ctx context.Context, delivery dueDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.EndpointURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OrderFlow-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// LoadDeliveries returns the most recent deliveries, newest first
+func LoadDeliveries(db *sql.DB, limit int) ([]Delivery, error) {
+	rows, err := db.Query(`
+        SELECT wd.id, wd.endpoint_id, COALESCE(we.url, ''), wd.event, wd.payload, wd.status,
+               wd.attempts, wd.next_attempt_at, wd.last_status_code, wd.last_error,
+               wd.created_at, wd.delivered_at
+        FROM webhook_deliveries wd
+        LEFT JOIN webhook_endpoints we ON wd.endpoint_id = we.id
+        ORDER BY wd.id DESC
+        LIMIT ?
+    `, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var (
+			d                                   Delivery
+			nextAttemptAt, createdAt, delivered sql.NullTime
+			lastError                           sql.NullString
+		)
+		err := rows.Scan(&d.ID, &d.EndpointID, &d.EndpointURL, &d.Event, &d.Payload, &d.Status,
+			&d.Attempts, &nextAttemptAt, &d.LastStatusCode, &lastError, &createdAt, &delivered)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+		d.NextAttemptAt = nextAttemptAt.Time
+		d.LastError = lastError.String
+		d.CreatedAt = createdAt.Time
+		d.DeliveredAt = delivered.Time
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Retry queues a failed delivery to be sent again on the next run
+func Retry(db *sql.DB, id int64) error {
+	_, err := db.Exec(`
+        UPDATE webhook_deliveries
+        SET status = ?, attempts = 0, next_attempt_at = ?
+        WHERE id = ? AND status = ?`,
+		StatusPending, time.Now(), id, StatusFailed)
+	if err != nil {
+		return fmt.Errorf("error retrying webhook delivery: %w", err)
+	}
+	return nil
+}
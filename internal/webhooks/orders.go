@@ -0,0 +1,107 @@
+// internal/webhooks/orders.go
+package webhooks
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// OrderData is the data of order events
+type OrderData struct {
+	ID               int64           `json:"id"`
+	CreatedAt        time.Time       `json:"created_at"`
+	DueDate          string          `json:"due_date"`
+	ClientName       string          `json:"client_name"`
+	Contact          string          `json:"contact"`
+	RepresentativeID int64           `json:"representative_id"`
+	Representative   string          `json:"representative"`
+	NeedsDelivery    bool            `json:"needs_delivery"`
+	DeliveryAddress  string          `json:"delivery_address"`
+	Comment          string          `json:"comment"`
+	Completed        bool            `json:"completed"`
+	Cancelled        bool            `json:"cancelled"`
+	TotalPrice       float64         `json:"total_price"`
+	Items            []OrderItemData `json:"items"`
+}
+
+// OrderItemData is an item of OrderData
+type OrderItemData struct {
+	ProductID int64   `json:"product_id"`
+	Product   string  `json:"product"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// NewOrderData converts an order to its event data. The due date has its time
+// of day, if any, in the time zone of the business.
+func NewOrderData(o internal.Order, hours internal.BusinessHours) OrderData {
+	items := []OrderItemData{}
+	for _, item := range o.Items {
+		items = append(items, OrderItemData{
+			ProductID: item.ProductID,
+			Product:   item.ProductName,
+			Quantity:  item.Quantity,
+			Price:     item.Price,
+		})
+	}
+	return OrderData{
+		ID:               o.ID,
+		CreatedAt:        o.CreatedAt,
+		DueDate:          hours.FormatDueRFC3339(o.DueDate),
+		ClientName:       o.ClientName,
+		Contact:          o.Contact,
+		RepresentativeID: o.RepresentativeID,
+		Representative:   o.RepresentativeName,
+		NeedsDelivery:    o.NeedsDelivery,
+		DeliveryAddress:  o.DeliveryAddress,
+		Comment:          o.Comment,
+		Completed:        o.Completed,
+		Cancelled:        o.Cancelled,
+		TotalPrice:       o.TotalPrice,
+		Items:            items,
+	}
+}
+
+// Subscribe queues order events published on bus for the endpoints stored in
+// db, including those of the orders changed by an import or workbook
+func Subscribe(bus *internal.Bus, db *sql.DB) (unsubscribe func()) {
+	var notify func(e internal.Event)
+	notify = func(e internal.Event) {
+		switch e := e.(type) {
+		case internal.OrderCreated:
+			NotifyOrder(db, EventOrderCreated, e.OrderID)
+		case internal.OrderEdited:
+			NotifyOrder(db, EventOrderEdited, e.OrderID)
+		case internal.OrderCompleted:
+			NotifyOrder(db, EventOrderCompleted, e.OrderID)
+		case internal.OrderCancelled:
+			NotifyOrder(db, EventOrderCancelled, e.OrderID)
+		case internal.DataImported:
+			for _, order := range e.Orders {
+				notify(order)
+			}
+		}
+	}
+	return bus.Subscribe(notify)
+}
+
+// NotifyOrder queues event for the order with the given ID. It runs after the
+// change was committed, so failures are logged rather than returned.
+func NotifyOrder(db *sql.DB, event string, orderID int64) {
+	order, err := internal.LoadOrder(db, orderID)
+	if err != nil {
+		log.Printf("Error loading order %d for webhook: %v", orderID, err)
+		return
+	}
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		log.Printf("Error loading business hours for webhook: %v", err)
+		return
+	}
+	if err := Enqueue(db, event, NewOrderData(order, hours)); err != nil {
+		log.Printf("Error queueing %s webhook: %v", event, err)
+	}
+}
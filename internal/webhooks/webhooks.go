@@ -0,0 +1,308 @@
+// internal/webhooks/webhooks.go
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+)
+
+// Event types that endpoints can subscribe to
+const (
+	EventOrderCreated   = "order.created"
+	EventOrderEdited    = "order.edited"
+	EventOrderCompleted = "order.completed"
+	EventOrderCancelled = "order.cancelled"
+)
+
+// Events lists all event types
+var Events = []string{EventOrderCreated, EventOrderEdited, EventOrderCompleted, EventOrderCancelled}
+
+// Headers sent with every delivery. The signature is the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the endpoint secret.
+const (
+	HeaderEvent     = "X-OrderFlow-Event"
+	HeaderDelivery  = "X-OrderFlow-Delivery"
+	HeaderTimestamp = "X-OrderFlow-Timestamp"
+	HeaderSignature = "X-OrderFlow-Signature"
+)
+
+// Endpoint is a URL that receives the events it subscribed to
+type Endpoint struct {
+	ID        int64
+	URL       string
+	Secret    string
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+}
+
+// Subscribed reports whether the endpoint receives event
+func (e Endpoint) Subscribed(event string) bool {
+	for _, subscribed := range e.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body of a delivery
+type Payload struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Sign returns the signature header value for a delivery body
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature produced by Sign, for use by receivers and tests
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// validate checks the URL and event types of an endpoint
+func (e Endpoint) validate() error {
+	u, err := url.Parse(strings.TrimSpace(e.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook URL must be an http or https URL")
+	}
+	if len(e.Events) == 0 {
+		return fmt.Errorf("select at least one event")
+	}
+	for _, event := range e.Events {
+		known := false
+		for _, e := range Events {
+			known = known || e == event
+		}
+		if !known {
+			return fmt.Errorf("unknown webhook event %q", event)
+		}
+	}
+	return nil
+}
+
+// LoadEndpoints returns all endpoints, active or not
+func LoadEndpoints(db *sql.DB) ([]Endpoint, error) {
+	rows, err := db.Query(`
+        SELECT id, url, secret, events, active, created_at
+        FROM webhook_endpoints
+        ORDER BY id
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var endpoints []Endpoint
+	for rows.Next() {
+		var (
+			e         Endpoint
+			events    string
+			createdAt sql.NullTime
+		)
+		if err := rows.Scan(&e.ID, &e.URL, &e.Secret, &events, &e.Active, &createdAt); err != nil {
+			return nil, fmt.Errorf("error scanning webhook endpoint: %w", err)
+		}
+		e.Events = splitEvents(events)
+		e.CreatedAt = createdAt.Time
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, rows.Err()
+}
+
+// auditEndpoint is an endpoint as recorded in the audit log. The secret is
+// never written to it.
+type auditEndpoint struct {
+	ID     int64
+	URL    string
+	Events []string
+	Active bool
+}
+
+func (e Endpoint) audited() auditEndpoint {
+	return auditEndpoint{ID: e.ID, URL: e.URL, Events: e.Events, Active: e.Active}
+}
+
+// loadEndpoint returns the endpoint with id within tx
+func loadEndpoint(tx *sql.Tx, id int64) (Endpoint, error) {
+	var (
+		e         Endpoint
+		events    string
+		createdAt sql.NullTime
+	)
+	err := tx.QueryRow(`
+        SELECT id, url, secret, events, active, created_at
+        FROM webhook_endpoints
+        WHERE id = ?`, id).Scan(&e.ID, &e.URL, &e.Secret, &events, &e.Active, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, fmt.Errorf("webhook endpoint %d not found", id)
+	}
+	if err != nil {
+		return e, fmt.Errorf("error loading webhook endpoint %d: %w", id, err)
+	}
+	e.Events = splitEvents(events)
+	e.CreatedAt = createdAt.Time
+	return e, nil
+}
+
+// AddEndpoint stores a new active endpoint, generating a secret when none is
+// given, and returns it with its ID
+func AddEndpoint(db *sql.DB, e Endpoint) (Endpoint, error) {
+	e.URL = strings.TrimSpace(e.URL)
+	if err := e.validate(); err != nil {
+		return e, err
+	}
+	if e.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return e, err
+		}
+		e.Secret = secret
+	}
+	e.Active = true
+	e.CreatedAt = time.Now()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return e, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        INSERT INTO webhook_endpoints (url, secret, events, active, created_at)
+        VALUES (?, ?, ?, ?, ?)`,
+		e.URL, e.Secret, strings.Join(e.Events, ","), e.Active, e.CreatedAt)
+	if err != nil {
+		return e, fmt.Errorf("error adding webhook endpoint: %w", err)
+	}
+	if e.ID, err = result.LastInsertId(); err != nil {
+		return e, err
+	}
+
+	if err := internal.RecordAudit(tx, internal.EntityWebhook, e.ID, internal.ActionCreate, nil, e.audited()); err != nil {
+		return e, err
+	}
+	return e, tx.Commit()
+}
+
+// UpdateEndpoint changes the URL, events and active flag of an endpoint
+func UpdateEndpoint(db *sql.DB, e Endpoint) error {
+	e.URL = strings.TrimSpace(e.URL)
+	if err := e.validate(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := loadEndpoint(tx, e.ID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE webhook_endpoints SET url = ?, events = ?, active = ? WHERE id = ?",
+		e.URL, strings.Join(e.Events, ","), e.Active, e.ID)
+	if err != nil {
+		return fmt.Errorf("error updating webhook endpoint: %w", err)
+	}
+
+	if err := internal.RecordAudit(tx, internal.EntityWebhook, e.ID, internal.ActionUpdate,
+		before.audited(), e.audited()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteEndpoint removes an endpoint together with its delivery log
+func DeleteEndpoint(db *sql.DB, id int64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := loadEndpoint(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM webhook_deliveries WHERE endpoint_id = ?", id); err != nil {
+		return fmt.Errorf("error deleting webhook deliveries: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM webhook_endpoints WHERE id = ?", id); err != nil {
+		return fmt.Errorf("error deleting webhook endpoint: %w", err)
+	}
+
+	if err := internal.RecordAudit(tx, internal.EntityWebhook, id, internal.ActionDelete, before.audited(), nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Enqueue stores a pending delivery of event for every active endpoint
+// subscribed to it. The deliveries are sent by a Dispatcher.
+func Enqueue(db *sql.DB, event string, data interface{}) error {
+	endpoints, err := LoadEndpoints(db)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(Payload{Event: event, OccurredAt: now, Data: data})
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+
+	for _, e := range endpoints {
+		if !e.Active || !e.Subscribed(event) {
+			continue
+		}
+		_, err := db.Exec(`
+            INSERT INTO webhook_deliveries (
+                endpoint_id, event, payload, status, attempts, next_attempt_at, created_at
+            ) VALUES (?, ?, ?, ?, 0, ?, ?)`,
+			e.ID, event, string(body), StatusPending, now, now)
+		if err != nil {
+			return fmt.Errorf("error queueing webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+func splitEvents(events string) []string {
+	var result []string
+	for _, event := range strings.Split(events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			result = append(result, event)
+		}
+	}
+	return result
+}
@@ -0,0 +1,346 @@
+// internal/webhooks/webhooks_test.go
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/shared/db"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	database, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := db.CreateSchema(database); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	_, err = database.Exec(`
+		INSERT INTO products (name, price, active) VALUES ('Muffin', 12.5, true);
+		INSERT INTO representatives (name, active) VALUES ('Anna', true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	return database
+}
+
+// receiver records the requests sent to a test endpoint
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"order.created"}`)
+	signature := Sign("secret", "1700000000", body)
+
+	if !Verify("secret", "1700000000", body, signature) {
+		t.Error("Expected signature to verify")
+	}
+	if Verify("other", "1700000000", body, signature) || Verify("secret", "1700000001", body, signature) {
+		t.Error("Expected signature with other secret or timestamp to fail")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if Backoff(1) != InitialDelay || Backoff(3) != 4*InitialDelay {
+		t.Errorf("Unexpected backoff: %v, %v", Backoff(1), Backoff(3))
+	}
+	if Backoff(30) != MaxDelay {
+		t.Errorf("Expected backoff capped at %v, got %v", MaxDelay, Backoff(30))
+	}
+}
+
+func TestAddEndpoint_Validation(t *testing.T) {
+	database := setupTestDB(t)
+
+	for _, e := range []Endpoint{
+		{URL: "ftp://example.com", Events: []string{EventOrderCreated}},
+		{URL: "https://example.com/hook"},
+		{URL: "https://example.com/hook", Events: []string{"order.deleted"}},
+	} {
+		if _, err := AddEndpoint(database, e); err == nil {
+			t.Errorf("Expected error for %+v", e)
+		}
+	}
+
+	e, err := AddEndpoint(database, Endpoint{URL: " https://example.com/hook ", Events: []string{EventOrderCreated}})
+	if err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	if e.ID == 0 || e.Secret == "" || e.URL != "https://example.com/hook" {
+		t.Errorf("Unexpected endpoint: %+v", e)
+	}
+}
+
+func TestEndpoint_Audit(t *testing.T) {
+	database := setupTestDB(t)
+
+	e, err := AddEndpoint(database, Endpoint{URL: "https://example.com/hook", Events: []string{EventOrderCreated}})
+	if err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	e.Active = false
+	if err := UpdateEndpoint(database, e); err != nil {
+		t.Fatalf("UpdateEndpoint failed: %v", err)
+	}
+	if err := DeleteEndpoint(database, e.ID); err != nil {
+		t.Fatalf("DeleteEndpoint failed: %v", err)
+	}
+	if err := DeleteEndpoint(database, e.ID); err == nil {
+		t.Error("Expected an error deleting a missing endpoint")
+	}
+
+	entries, err := internal.LoadAuditLog(database, internal.AuditFilter{Entity: internal.EntityWebhook, EntityID: e.ID})
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d (%v)", len(entries), err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Before+entry.After, e.Secret) {
+			t.Errorf("Expected the secret to be left out of the audit log, got %+v", entry)
+		}
+	}
+}
+
+func TestDeliverySignedAndRetried(t *testing.T) {
+	database := setupTestDB(t)
+
+	recv := &receiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	endpoint, err := AddEndpoint(database, Endpoint{URL: server.URL, Events: []string{EventOrderCreated}})
+	if err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	// Not subscribed, so nothing is queued for this endpoint
+	if _, err := AddEndpoint(database, Endpoint{URL: server.URL + "/other", Events: []string{EventOrderCompleted}}); err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+
+	unsubscribe := Subscribe(internal.DefaultBus, database)
+	defer unsubscribe()
+
+	_, err = internal.CreateOrder(database, internal.Order{
+		DueDate:          time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		ClientName:       "Bob",
+		RepresentativeID: 1,
+		Items:            []internal.OrderItem{{ProductID: 1, Quantity: 2, Price: 25}},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder failed: %v", err)
+	}
+
+	now := time.Now()
+	dispatcher := NewDispatcher(database)
+	dispatcher.now = func() time.Time { return now }
+
+	// First attempt fails and is rescheduled
+	delivered, err := dispatcher.DeliverDue(context.Background())
+	if err != nil || delivered != 0 {
+		t.Fatalf("Expected failed attempt, got %d delivered (%v)", delivered, err)
+	}
+	deliveries, _ := LoadDeliveries(database, 10)
+	if len(deliveries) != 1 || deliveries[0].Status != StatusPending || deliveries[0].Attempts != 1 ||
+		deliveries[0].LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("Unexpected deliveries after failure: %+v", deliveries)
+	}
+
+	// Not due again until the backoff has passed
+	recv.status = http.StatusOK
+	if delivered, _ := dispatcher.DeliverDue(context.Background()); delivered != 0 {
+		t.Errorf("Expected no delivery before backoff, got %d", delivered)
+	}
+	now = now.Add(Backoff(1) + time.Second)
+	if delivered, err := dispatcher.DeliverDue(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("Expected delivery after backoff, got %d (%v)", delivered, err)
+	}
+
+	deliveries, _ = LoadDeliveries(database, 10)
+	if deliveries[0].Status != StatusDelivered || deliveries[0].Attempts != 2 {
+		t.Errorf("Unexpected delivery: %+v", deliveries[0])
+	}
+
+	req, body := recv.requests[1], recv.bodies[1]
+	if req.Header.Get(HeaderEvent) != EventOrderCreated {
+		t.Errorf("Unexpected event header %q", req.Header.Get(HeaderEvent))
+	}
+	if !Verify(endpoint.Secret, req.Header.Get(HeaderTimestamp), body, req.Header.Get(HeaderSignature)) {
+		t.Error("Expected a valid signature")
+	}
+
+	var payload struct {
+		Event string    `json:"event"`
+		Data  OrderData `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	if payload.Event != EventOrderCreated || payload.Data.ClientName != "Bob" || len(payload.Data.Items) != 1 {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+}
+
+func TestDeliveryGivesUpAndRetry(t *testing.T) {
+	database := setupTestDB(t)
+
+	recv := &receiver{status: http.StatusBadGateway}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	if _, err := AddEndpoint(database, Endpoint{URL: server.URL, Events: Events}); err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	if err := Enqueue(database, EventOrderCompleted, map[string]int{"id": 1}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	now := time.Now()
+	dispatcher := NewDispatcher(database)
+	dispatcher.now = func() time.Time { return now }
+	for i := 0; i < MaxAttempts; i++ {
+		dispatcher.DeliverDue(context.Background())
+		now = now.Add(MaxDelay)
+	}
+
+	deliveries, _ := LoadDeliveries(database, 10)
+	if deliveries[0].Status != StatusFailed || deliveries[0].Attempts != MaxAttempts {
+		t.Fatalf("Expected failed delivery, got %+v", deliveries[0])
+	}
+
+	if err := Retry(database, deliveries[0].ID); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	recv.status = http.StatusNoContent
+	dispatcher.now = time.Now
+	if delivered, err := dispatcher.DeliverDue(context.Background()); err != nil || delivered != 1 {
+		t.Errorf("Expected retried delivery to succeed, got %d (%v)", delivered, err)
+	}
+}
+
+func TestDeliveryClaimedOnce(t *testing.T) {
+	database := setupTestDB(t)
+
+	recv := &receiver{status: http.StatusOK}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	if _, err := AddEndpoint(database, Endpoint{URL: server.URL, Events: Events}); err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	if err := Enqueue(database, EventOrderCompleted, map[string]int{"id": 1}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	// Two computers read the same pending delivery
+	first, second := NewDispatcher(database), NewDispatcher(database)
+	due, err := first.dueDeliveries()
+	if err != nil || len(due) != 1 {
+		t.Fatalf("Expected one due delivery, got %d (%v)", len(due), err)
+	}
+	if delivered, err := second.DeliverDue(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("Expected second dispatcher to deliver, got %d (%v)", delivered, err)
+	}
+	if ok, err := first.attempt(context.Background(), due[0]); err != nil || ok {
+		t.Errorf("Expected first dispatcher to skip the claimed delivery, got %v (%v)", ok, err)
+	}
+	if len(recv.requests) != 1 {
+		t.Errorf("Expected one request, got %d", len(recv.requests))
+	}
+
+	// A delivery left sending by a dispatcher that stopped is sent again
+	// once the claim has timed out
+	if err := Enqueue(database, EventOrderCompleted, map[string]int{"id": 2}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	now := time.Now()
+	first.now = func() time.Time { return now }
+	due, _ = first.dueDeliveries()
+	if ok, err := first.claim(due[0]); err != nil || !ok {
+		t.Fatalf("Expected claim, got %v (%v)", ok, err)
+	}
+	if delivered, _ := second.DeliverDue(context.Background()); delivered != 0 {
+		t.Errorf("Expected no delivery while claimed, got %d", delivered)
+	}
+	second.now = func() time.Time { return now.Add(ClaimTimeout + time.Second) }
+	if delivered, err := second.DeliverDue(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("Expected stale delivery to be sent, got %d (%v)", delivered, err)
+	}
+	deliveries, _ := LoadDeliveries(database, 10)
+	if deliveries[0].Status != StatusDelivered || deliveries[0].Attempts != 2 {
+		t.Errorf("Unexpected delivery: %+v", deliveries[0])
+	}
+}
+
+func TestSubscribe_DataImported(t *testing.T) {
+	database := setupTestDB(t)
+
+	if _, err := AddEndpoint(database, Endpoint{URL: "https://example.com/hook", Events: Events}); err != nil {
+		t.Fatalf("AddEndpoint failed: %v", err)
+	}
+	_, err := database.Exec(`
+		INSERT INTO orders (created_at, due_date, client_name, contact, representative_id,
+			needs_delivery, delivery_address, comment, completed, total_price)
+		VALUES (?, ?, 'Bob', '', 1, false, '', '', true, 25), (?, ?, 'Carol', '', 1, false, '', '', false, 10)`,
+		time.Now(), time.Now(), time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("Failed to insert orders: %v", err)
+	}
+
+	bus := internal.NewBus()
+	unsubscribe := Subscribe(bus, database)
+	defer unsubscribe()
+	bus.Publish(internal.DataImported{Source: "workbook", Orders: []internal.Event{
+		internal.OrderCompleted{OrderID: 1},
+		internal.OrderCreated{OrderID: 2},
+	}})
+
+	deliveries, err := LoadDeliveries(database, 10)
+	if err != nil || len(deliveries) != 2 {
+		t.Fatalf("Expected 2 deliveries, got %d (%v)", len(deliveries), err)
+	}
+	if deliveries[1].Event != EventOrderCompleted || deliveries[0].Event != EventOrderCreated {
+		t.Errorf("Unexpected events %q and %q", deliveries[1].Event, deliveries[0].Event)
+	}
+}
+
+func TestNewOrderData_DueDate(t *testing.T) {
+	hours := internal.DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+
+	timed := NewOrderData(internal.Order{DueDate: time.Date(2024, 6, 1, 7, 30, 0, 0, time.UTC)}, hours)
+	if timed.DueDate != "2024-06-01T09:30:00+02:00" {
+		t.Errorf("Expected the due time of the business, got %q", timed.DueDate)
+	}
+	dateOnly := NewOrderData(internal.Order{DueDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, hours)
+	if dateOnly.DueDate != "2024-06-01" {
+		t.Errorf("Expected a date without a time, got %q", dateOnly.DueDate)
+	}
+}
@@ -0,0 +1,805 @@
+// internal/workbook/apply.go
+package workbook
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal/importer"
+	"github.com/xuri/excelize/v2"
+)
+
+// Counts records what happened to one kind of record when applying a workbook
+type Counts struct {
+	Added   int
+	Updated int
+	Deleted int
+}
+
+// Result summarises the changes applied from a workbook
+type Result struct {
+	Products        Counts
+	Representatives Counts
+	Orders          Counts
+	Items           Counts
+}
+
+// CellError points at an invalid value in the workbook
+type CellError struct {
+	Sheet   string
+	Line    int
+	Column  string
+	Message string
+}
+
+func (e CellError) Error() string {
+	return fmt.Sprintf("%s line %d, %s: %s", e.Sheet, e.Line, e.Column, e.Message)
+}
+
+// ValidationError is returned by Apply when the workbook contains invalid values.
+// Nothing is written to the database in that case.
+type ValidationError struct {
+	Errors []CellError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+// Apply reads a workbook produced by Export and writes the edits back to the
+// database in a single transaction. Rows with an ID that exists are updated
+// when they changed, rows with a blank or unknown ID are added, and order items
+// missing from the Items sheet are deleted from orders listed on the Orders
+// sheet. Products, representatives and orders are never deleted; deactivate
+// them or mark orders complete or cancelled instead. Orders changed in the
+// database since the workbook was exported are reported as errors rather
+// than overwritten.
+func Apply(db *sql.DB, filePath string) (Result, error) {
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return Result{}, fmt.Errorf("error opening Excel file: %w", err)
+	}
+	defer f.Close()
+
+	sheets := make(map[string]*sheetData)
+	for name, headers := range map[string][]string{
+		OrdersSheet:          OrderHeaders,
+		ItemsSheet:           ItemHeaders,
+		ProductsSheet:        ProductHeaders,
+		RepresentativesSheet: RepresentativeHeaders,
+	} {
+		data, err := readSheet(f, name, headers)
+		if err != nil {
+			return Result{}, err
+		}
+		sheets[name] = data
+	}
+
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		return Result{}, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback()
+
+	a := &applier{tx: tx, sheets: sheets, hours: hours}
+	steps := []func() error{
+		a.applyRepresentatives,
+		a.applyProducts,
+		a.applyOrders,
+		a.applyItems,
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if len(a.errors) > 0 {
+		return Result{}, &ValidationError{Errors: a.errors}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Result{}, err
+	}
+	internal.DefaultBus.Publish(internal.DataImported{Source: "workbook", Orders: a.orderEvents()})
+	return a.result, nil
+}
+
+// sheetData holds the raw rows of a sheet with columns located by header
+type sheetData struct {
+	name    string
+	columns map[string]int
+	rows    [][]string
+}
+
+func readSheet(f *excelize.File, name string, headers []string) (*sheetData, error) {
+	rows, err := f.GetRows(name, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("workbook is missing the %s sheet: %w", name, err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("sheet %s has no header row", name)
+	}
+
+	data := &sheetData{name: name, columns: make(map[string]int)}
+	for i, header := range rows[0] {
+		data.columns[strings.TrimSpace(header)] = i
+	}
+	for _, header := range headers {
+		if _, ok := data.columns[header]; !ok {
+			return nil, fmt.Errorf("sheet %s is missing the %q column", name, header)
+		}
+	}
+	data.rows = rows[1:]
+	return data, nil
+}
+
+// cells reads typed values from one row and records errors against it
+type cells struct {
+	a     *applier
+	sheet *sheetData
+	index int
+}
+
+func (c cells) fail(column, format string, args ...interface{}) {
+	c.a.errors = append(c.a.errors, CellError{
+		Sheet:   c.sheet.name,
+		Line:    c.index + 2,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c cells) blank() bool {
+	return strings.TrimSpace(strings.Join(c.sheet.rows[c.index], "")) == ""
+}
+
+func (c cells) text(column string) string {
+	col := c.sheet.columns[column]
+	row := c.sheet.rows[c.index]
+	if col >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[col])
+}
+
+// id returns 0 for a blank cell
+func (c cells) id(column string) int64 {
+	value := c.text(column)
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != math.Trunc(f) || f < 0 {
+		c.fail(column, "%q is not a valid ID", value)
+		return 0
+	}
+	return int64(f)
+}
+
+func (c cells) integer(column string) int {
+	value := c.text(column)
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f != math.Trunc(f) {
+		c.fail(column, "%q is not a whole number", value)
+		return 0
+	}
+	return int(f)
+}
+
+// amount reports false when the cell is blank
+func (c cells) amount(column string) (float64, bool) {
+	value := c.text(column)
+	if value == "" {
+		return 0, false
+	}
+	amount, err := importer.ParseAmount(value)
+	if err != nil {
+		c.fail(column, "%q is not a valid amount", value)
+		return 0, true
+	}
+	// Round to cents so that values read back from Excel compare equal
+	return math.Round(amount*100) / 100, true
+}
+
+func (c cells) boolean(column string) bool {
+	value := c.text(column)
+	if value == "" {
+		return false
+	}
+	b, err := importer.ParseBool(value)
+	if err != nil {
+		c.fail(column, "%q is not TRUE or FALSE", value)
+	}
+	return b
+}
+
+// date accepts Excel date serials as well as dates typed as text, reading
+// times as the wall-clock time of the business
+func (c cells) date(column string) (time.Time, bool) {
+	value := c.text(column)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil {
+		t, err := excelize.ExcelDateToTime(serial, false)
+		if err == nil {
+			// Round to the minute to drop floating point noise from the serial
+			return t.Round(time.Minute), true
+		}
+	}
+	t, err := importer.ParseDate(value, c.a.hours.Location())
+	if err != nil {
+		c.fail(column, "%q is not a date", value)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+type applier struct {
+	tx     *sql.Tx
+	sheets map[string]*sheetData
+	hours  internal.BusinessHours
+	result Result
+	errors []CellError
+
+	// Sheet IDs of added rows mapped to the IDs assigned by the database
+	productIDs map[int64]int64
+	repIDs     map[int64]int64
+	orderIDs   map[int64]int64
+
+	// Versions the orders on the sheet must still have in the transaction,
+	// and their rows for reporting conflicts, by order ID
+	versions  map[int64]int64
+	orderRows map[int64]cells
+
+	// Events of the added and changed orders, by order ID
+	events map[int64]internal.Event
+}
+
+// orderEvents returns the events of the changed orders in order of ID
+func (a *applier) orderEvents() []internal.Event {
+	ids := make([]int64, 0, len(a.events))
+	for id := range a.events {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	events := make([]internal.Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, a.events[id])
+	}
+	return events
+}
+
+// conflict reports that an order was changed since the workbook was exported
+func (a *applier) conflict(orderID int64) {
+	a.orderRows[orderID].fail("Version", "order %d was %s since the workbook was exported, export it again",
+		orderID, internal.ErrConflict)
+}
+
+// audit records a change in the audit log of the transaction
+func (a *applier) audit(entity string, id int64, action string, before, after interface{}) error {
+	return internal.RecordAudit(a.tx, entity, id, action, before, after)
+}
+
+// dueDate reads a due time from the sheet as the wall-clock time of the
+// business, as Export writes it. Dates without a time stay at midnight UTC.
+func (a *applier) dueDate(t time.Time) time.Time {
+	if !internal.HasDueTime(t) {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, a.hours.Location())
+}
+
+func (a *applier) rows(sheet string) []cells {
+	data := a.sheets[sheet]
+	var rows []cells
+	for i := range data.rows {
+		c := cells{a: a, sheet: data, index: i}
+		if !c.blank() {
+			rows = append(rows, c)
+		}
+	}
+	return rows
+}
+
+type rep struct {
+	name   string
+	active bool
+}
+
+func (r rep) representative(id int64) internal.Representative {
+	return internal.Representative{ID: id, Name: r.name, Active: r.active}
+}
+
+func (a *applier) applyRepresentatives() error {
+	a.repIDs = make(map[int64]int64)
+
+	existing := make(map[int64]rep)
+	rows, err := a.tx.Query("SELECT id, name, active FROM representatives")
+	if err != nil {
+		return fmt.Errorf("error loading representatives: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		var r rep
+		if err := rows.Scan(&id, &r.name, &r.active); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = r
+	}
+	rows.Close()
+
+	for _, c := range a.rows(RepresentativesSheet) {
+		id := c.id("ID")
+		r := rep{name: c.text("Name"), active: c.boolean("Active")}
+		if r.name == "" {
+			c.fail("Name", "value is required")
+			continue
+		}
+
+		if old, ok := existing[id]; ok {
+			a.repIDs[id] = id
+			if old == r {
+				continue
+			}
+			if _, err := a.tx.Exec("UPDATE representatives SET name = ?, active = ? WHERE id = ?",
+				r.name, r.active, id); err != nil {
+				return fmt.Errorf("error updating representative %d: %w", id, err)
+			}
+			if err := a.audit(internal.EntityRepresentative, id, internal.ActionUpdate,
+				old.representative(id), r.representative(id)); err != nil {
+				return err
+			}
+			a.result.Representatives.Updated++
+			continue
+		}
+
+		result, err := a.tx.Exec("INSERT INTO representatives (name, active) VALUES (?, ?)", r.name, r.active)
+		if err != nil {
+			return fmt.Errorf("error adding representative %q: %w", r.name, err)
+		}
+		newID, _ := result.LastInsertId()
+		if id != 0 {
+			a.repIDs[id] = newID
+		}
+		if err := a.audit(internal.EntityRepresentative, newID, internal.ActionCreate, nil, r.representative(newID)); err != nil {
+			return err
+		}
+		a.result.Representatives.Added++
+	}
+	return nil
+}
+
+type product struct {
+	name   string
+	price  float64
+	active bool
+}
+
+func (p product) product(id int64) internal.Product {
+	return internal.Product{ID: id, Name: p.name, Price: p.price, Active: p.active}
+}
+
+func (a *applier) applyProducts() error {
+	a.productIDs = make(map[int64]int64)
+
+	existing := make(map[int64]product)
+	rows, err := a.tx.Query("SELECT id, name, price, active FROM products")
+	if err != nil {
+		return fmt.Errorf("error loading products: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		var p product
+		if err := rows.Scan(&id, &p.name, &p.price, &p.active); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[id] = p
+	}
+	rows.Close()
+
+	for _, c := range a.rows(ProductsSheet) {
+		id := c.id("ID")
+		price, ok := c.amount("Price")
+		p := product{name: c.text("Name"), price: price, active: c.boolean("Active")}
+		if p.name == "" {
+			c.fail("Name", "value is required")
+			continue
+		}
+		if !ok {
+			c.fail("Price", "value is required")
+			continue
+		}
+
+		if old, ok := existing[id]; ok {
+			a.productIDs[id] = id
+			if old == p {
+				continue
+			}
+			if _, err := a.tx.Exec("UPDATE products SET name = ?, price = ?, active = ? WHERE id = ?",
+				p.name, p.price, p.active, id); err != nil {
+				return fmt.Errorf("error updating product %d: %w", id, err)
+			}
+			if err := a.audit(internal.EntityProduct, id, internal.ActionUpdate, old.product(id), p.product(id)); err != nil {
+				return err
+			}
+			a.result.Products.Updated++
+			continue
+		}
+
+		result, err := a.tx.Exec("INSERT INTO products (name, price, active) VALUES (?, ?, ?)",
+			p.name, p.price, p.active)
+		if err != nil {
+			return fmt.Errorf("error adding product %q: %w", p.name, err)
+		}
+		newID, _ := result.LastInsertId()
+		if id != 0 {
+			a.productIDs[id] = newID
+		}
+		if err := a.audit(internal.EntityProduct, newID, internal.ActionCreate, nil, p.product(newID)); err != nil {
+			return err
+		}
+		a.result.Products.Added++
+	}
+	return nil
+}
+
+type orderRow struct {
+	dueDate         time.Time
+	clientName      string
+	contact         string
+	repID           int64
+	needsDelivery   bool
+	deliveryAddress string
+	comment         string
+	completed       bool
+	cancelled       bool
+}
+
+func (o orderRow) order(id int64) internal.Order {
+	return internal.Order{
+		ID:               id,
+		DueDate:          o.dueDate,
+		ClientName:       o.clientName,
+		Contact:          o.contact,
+		RepresentativeID: o.repID,
+		NeedsDelivery:    o.needsDelivery,
+		DeliveryAddress:  o.deliveryAddress,
+		Comment:          o.comment,
+		Completed:        o.completed,
+		Cancelled:        o.cancelled,
+	}
+}
+
+func (a *applier) applyOrders() error {
+	a.orderIDs = make(map[int64]int64)
+	a.versions = make(map[int64]int64)
+	a.orderRows = make(map[int64]cells)
+	a.events = make(map[int64]internal.Event)
+
+	existing := make(map[int64]orderRow)
+	rows, err := a.tx.Query(`
+        SELECT id, due_date, client_name, contact, representative_id,
+               needs_delivery, delivery_address, comment, completed, cancelled
+        FROM orders`)
+	if err != nil {
+		return fmt.Errorf("error loading orders: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id                                int64
+			o                                 orderRow
+			due                               sql.NullTime
+			client, contact, address, comment sql.NullString
+			repID                             sql.NullInt64
+			needsDelivery, completed          sql.NullBool
+			cancelled                         sql.NullBool
+		)
+		if err := rows.Scan(&id, &due, &client, &contact, &repID,
+			&needsDelivery, &address, &comment, &completed, &cancelled); err != nil {
+			rows.Close()
+			return err
+		}
+		o.dueDate = due.Time
+		o.clientName = client.String
+		o.contact = contact.String
+		o.repID = repID.Int64
+		o.needsDelivery = needsDelivery.Bool
+		o.deliveryAddress = address.String
+		o.comment = comment.String
+		o.completed = completed.Bool
+		o.cancelled = cancelled.Bool
+		existing[id] = o
+	}
+	rows.Close()
+
+	for _, c := range a.rows(OrdersSheet) {
+		id := c.id("ID")
+		o := orderRow{
+			clientName:      c.text("Client Name"),
+			contact:         c.text("Contact"),
+			needsDelivery:   c.boolean("Needs Delivery"),
+			deliveryAddress: c.text("Delivery Address"),
+			comment:         c.text("Comment"),
+			completed:       c.boolean("Completed"),
+			cancelled:       c.boolean("Cancelled"),
+		}
+		if o.completed && o.cancelled {
+			c.fail("Cancelled", "a completed order cannot be cancelled")
+			continue
+		}
+
+		due, ok := c.date("Due Date")
+		if !ok {
+			c.fail("Due Date", "value is required")
+			continue
+		}
+		o.dueDate = a.dueDate(due)
+
+		if sheetRepID := c.id("Representative ID"); sheetRepID != 0 {
+			repID, ok := a.repIDs[sheetRepID]
+			if !ok {
+				c.fail("Representative ID", "representative %d is not on the %s sheet", sheetRepID, RepresentativesSheet)
+				continue
+			}
+			o.repID = repID
+		}
+
+		if old, ok := existing[id]; ok {
+			version := int64(c.integer("Version"))
+			a.orderIDs[id] = id
+			a.versions[id] = version
+			a.orderRows[id] = c
+			if old.equal(o) {
+				continue
+			}
+			updated, err := a.tx.Exec(`
+                UPDATE orders
+                SET due_date = ?, client_name = ?, contact = ?,
+                    representative_id = ?, needs_delivery = ?,
+                    delivery_address = ?, comment = ?, completed = ?,
+                    cancelled = ?, version = version + 1
+                WHERE id = ? AND version = ?`,
+				o.dueDate, o.clientName, o.contact,
+				o.repID, o.needsDelivery,
+				o.deliveryAddress, o.comment, o.completed,
+				o.cancelled, id, version)
+			if err != nil {
+				return fmt.Errorf("error updating order %d: %w", id, err)
+			}
+			if affected, err := updated.RowsAffected(); err != nil {
+				return fmt.Errorf("error updating order %d: %w", id, err)
+			} else if affected == 0 {
+				a.conflict(id)
+				continue
+			}
+			a.versions[id] = version + 1
+			if err := a.audit(internal.EntityOrder, id, internal.ActionUpdate, old.order(id), o.order(id)); err != nil {
+				return err
+			}
+			a.events[id] = internal.OrderChanged(old.order(id), o.order(id))
+			a.result.Orders.Updated++
+			continue
+		}
+
+		createdAt, ok := c.date("Created At")
+		if !ok {
+			createdAt = time.Now()
+		}
+		result, err := a.tx.Exec(`
+            INSERT INTO orders (
+                created_at, due_date, client_name, contact,
+                representative_id, needs_delivery, delivery_address,
+                comment, completed, cancelled, total_price
+            ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 0)`,
+			createdAt, o.dueDate, o.clientName, o.contact,
+			o.repID, o.needsDelivery, o.deliveryAddress,
+			o.comment, o.completed, o.cancelled)
+		if err != nil {
+			return fmt.Errorf("error adding order for %q: %w", o.clientName, err)
+		}
+		// Items refer to new orders by the temporary ID given on the sheet
+		newID, _ := result.LastInsertId()
+		if id != 0 {
+			a.orderIDs[id] = newID
+		}
+		a.versions[newID] = 1
+		a.orderRows[newID] = c
+		created := o.order(newID)
+		created.CreatedAt = createdAt
+		if err := a.audit(internal.EntityOrder, newID, internal.ActionCreate, nil, created); err != nil {
+			return err
+		}
+		a.events[newID] = internal.OrderCreated{OrderID: newID}
+		a.result.Orders.Added++
+	}
+	return nil
+}
+
+func (o orderRow) equal(other orderRow) bool {
+	due := o.dueDate.Equal(other.dueDate)
+	o.dueDate, other.dueDate = time.Time{}, time.Time{}
+	return due && o == other
+}
+
+type itemRow struct {
+	productID int64
+	quantity  int
+	price     float64
+}
+
+// auditItem is an order item as recorded in the audit log
+type auditItem struct {
+	internal.OrderItem
+	OrderID int64
+}
+
+func (i itemRow) item(id, orderID int64) auditItem {
+	return auditItem{
+		OrderItem: internal.OrderItem{ID: id, ProductID: i.productID, Quantity: i.quantity, Price: i.price},
+		OrderID:   orderID,
+	}
+}
+
+func (a *applier) applyItems() error {
+	// Existing items of the orders on the Orders sheet, by order then item ID
+	existing := make(map[int64]map[int64]itemRow)
+	for _, orderID := range a.orderIDs {
+		existing[orderID] = make(map[int64]itemRow)
+	}
+
+	rows, err := a.tx.Query("SELECT id, order_id, product_id, quantity, price FROM order_items")
+	if err != nil {
+		return fmt.Errorf("error loading order items: %w", err)
+	}
+	for rows.Next() {
+		var id, orderID int64
+		var item itemRow
+		if err := rows.Scan(&id, &orderID, &item.productID, &item.quantity, &item.price); err != nil {
+			rows.Close()
+			return err
+		}
+		if items, ok := existing[orderID]; ok {
+			items[id] = item
+		}
+	}
+	rows.Close()
+
+	prices, err := a.productPrices()
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[int64]bool)
+	changed := make(map[int64]bool)
+
+	for _, c := range a.rows(ItemsSheet) {
+		id := c.id("ID")
+
+		orderID, ok := a.orderIDs[c.id("Order ID")]
+		if !ok {
+			c.fail("Order ID", "order %s is not on the %s sheet", c.text("Order ID"), OrdersSheet)
+			continue
+		}
+		productID, ok := a.productIDs[c.id("Product ID")]
+		if !ok {
+			c.fail("Product ID", "product %s is not on the %s sheet", c.text("Product ID"), ProductsSheet)
+			continue
+		}
+
+		item := itemRow{productID: productID, quantity: c.integer("Quantity")}
+		if item.quantity <= 0 {
+			c.fail("Quantity", "quantity must be at least 1")
+			continue
+		}
+		price, ok := c.amount("Price")
+		if !ok {
+			price = prices[productID] * float64(item.quantity)
+		}
+		item.price = price
+
+		if old, ok := existing[orderID][id]; ok && id != 0 {
+			kept[id] = true
+			if old == item {
+				continue
+			}
+			if _, err := a.tx.Exec("UPDATE order_items SET product_id = ?, quantity = ?, price = ? WHERE id = ?",
+				item.productID, item.quantity, item.price, id); err != nil {
+				return fmt.Errorf("error updating order item %d: %w", id, err)
+			}
+			if err := a.audit(internal.EntityOrderItem, id, internal.ActionUpdate, old.item(id, orderID), item.item(id, orderID)); err != nil {
+				return err
+			}
+			changed[orderID] = true
+			a.result.Items.Updated++
+			continue
+		}
+
+		result, err := a.tx.Exec("INSERT INTO order_items (order_id, product_id, quantity, price) VALUES (?, ?, ?, ?)",
+			orderID, item.productID, item.quantity, item.price)
+		if err != nil {
+			return fmt.Errorf("error adding order item: %w", err)
+		}
+		newID, _ := result.LastInsertId()
+		if err := a.audit(internal.EntityOrderItem, newID, internal.ActionCreate, nil, item.item(newID, orderID)); err != nil {
+			return err
+		}
+		changed[orderID] = true
+		a.result.Items.Added++
+	}
+
+	// Items left out of the sheet were removed by the user
+	for orderID, items := range existing {
+		for id, old := range items {
+			if kept[id] {
+				continue
+			}
+			if _, err := a.tx.Exec("DELETE FROM order_items WHERE id = ?", id); err != nil {
+				return fmt.Errorf("error deleting order item %d: %w", id, err)
+			}
+			if err := a.audit(internal.EntityOrderItem, id, internal.ActionDelete, old.item(id, orderID), nil); err != nil {
+				return err
+			}
+			changed[orderID] = true
+			a.result.Items.Deleted++
+		}
+	}
+
+	// Keep order totals in line with their items
+	for orderID := range changed {
+		result, err := a.tx.Exec(`
+            UPDATE orders
+            SET total_price = (SELECT COALESCE(SUM(price), 0) FROM order_items WHERE order_id = ?),
+                version = version + 1
+            WHERE id = ? AND version = ?`, orderID, orderID, a.versions[orderID])
+		if err != nil {
+			return fmt.Errorf("error updating total of order %d: %w", orderID, err)
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("error updating total of order %d: %w", orderID, err)
+		} else if affected == 0 {
+			a.conflict(orderID)
+		}
+		if _, ok := a.events[orderID]; !ok {
+			a.events[orderID] = internal.OrderEdited{OrderID: orderID}
+		}
+	}
+	return nil
+}
+
+func (a *applier) productPrices() (map[int64]float64, error) {
+	rows, err := a.tx.Query("SELECT id, price FROM products")
+	if err != nil {
+		return nil, fmt.Errorf("error loading product prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make(map[int64]float64)
+	for rows.Next() {
+		var id int64
+		var price float64
+		if err := rows.Scan(&id, &price); err != nil {
+			return nil, err
+		}
+		prices[id] = price
+	}
+	return prices, rows.Err()
+}
@@ -0,0 +1,304 @@
+// internal/workbook/export.go
+package workbook
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheet names of the structured workbook
+const (
+	OrdersSheet          = "Orders"
+	ItemsSheet           = "Items"
+	ProductsSheet        = "Products"
+	RepresentativesSheet = "Representatives"
+)
+
+// Column headers of each sheet. Columns are located by header when a workbook
+// is applied, so users may reorder them but must not rename them.
+var (
+	OrderHeaders = []string{
+		"ID", "Created At", "Due Date", "Client Name", "Contact",
+		"Representative ID", "Representative", "Needs Delivery",
+		"Delivery Address", "Comment", "Completed", "Cancelled", "Total Price",
+		"Version",
+	}
+	ItemHeaders           = []string{"ID", "Order ID", "Product ID", "Product", "Quantity", "Price"}
+	ProductHeaders        = []string{"ID", "Name", "Price", "Active"}
+	RepresentativeHeaders = []string{"ID", "Name", "Active"}
+)
+
+const (
+	currencyFormat = `"R"#,##0.00`
+	dateTimeFormat = "yyyy-mm-dd hh:mm"
+)
+
+// Export writes all orders, order items, products and representatives to a
+// workbook with one sheet per table, keyed by ID, using numeric cell types so
+// that it can be edited and applied back with Apply.
+func Export(db *sql.DB, filePath string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	styles, err := newStyles(f)
+	if err != nil {
+		return err
+	}
+
+	f.SetSheetName("Sheet1", OrdersSheet)
+	for _, sheet := range []string{ItemsSheet, ProductsSheet, RepresentativesSheet} {
+		if _, err := f.NewSheet(sheet); err != nil {
+			return fmt.Errorf("error creating sheet %s: %w", sheet, err)
+		}
+	}
+
+	hours, err := internal.LoadBusinessHours(db)
+	if err != nil {
+		return err
+	}
+	if err := exportOrders(db, f, styles, hours); err != nil {
+		return err
+	}
+	if err := exportItems(db, f, styles); err != nil {
+		return err
+	}
+	if err := exportProducts(db, f, styles); err != nil {
+		return err
+	}
+	if err := exportRepresentatives(db, f, styles); err != nil {
+		return err
+	}
+
+	if err := f.SaveAs(filePath); err != nil {
+		return fmt.Errorf("error saving Excel file: %w", err)
+	}
+	return nil
+}
+
+type styles struct {
+	header   int
+	currency int
+	dateTime int
+}
+
+func newStyles(f *excelize.File) (styles, error) {
+	var s styles
+	var err error
+
+	s.header, err = f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{
+			Bold: true,
+		},
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Color:   []string{"#E0E0E0"},
+			Pattern: 1,
+		},
+	})
+	if err != nil {
+		return s, fmt.Errorf("error creating header style: %w", err)
+	}
+
+	currency := currencyFormat
+	s.currency, err = f.NewStyle(&excelize.Style{CustomNumFmt: &currency})
+	if err != nil {
+		return s, fmt.Errorf("error creating currency style: %w", err)
+	}
+
+	dateTime := dateTimeFormat
+	s.dateTime, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateTime})
+	if err != nil {
+		return s, fmt.Errorf("error creating date style: %w", err)
+	}
+	return s, nil
+}
+
+// sheet writes rows to one sheet and applies column styles
+type sheet struct {
+	f       *excelize.File
+	name    string
+	styles  styles
+	headers []string
+	row     int
+}
+
+func newSheet(f *excelize.File, name string, s styles, headers []string) *sheet {
+	for i, header := range headers {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		f.SetCellValue(name, col+"1", header)
+		f.SetColWidth(name, col, col, 15)
+	}
+	f.SetRowStyle(name, 1, 1, s.header)
+	f.SetPanes(name, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	return &sheet{f: f, name: name, styles: s, headers: headers, row: 1}
+}
+
+func (s *sheet) add(values ...interface{}) {
+	s.row++
+	for i, value := range values {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		cell := fmt.Sprintf("%s%d", col, s.row)
+
+		switch v := value.(type) {
+		case bool:
+			s.f.SetCellBool(s.name, cell, v)
+		case money:
+			s.f.SetCellFloat(s.name, cell, float64(v), -1, 64)
+			s.f.SetCellStyle(s.name, cell, cell, s.styles.currency)
+		case time.Time:
+			if v.IsZero() {
+				continue
+			}
+			s.f.SetCellValue(s.name, cell, v)
+			s.f.SetCellStyle(s.name, cell, cell, s.styles.dateTime)
+		default:
+			s.f.SetCellValue(s.name, cell, v)
+		}
+	}
+}
+
+func (s *sheet) finish() {
+	lastCol, _ := excelize.ColumnNumberToName(len(s.headers))
+	s.f.AutoFilter(s.name, fmt.Sprintf("A1:%s%d", lastCol, s.row), []excelize.AutoFilterOptions{})
+}
+
+// money marks a float64 as a currency amount
+type money float64
+
+// exportOrders writes due times as the wall-clock time of the business, since
+// Excel dates have no time zone
+func exportOrders(db *sql.DB, f *excelize.File, s styles, hours internal.BusinessHours) error {
+	rows, err := db.Query(`
+        SELECT o.id, o.created_at, o.due_date, o.client_name, o.contact,
+               o.representative_id, r.name, o.needs_delivery, o.delivery_address,
+               o.comment, o.completed, o.cancelled, o.total_price, o.version
+        FROM orders o
+        LEFT JOIN representatives r ON o.representative_id = r.id
+        ORDER BY o.id
+    `)
+	if err != nil {
+		return fmt.Errorf("error querying orders: %w", err)
+	}
+	defer rows.Close()
+
+	sh := newSheet(f, OrdersSheet, s, OrderHeaders)
+	for rows.Next() {
+		var (
+			id              int64
+			createdAt       sql.NullTime
+			dueDate         sql.NullTime
+			clientName      sql.NullString
+			contact         sql.NullString
+			repID           sql.NullInt64
+			repName         sql.NullString
+			needsDelivery   sql.NullBool
+			deliveryAddress sql.NullString
+			comment         sql.NullString
+			completed       sql.NullBool
+			cancelled       sql.NullBool
+			totalPrice      sql.NullFloat64
+			version         int64
+		)
+		err := rows.Scan(&id, &createdAt, &dueDate, &clientName, &contact,
+			&repID, &repName, &needsDelivery, &deliveryAddress,
+			&comment, &completed, &cancelled, &totalPrice, &version)
+		if err != nil {
+			return fmt.Errorf("error scanning order: %w", err)
+		}
+
+		due := dueDate.Time
+		if internal.HasDueTime(due) {
+			due = due.In(hours.Location())
+		}
+		sh.add(id, createdAt.Time, due, clientName.String, contact.String,
+			repID.Int64, repName.String, needsDelivery.Bool, deliveryAddress.String,
+			comment.String, completed.Bool, cancelled.Bool, money(totalPrice.Float64), version)
+	}
+	sh.finish()
+	return rows.Err()
+}
+
+func exportItems(db *sql.DB, f *excelize.File, s styles) error {
+	rows, err := db.Query(`
+        SELECT oi.id, oi.order_id, oi.product_id, p.name, oi.quantity, oi.price
+        FROM order_items oi
+        LEFT JOIN products p ON oi.product_id = p.id
+        ORDER BY oi.order_id, oi.id
+    `)
+	if err != nil {
+		return fmt.Errorf("error querying order items: %w", err)
+	}
+	defer rows.Close()
+
+	sh := newSheet(f, ItemsSheet, s, ItemHeaders)
+	for rows.Next() {
+		var (
+			id, orderID, productID int64
+			productName            sql.NullString
+			quantity               int
+			price                  float64
+		)
+		if err := rows.Scan(&id, &orderID, &productID, &productName, &quantity, &price); err != nil {
+			return fmt.Errorf("error scanning order item: %w", err)
+		}
+		sh.add(id, orderID, productID, productName.String, quantity, money(price))
+	}
+	sh.finish()
+	return rows.Err()
+}
+
+func exportProducts(db *sql.DB, f *excelize.File, s styles) error {
+	rows, err := db.Query("SELECT id, name, price, active FROM products ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("error querying products: %w", err)
+	}
+	defer rows.Close()
+
+	sh := newSheet(f, ProductsSheet, s, ProductHeaders)
+	for rows.Next() {
+		var (
+			id     int64
+			name   string
+			price  float64
+			active bool
+		)
+		if err := rows.Scan(&id, &name, &price, &active); err != nil {
+			return fmt.Errorf("error scanning product: %w", err)
+		}
+		sh.add(id, name, money(price), active)
+	}
+	sh.finish()
+	return rows.Err()
+}
+
+func exportRepresentatives(db *sql.DB, f *excelize.File, s styles) error {
+	rows, err := db.Query("SELECT id, name, active FROM representatives ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("error querying representatives: %w", err)
+	}
+	defer rows.Close()
+
+	sh := newSheet(f, RepresentativesSheet, s, RepresentativeHeaders)
+	for rows.Next() {
+		var (
+			id     int64
+			name   string
+			active bool
+		)
+		if err := rows.Scan(&id, &name, &active); err != nil {
+			return fmt.Errorf("error scanning representative: %w", err)
+		}
+		sh.add(id, name, active)
+	}
+	sh.finish()
+	return rows.Err()
+}
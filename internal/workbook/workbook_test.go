@@ -0,0 +1,424 @@
+// internal/workbook/workbook_test.go
+package workbook
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/reinhardt-bit/OrderFlow-Manager/internal"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
+	"github.com/xuri/excelize/v2"
+)
+
+func setupTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name()))
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE products (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			price REAL NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE representatives (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			active BOOLEAN DEFAULT true
+		);
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			created_at DATETIME,
+			due_date DATETIME,
+			client_name TEXT,
+			contact TEXT,
+			needs_delivery BOOLEAN,
+			delivery_address TEXT,
+			comment TEXT,
+			completed BOOLEAN,
+			cancelled BOOLEAN DEFAULT false,
+			version INTEGER NOT NULL DEFAULT 1,
+			representative_id INTEGER,
+			total_price REAL
+		);
+		CREATE TABLE order_items (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			order_id INTEGER,
+			product_id INTEGER,
+			quantity INTEGER,
+			price REAL
+		);
+		CREATE TABLE audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			entity TEXT NOT NULL,
+			entity_id INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			before_json TEXT,
+			after_json TEXT,
+			user TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		CREATE TABLE settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+		INSERT INTO products (name, price, active) VALUES ('Muffin', 10, true), ('Cake', 25.5, true);
+		INSERT INTO representatives (name, active) VALUES ('Anna', true);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create test tables: %v", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO orders (created_at, due_date, client_name, contact, representative_id,
+			needs_delivery, delivery_address, comment, completed, total_price)
+		VALUES (?, ?, 'Alice', '555', 1, false, '', 'Birthday', false, 45.5)`,
+		time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC), time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Failed to insert order: %v", err)
+	}
+	_, err = db.Exec(`
+		INSERT INTO order_items (order_id, product_id, quantity, price) VALUES
+		(1, 1, 2, 20), (1, 2, 1, 25.5)`)
+	if err != nil {
+		t.Fatalf("Failed to insert order items: %v", err)
+	}
+	return db
+}
+
+func exportTo(t *testing.T, db *sql.DB) string {
+	path := filepath.Join(t.TempDir(), "workbook.xlsx")
+	if err := Export(db, path); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	return path
+}
+
+func TestExport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	f, err := excelize.OpenFile(exportTo(t, db))
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	expected := []string{OrdersSheet, ItemsSheet, ProductsSheet, RepresentativesSheet}
+	if fmt.Sprint(sheets) != fmt.Sprint(expected) {
+		t.Fatalf("Expected sheets %v, got %v", expected, sheets)
+	}
+
+	// Prices are numbers with a currency format rather than "R25.50" strings
+	raw, _ := f.GetCellValue(ProductsSheet, "C3", excelize.Options{RawCellValue: true})
+	if raw != "25.5" {
+		t.Errorf("Expected raw price 25.5, got %q", raw)
+	}
+	formatted, _ := f.GetCellValue(ProductsSheet, "C3")
+	if formatted != "R25.50" {
+		t.Errorf("Expected formatted price R25.50, got %q", formatted)
+	}
+
+	cellType, _ := f.GetCellType(OrdersSheet, "M2")
+	if cellType != excelize.CellTypeNumber && cellType != excelize.CellTypeUnset {
+		t.Errorf("Expected total price to be numeric, got type %v", cellType)
+	}
+}
+
+func TestApply_RoundTripWithoutChanges(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	result, err := Apply(db, exportTo(t, db))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if result != (Result{}) {
+		t.Errorf("Expected no changes when applying an unmodified export, got %+v", result)
+	}
+}
+
+func TestApply_DueTimeInBusinessTimeZone(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	hours := internal.DefaultBusinessHours()
+	hours.TimeZone = "Africa/Johannesburg"
+	if err := internal.SaveBusinessHours(db, hours); err != nil {
+		t.Fatalf("SaveBusinessHours failed: %v", err)
+	}
+	due := time.Date(2024, 5, 10, 14, 30, 0, 0, hours.Location())
+	if _, err := db.Exec("UPDATE orders SET due_date = ? WHERE id = 1", due); err != nil {
+		t.Fatalf("Failed to set due time: %v", err)
+	}
+
+	path := exportTo(t, db)
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	if shown, _ := f.GetCellValue(OrdersSheet, "C2"); shown != "2024-05-10 14:30" {
+		t.Errorf("Expected the due time of the business, got %q", shown)
+	}
+	f.Close()
+
+	result, err := Apply(db, path)
+	if err != nil || result != (Result{}) {
+		t.Fatalf("Expected no changes when applying an unmodified export, got %+v (%v)", result, err)
+	}
+
+	// A due time typed on the sheet is a time of the business too
+	f, _ = excelize.OpenFile(path)
+	f.SetCellValue(OrdersSheet, "C2", "2024-05-10 09:00")
+	f.Save()
+	f.Close()
+	if _, err := Apply(db, path); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	order, err := internal.LoadOrder(db, 1)
+	if err != nil || !order.DueDate.Equal(time.Date(2024, 5, 10, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected 09:00 in Johannesburg, got %v (%v)", order.DueDate, err)
+	}
+}
+
+func TestApply_Edits(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	path := exportTo(t, db)
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+
+	// Raise the muffin price, add a product, change the client name,
+	// drop the cake item and add two of the new product instead
+	f.SetCellFloat(ProductsSheet, "C2", 12, 2, 64)
+	f.SetSheetRow(ProductsSheet, "A4", &[]interface{}{100, "Scone", 8, true})
+	f.SetCellValue(OrdersSheet, "D2", "Alice Smith")
+	f.RemoveRow(ItemsSheet, 3)
+	f.SetSheetRow(ItemsSheet, "A3", &[]interface{}{"", 1, 100, "Scone", 2, ""})
+	if err := f.Save(); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+	f.Close()
+
+	result, err := Apply(db, path)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	expected := Result{
+		Products: Counts{Added: 1, Updated: 1},
+		Orders:   Counts{Updated: 1},
+		Items:    Counts{Added: 1, Deleted: 1},
+	}
+	if result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	var client string
+	var total float64
+	if err := db.QueryRow("SELECT client_name, total_price FROM orders WHERE id = 1").Scan(&client, &total); err != nil {
+		t.Fatalf("Failed to load order: %v", err)
+	}
+	if client != "Alice Smith" {
+		t.Errorf("Expected client name to be updated, got %q", client)
+	}
+	// Existing muffin line keeps its price, the scones are priced from the new product
+	if total != 36 {
+		t.Errorf("Expected total to be recalculated to 36, got %.2f", total)
+	}
+
+	// Every change is in the audit log
+	entries, err := internal.LoadAuditLog(db, internal.AuditFilter{})
+	if err != nil {
+		t.Fatalf("LoadAuditLog failed: %v", err)
+	}
+	if len(entries) != 5 {
+		t.Errorf("Expected 5 audit entries, got %d", len(entries))
+	}
+	items, err := internal.LoadAuditLog(db, internal.AuditFilter{Entity: internal.EntityOrderItem})
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Expected 2 item entries, got %d (%v)", len(items), err)
+	}
+}
+
+func TestApply_Cancelled(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	if err := internal.CancelOrders(db, []int64{1}); err != nil {
+		t.Fatalf("CancelOrders failed: %v", err)
+	}
+
+	path := exportTo(t, db)
+	result, err := Apply(db, path)
+	if err != nil || result != (Result{}) {
+		t.Fatalf("Expected the cancelled order to round trip unchanged, got %+v (%v)", result, err)
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	if cancelled, _ := f.GetCellValue(OrdersSheet, "L2"); cancelled != "TRUE" {
+		t.Errorf("Expected the order to be exported as cancelled, got %q", cancelled)
+	}
+	// A new order that is both completed and cancelled is rejected
+	f.SetSheetRow(OrdersSheet, "A3", &[]interface{}{"", "", time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+		"Bob", "", "", "", false, "", "", true, true})
+	f.SetCellBool(OrdersSheet, "L2", false)
+	if err := f.Save(); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+	f.Close()
+
+	_, err = Apply(db, path)
+	var validation *ValidationError
+	if !errors.As(err, &validation) || validation.Errors[0].Column != "Cancelled" {
+		t.Fatalf("Expected a completed and cancelled order to be rejected, got %v", err)
+	}
+
+	f, _ = excelize.OpenFile(path)
+	f.RemoveRow(OrdersSheet, 3)
+	f.Save()
+	f.Close()
+	if result, err := Apply(db, path); err != nil || result.Orders.Updated != 1 {
+		t.Fatalf("Expected the order to be reinstated, got %+v (%v)", result, err)
+	}
+	order, err := internal.LoadOrder(db, 1)
+	if err != nil || order.Cancelled {
+		t.Errorf("Expected the order to be pending again, got %+v (%v)", order, err)
+	}
+}
+
+func TestApply_InvalidValuesRollBack(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	path := exportTo(t, db)
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	f.SetCellValue(ProductsSheet, "B2", "Big Muffin")
+	f.SetCellValue(ItemsSheet, "E2", "lots")
+	if err := f.Save(); err != nil {
+		t.Fatalf("Failed to save workbook: %v", err)
+	}
+	f.Close()
+
+	_, err = Apply(db, path)
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if validation.Errors[0].Sheet != ItemsSheet || validation.Errors[0].Column != "Quantity" {
+		t.Errorf("Unexpected validation error: %v", validation.Errors[0])
+	}
+
+	var name string
+	db.QueryRow("SELECT name FROM products WHERE id = 1").Scan(&name)
+	if name != "Muffin" {
+		t.Errorf("Expected product rename to be rolled back, got %q", name)
+	}
+}
+
+func TestApply_ChangedSinceExport(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	path := exportTo(t, db)
+	// Someone else edits the order after the export
+	if _, err := db.Exec("UPDATE orders SET comment = 'Wedding', version = version + 1 WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to update order: %v", err)
+	}
+
+	// An edit of the order row is not written over theirs
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	f.SetCellValue(OrdersSheet, "D2", "Alicia")
+	f.Save()
+	f.Close()
+
+	_, err = Apply(db, path)
+	var validation *ValidationError
+	if !errors.As(err, &validation) || validation.Errors[0].Column != "Version" || validation.Errors[0].Line != 2 {
+		t.Fatalf("Expected a conflict on the order, got %v", err)
+	}
+	order, _ := internal.LoadOrder(db, 1)
+	if order.ClientName != "Alice" || order.Comment != "Wedding" {
+		t.Errorf("Expected the other edit to be kept, got %+v", order)
+	}
+
+	// Nor is an edit of its items
+	path = exportTo(t, db)
+	f, _ = excelize.OpenFile(path)
+	f.SetCellValue(ItemsSheet, "E2", 3)
+	f.Save()
+	f.Close()
+	// Someone else changes only the price of an item
+	if _, err := db.Exec("UPDATE order_items SET price = 30 WHERE id = 2"); err != nil {
+		t.Fatalf("Failed to update order item: %v", err)
+	}
+	if _, err := db.Exec("UPDATE orders SET total_price = 50, version = version + 1 WHERE id = 1"); err != nil {
+		t.Fatalf("Failed to update order: %v", err)
+	}
+	_, err = Apply(db, path)
+	if !errors.As(err, &validation) || validation.Errors[0].Column != "Version" {
+		t.Fatalf("Expected a conflict on the order items, got %v", err)
+	}
+
+	// A workbook exported after the edit applies
+	path = exportTo(t, db)
+	f, _ = excelize.OpenFile(path)
+	f.SetCellValue(OrdersSheet, "D2", "Alicia")
+	f.Save()
+	f.Close()
+	if result, err := Apply(db, path); err != nil || result.Orders.Updated != 1 {
+		t.Fatalf("Expected the edit to apply, got %+v (%v)", result, err)
+	}
+}
+
+func TestApply_PublishesOrderEvents(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var imported []internal.DataImported
+	unsubscribe := internal.SubscribeTo(internal.DefaultBus, func(e internal.DataImported) {
+		imported = append(imported, e)
+	})
+	defer unsubscribe()
+
+	path := exportTo(t, db)
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatalf("Failed to open workbook: %v", err)
+	}
+	f.SetCellBool(OrdersSheet, "K2", true)
+	f.SetSheetRow(OrdersSheet, "A3", &[]interface{}{"", "", time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+		"Bob", "", "", "", false, "", "", false, false})
+	f.Save()
+	f.Close()
+
+	if _, err := Apply(db, path); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("Expected one DataImported event, got %d", len(imported))
+	}
+	expected := []internal.Event{internal.OrderCompleted{OrderID: 1}, internal.OrderCreated{OrderID: 2}}
+	if fmt.Sprint(imported[0].Orders) != fmt.Sprint(expected) {
+		t.Errorf("Expected order events %v, got %v", expected, imported[0].Orders)
+	}
+}
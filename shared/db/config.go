@@ -0,0 +1,261 @@
+// shared/db/config.go
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by LoadConfig
+const (
+	EnvConfigPath     = "ORDERFLOW_CONFIG"
+	EnvProfile        = "ORDERFLOW_PROFILE"
+	EnvDatabaseURL    = "TURSO_DATABASE_URL"
+	EnvAuthToken      = "TURSO_AUTH_TOKEN"
+	EnvConnectTimeout = "ORDERFLOW_CONNECT_TIMEOUT"
+)
+
+// DefaultConnectTimeout is how long Connect waits for the database to answer
+const DefaultConnectTimeout = 10 * time.Second
+
+// SourceKind is the layer a configuration value came from. Later layers take
+// precedence over earlier ones.
+type SourceKind int
+
+const (
+	SourceNone SourceKind = iota
+	SourceDefault
+	SourceFile
+	SourceEnv
+	SourceFlag
+)
+
+// Source describes where a configuration value came from
+type Source struct {
+	Kind SourceKind
+	// Name is the file path, environment variable or flag name
+	Name string
+}
+
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "config file " + s.Name
+	case SourceEnv:
+		return "environment variable " + s.Name
+	case SourceFlag:
+		return "flag -" + s.Name
+	default:
+		return "not set"
+	}
+}
+
+// Overrides are the configuration values given on the command line. Empty
+// fields are not overridden.
+type Overrides struct {
+	ConfigPath     string
+	Profile        string
+	DatabaseURL    string
+	AuthToken      string
+	ConnectTimeout string
+}
+
+// Config is the configuration resolved from defaults, the config file, the
+// environment and command-line flags, in increasing order of precedence
+type Config struct {
+	ConfigPath     string
+	Profile        string
+	DatabaseURL    string
+	AuthToken      string
+	ConnectTimeout time.Duration
+
+	Sources struct {
+		ConfigPath     Source
+		Profile        Source
+		DatabaseURL    Source
+		AuthToken      Source
+		ConnectTimeout Source
+	}
+}
+
+// configPathOverride is the config file given with SetConfigPath
+var configPathOverride string
+
+// SetConfigPath makes the profile functions use the config file at path
+// instead of the one in the user's config directory
+func SetConfigPath(path string) {
+	configPathOverride = path
+}
+
+// layer returns the value of the highest layer that sets it
+func layer(values ...layerValue) (string, Source) {
+	var value string
+	var source Source
+	for _, v := range values {
+		if v.value != "" {
+			value, source = v.value, v.source
+		}
+	}
+	return value, source
+}
+
+type layerValue struct {
+	value  string
+	source Source
+}
+
+func fromEnv(name string) layerValue {
+	return layerValue{os.Getenv(name), Source{SourceEnv, name}}
+}
+
+func fromFlag(value, name string) layerValue {
+	return layerValue{value, Source{SourceFlag, name}}
+}
+
+// LoadConfig resolves the database configuration. A config path given in the
+// overrides is also used by the profile functions from then on.
+func LoadConfig(overrides Overrides) (Config, error) {
+	var config Config
+
+	if overrides.ConfigPath != "" {
+		SetConfigPath(overrides.ConfigPath)
+	}
+	path, err := getConfigFilePath()
+	if err != nil {
+		return Config{}, fmt.Errorf("error finding config file: %v", err)
+	}
+	config.ConfigPath = path
+	config.Sources.ConfigPath = Source{Kind: SourceDefault}
+	if overrides.ConfigPath != "" {
+		config.Sources.ConfigPath = Source{SourceFlag, "config"}
+	} else if os.Getenv(EnvConfigPath) != "" {
+		config.Sources.ConfigPath = Source{SourceEnv, EnvConfigPath}
+	}
+
+	file, err := readConfigFile()
+	if err != nil {
+		return Config{}, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	fileSource := Source{SourceFile, path}
+
+	config.Profile, config.Sources.Profile = layer(
+		layerValue{file.ActiveProfile, fileSource},
+		fromEnv(EnvProfile),
+		fromFlag(overrides.Profile, "profile"),
+	)
+
+	// A token that is overridden does not need to be decrypted, so a
+	// deployment can set it without the passphrase of the saved one
+	var stored DatabaseConfig
+	var decryptErr error
+	if config.Profile != "" {
+		i := file.find(config.Profile)
+		if i < 0 {
+			return Config{}, fmt.Errorf("profile %q from %s not found in %s", config.Profile, config.Sources.Profile, path)
+		}
+		stored.DatabaseURL = file.Profiles[i].DatabaseURL
+		if decrypted, err := file.Profiles[i].decrypt(); err != nil {
+			decryptErr = err
+		} else {
+			stored.AuthToken = decrypted.AuthToken
+		}
+		fileSource.Name = fmt.Sprintf("%s (profile %s)", path, config.Profile)
+	}
+
+	// A profile chosen with -profile is an explicit choice, so its connection
+	// outranks the environment; only -db-url and -auth-token override it
+	explicit := config.Sources.Profile.Kind == SourceFlag
+	urls := []layerValue{{stored.DatabaseURL, fileSource}, fromEnv(EnvDatabaseURL)}
+	tokens := []layerValue{{stored.AuthToken, fileSource}, fromEnv(EnvAuthToken)}
+	if explicit {
+		urls[0], urls[1] = urls[1], urls[0]
+		tokens[0], tokens[1] = tokens[1], tokens[0]
+	}
+	config.DatabaseURL, config.Sources.DatabaseURL = layer(append(urls, fromFlag(overrides.DatabaseURL, "db-url"))...)
+	config.AuthToken, config.Sources.AuthToken = layer(append(tokens, fromFlag(overrides.AuthToken, "auth-token"))...)
+	if decryptErr != nil && (config.AuthToken == "" || explicit && config.Sources.AuthToken.Kind == SourceEnv) {
+		return Config{}, fmt.Errorf("error reading profile %q: %v", config.Profile, decryptErr)
+	}
+
+	timeout, source := layer(
+		layerValue{DefaultConnectTimeout.String(), Source{Kind: SourceDefault}},
+		fromEnv(EnvConnectTimeout),
+		fromFlag(overrides.ConnectTimeout, "connect-timeout"),
+	)
+	config.Sources.ConnectTimeout = source
+	config.ConnectTimeout, err = time.ParseDuration(timeout)
+	if err != nil || config.ConnectTimeout <= 0 {
+		return Config{}, fmt.Errorf("invalid connect timeout %q from %s", timeout, source)
+	}
+
+	return config, nil
+}
+
+// databaseSchemes are the URL schemes the libsql driver connects to
+var databaseSchemes = []string{"libsql", "https", "http", "wss", "ws"}
+
+// Validate checks that the configuration is complete. Errors name the source
+// of the offending value so that it can be fixed in the right place.
+func (c Config) Validate() error {
+	if c.DatabaseURL == "" {
+		return fmt.Errorf("database URL is missing; set it in %s, %s or with -db-url", c.ConfigPath, EnvDatabaseURL)
+	}
+	u, err := url.Parse(strings.TrimSpace(c.DatabaseURL))
+	if err != nil || u.Host == "" || !containsString(databaseSchemes, u.Scheme) {
+		return fmt.Errorf("database URL %q from %s must be a %s:// URL", c.DatabaseURL, c.Sources.DatabaseURL,
+			strings.Join(databaseSchemes, "://, "))
+	}
+
+	if c.AuthToken == "" {
+		return fmt.Errorf("authentication token is missing; set it in %s, %s or with -auth-token", c.ConfigPath, EnvAuthToken)
+	}
+	return nil
+}
+
+// Describe lists every value with its source, hiding the auth token
+func (c Config) Describe() string {
+	token := ""
+	if c.AuthToken != "" {
+		token = "(set)"
+	}
+
+	var b strings.Builder
+	for _, line := range []struct {
+		name, value string
+		source      Source
+	}{
+		{"config file", c.ConfigPath, c.Sources.ConfigPath},
+		{"profile", c.Profile, c.Sources.Profile},
+		{"database URL", c.DatabaseURL, c.Sources.DatabaseURL},
+		{"auth token", token, c.Sources.AuthToken},
+		{"connect timeout", c.ConnectTimeout.String(), c.Sources.ConnectTimeout},
+	} {
+		fmt.Fprintf(&b, "%-16s %-40s %s\n", line.name+":", line.value, line.source)
+	}
+	return b.String()
+}
+
+// DatabaseConfig returns the connection details of the configuration
+func (c Config) DatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{DatabaseURL: c.DatabaseURL, AuthToken: c.AuthToken}
+}
+
+// Open connects to the configured database
+func (c Config) Open() (*sql.DB, error) {
+	return connect(c.DatabaseConfig(), c.ConnectTimeout)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// shared/db/config_test.go
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupConfigFile points the config functions at a config file in a temporary
+// directory with the environment cleared
+func setupConfigFile(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	for _, name := range []string{EnvProfile, EnvDatabaseURL, EnvAuthToken, EnvConnectTimeout, PassphraseEnv} {
+		t.Setenv(name, "")
+	}
+	t.Setenv(EnvConfigPath, path)
+	return path
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	path := setupConfigFile(t)
+	fileConfig := DatabaseConfig{DatabaseURL: "libsql://file.turso.io", AuthToken: "file-token"}
+	if err := SaveProfile(Profile{Name: "Shop", DatabaseConfig: fileConfig}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+
+	config, err := LoadConfig(Overrides{})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.ConfigPath != path || config.Sources.ConfigPath.Kind != SourceEnv {
+		t.Errorf("Expected config path %s from the environment, got %s from %s", path, config.ConfigPath, config.Sources.ConfigPath)
+	}
+	if config.Profile != "Shop" || config.DatabaseURL != fileConfig.DatabaseURL || config.AuthToken != fileConfig.AuthToken {
+		t.Errorf("Expected the Shop profile, got %+v", config)
+	}
+	if config.Sources.DatabaseURL.Kind != SourceFile || config.ConnectTimeout != DefaultConnectTimeout ||
+		config.Sources.ConnectTimeout.Kind != SourceDefault {
+		t.Errorf("Expected file and default sources, got %+v", config.Sources)
+	}
+
+	// The environment overrides the file
+	t.Setenv(EnvDatabaseURL, "libsql://env.turso.io")
+	t.Setenv(EnvConnectTimeout, "30s")
+	config, err = LoadConfig(Overrides{})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.DatabaseURL != "libsql://env.turso.io" || config.Sources.DatabaseURL.Name != EnvDatabaseURL {
+		t.Errorf("Expected the URL from %s, got %s from %s", EnvDatabaseURL, config.DatabaseURL, config.Sources.DatabaseURL)
+	}
+	if config.AuthToken != fileConfig.AuthToken || config.Sources.AuthToken.Kind != SourceFile {
+		t.Errorf("Expected the token from the file, got %s", config.Sources.AuthToken)
+	}
+	if config.ConnectTimeout != 30*time.Second {
+		t.Errorf("Expected timeout 30s, got %v", config.ConnectTimeout)
+	}
+
+	// A profile chosen with -profile outranks the environment
+	config, err = LoadConfig(Overrides{Profile: "Shop"})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.DatabaseURL != fileConfig.DatabaseURL || config.Sources.DatabaseURL.Kind != SourceFile {
+		t.Errorf("Expected the URL of the chosen profile, got %s from %s", config.DatabaseURL, config.Sources.DatabaseURL)
+	}
+	t.Setenv(EnvAuthToken, "env-token")
+	if config, err = LoadConfig(Overrides{Profile: "Shop"}); err != nil || config.AuthToken != fileConfig.AuthToken {
+		t.Errorf("Expected the token of the chosen profile, got %s (%v)", config.Sources.AuthToken, err)
+	}
+	t.Setenv(EnvAuthToken, "")
+
+	// Flags override the environment
+	config, err = LoadConfig(Overrides{DatabaseURL: "libsql://flag.turso.io", AuthToken: "flag-token"})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.DatabaseURL != "libsql://flag.turso.io" || config.Sources.DatabaseURL.String() != "flag -db-url" {
+		t.Errorf("Expected the URL from -db-url, got %s from %s", config.DatabaseURL, config.Sources.DatabaseURL)
+	}
+	if config.AuthToken != "flag-token" || config.Sources.AuthToken.Kind != SourceFlag {
+		t.Errorf("Expected the token from -auth-token, got %s", config.Sources.AuthToken)
+	}
+	if strings.Contains(config.Describe(), "flag-token") {
+		t.Errorf("Expected the token to be hidden, got:\n%s", config.Describe())
+	}
+}
+
+func TestLoadConfig_ConfigFlag(t *testing.T) {
+	setupConfigFile(t)
+	path := filepath.Join(t.TempDir(), "other.json")
+	t.Cleanup(func() { SetConfigPath("") })
+
+	config, err := LoadConfig(Overrides{ConfigPath: path})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if config.ConfigPath != path || config.Sources.ConfigPath.String() != "flag -config" {
+		t.Errorf("Expected config path %s from -config, got %s from %s", path, config.ConfigPath, config.Sources.ConfigPath)
+	}
+
+	// Profiles are saved to the file given with -config from then on
+	if err := SaveDbConfig(DatabaseConfig{DatabaseURL: "libsql://other.turso.io", AuthToken: "token"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if config, err = LoadConfig(Overrides{}); err != nil || config.DatabaseURL != "libsql://other.turso.io" {
+		t.Errorf("Expected the config saved to %s, got %+v (%v)", path, config, err)
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	setupConfigFile(t)
+
+	t.Setenv(EnvProfile, "Stall")
+	if _, err := LoadConfig(Overrides{}); err == nil || !strings.Contains(err.Error(), EnvProfile) {
+		t.Errorf("Expected unknown profile error naming %s, got %v", EnvProfile, err)
+	}
+	t.Setenv(EnvProfile, "")
+
+	if _, err := LoadConfig(Overrides{ConnectTimeout: "soon"}); err == nil || !strings.Contains(err.Error(), "-connect-timeout") {
+		t.Errorf("Expected invalid timeout error naming -connect-timeout, got %v", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	setupConfigFile(t)
+
+	config, err := LoadConfig(Overrides{})
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "database URL is missing") {
+		t.Errorf("Expected missing URL error, got %v", err)
+	}
+
+	t.Setenv(EnvDatabaseURL, "postgres://db.example.com")
+	config, _ = LoadConfig(Overrides{})
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), EnvDatabaseURL) {
+		t.Errorf("Expected invalid URL error naming %s, got %v", EnvDatabaseURL, err)
+	}
+
+	t.Setenv(EnvDatabaseURL, "libsql://db.turso.io")
+	config, _ = LoadConfig(Overrides{})
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "token is missing") {
+		t.Errorf("Expected missing token error, got %v", err)
+	}
+
+	t.Setenv(EnvAuthToken, "token")
+	config, _ = LoadConfig(Overrides{})
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
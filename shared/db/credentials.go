@@ -0,0 +1,194 @@
+// shared/db/credentials.go
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase the
+// credentials are encrypted with. Without it a random key file is used.
+const PassphraseEnv = "ORDERFLOW_CONFIG_PASSPHRASE"
+
+// Key sources stored in the config file
+const (
+	KeySourcePassphrase = "passphrase"
+	KeySourceFile       = "file"
+)
+
+const keySize = 32 // AES-256
+
+// KeyProvider supplies the key credentials are encrypted with. A provider
+// backed by the operating system keyring can be added by implementing it.
+type KeyProvider interface {
+	// Source is stored in the config file to find the key again
+	Source() string
+	// Key returns the encryption key. salt is random per config file; providers
+	// that derive the key from a secret use it, others may ignore it.
+	Key(salt []byte) ([]byte, error)
+}
+
+// passphraseKey derives the key from a passphrase with scrypt
+type passphraseKey struct {
+	passphrase string
+}
+
+func (passphraseKey) Source() string { return KeySourcePassphrase }
+
+func (p passphraseKey) Key(salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(p.passphrase), salt, 1<<15, 8, 1, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving key: %v", err)
+	}
+	return key, nil
+}
+
+// fileKey keeps a random key in a file readable only by the current user,
+// next to the config file
+type fileKey struct {
+	path string
+}
+
+func (fileKey) Source() string { return KeySourceFile }
+
+func (f fileKey) Key([]byte) ([]byte, error) {
+	key, err := os.ReadFile(f.path)
+	if err == nil {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key file %s is damaged", f.path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading key file: %v", err)
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("error generating key: %v", err)
+	}
+	if err := writePrivateFile(f.path, key); err != nil {
+		return nil, fmt.Errorf("error writing key file: %v", err)
+	}
+	return key, nil
+}
+
+func keyFilePath() (string, error) {
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "database_config.key"), nil
+}
+
+// newKeyProvider returns the provider used for saving: the passphrase when it
+// is set, the key file otherwise
+func newKeyProvider() (KeyProvider, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphraseKey{passphrase: passphrase}, nil
+	}
+	path, err := keyFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return fileKey{path: path}, nil
+}
+
+// keyProviderFor returns the provider that encrypted a saved config
+func keyProviderFor(source string) (KeyProvider, error) {
+	switch source {
+	case KeySourcePassphrase:
+		passphrase := os.Getenv(PassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("database credentials are encrypted with a passphrase; set %s", PassphraseEnv)
+		}
+		return passphraseKey{passphrase: passphrase}, nil
+	case KeySourceFile:
+		path, err := keyFilePath()
+		if err != nil {
+			return nil, err
+		}
+		return fileKey{path: path}, nil
+	default:
+		return nil, fmt.Errorf("unknown key source %q", source)
+	}
+}
+
+// errDecrypt hides whether the key or the data was wrong
+var errDecrypt = errors.New("could not decrypt database credentials; the key or passphrase is wrong")
+
+// encryptSecret seals plaintext with AES-GCM and returns the nonce followed by
+// the ciphertext, base64 encoded
+func encryptSecret(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %v", err)
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decryptSecret reverses encryptSecret
+func decryptSecret(key []byte, encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errDecrypt
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errDecrypt
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errDecrypt
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// writePrivateFile replaces path with data readable only by the current user.
+// The data is written to a temporary file first so a crash never leaves a
+// half-written file behind.
+func writePrivateFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -2,10 +2,13 @@
 package db
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // DatabaseConfig stores the Turso database connection details
@@ -14,8 +17,54 @@ type DatabaseConfig struct {
 	AuthToken   string `json:"auth_token"`
 }
 
-// getConfigFilePath returns the path to the config file
+// DefaultProfileName names the profile of configs saved before profiles were
+// added, and of the first connection that is configured
+const DefaultProfileName = "Default"
+
+// Profile is a named database connection
+type Profile struct {
+	Name string
+	DatabaseConfig
+}
+
+// storedConfig is the stored form of a connection. The auth token is kept
+// encrypted; AuthToken is only read, from files written before encryption
+// was added.
+type storedConfig struct {
+	DatabaseURL        string `json:"database_url,omitempty"`
+	AuthToken          string `json:"auth_token,omitempty"`
+	EncryptedAuthToken string `json:"encrypted_auth_token,omitempty"`
+	KeySource          string `json:"key_source,omitempty"`
+	KeySalt            []byte `json:"key_salt,omitempty"`
+}
+
+type storedProfile struct {
+	Name string `json:"name"`
+	storedConfig
+}
+
+// configFile is the config file format. The embedded storedConfig is the
+// single connection of files written before profiles were added.
+type configFile struct {
+	ActiveProfile string          `json:"active_profile,omitempty"`
+	Profiles      []storedProfile `json:"profiles,omitempty"`
+	storedConfig
+}
+
+// getConfigFilePath returns the path to the config file: the one given with
+// SetConfigPath or ORDERFLOW_CONFIG, or the one in the user's config directory
 func getConfigFilePath() (string, error) {
+	path := configPathOverride
+	if path == "" {
+		path = os.Getenv(EnvConfigPath)
+	}
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
@@ -23,50 +72,290 @@ func getConfigFilePath() (string, error) {
 	appConfigDir := filepath.Join(configDir, "BlissfulBytesManagement")
 
 	// Create the directory if it doesn't exist
-	if err := os.MkdirAll(appConfigDir, 0755); err != nil {
+	if err := os.MkdirAll(appConfigDir, 0700); err != nil {
 		return "", err
 	}
 
 	return filepath.Join(appConfigDir, "database_config.json"), nil
 }
 
-// SaveDbConfig saves the database configuration to a JSON file
-func SaveDbConfig(config DatabaseConfig) error {
-	configPath, err := getConfigFilePath()
+// encryptConfig returns the stored form of config with the auth token
+// encrypted
+func encryptConfig(config DatabaseConfig) (storedConfig, error) {
+	stored := storedConfig{DatabaseURL: config.DatabaseURL}
+	if config.AuthToken == "" {
+		return stored, nil
+	}
+
+	provider, err := newKeyProvider()
 	if err != nil {
-		return err
+		return storedConfig{}, err
+	}
+	stored.KeySource = provider.Source()
+	stored.KeySalt = make([]byte, 16)
+	if _, err := rand.Read(stored.KeySalt); err != nil {
+		return storedConfig{}, fmt.Errorf("error generating salt: %v", err)
 	}
 
-	configJSON, err := json.Marshal(config)
+	key, err := provider.Key(stored.KeySalt)
 	if err != nil {
-		return err
+		return storedConfig{}, err
+	}
+	stored.EncryptedAuthToken, err = encryptSecret(key, config.AuthToken)
+	if err != nil {
+		return storedConfig{}, err
+	}
+	return stored, nil
+}
+
+// decrypt returns the connection with the auth token decrypted
+func (stored storedConfig) decrypt() (DatabaseConfig, error) {
+	config := DatabaseConfig{DatabaseURL: stored.DatabaseURL, AuthToken: stored.AuthToken}
+	if stored.EncryptedAuthToken == "" {
+		return config, nil
 	}
 
-	return os.WriteFile(configPath, configJSON, 0644)
+	provider, err := keyProviderFor(stored.KeySource)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+	key, err := provider.Key(stored.KeySalt)
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+	if config.AuthToken, err = decryptSecret(key, stored.EncryptedAuthToken); err != nil {
+		return DatabaseConfig{}, err
+	}
+	return config, nil
 }
 
-// LoadDbConfig loads the database configuration from the JSON file
-func LoadDbConfig() (DatabaseConfig, error) {
+// readConfigFile reads the config file, converting files written by earlier
+// versions: a single connection becomes the default profile and plaintext
+// tokens are encrypted.
+func readConfigFile() (configFile, error) {
 	configPath, err := getConfigFilePath()
 	if err != nil {
-		return DatabaseConfig{}, err
+		return configFile{}, err
 	}
 
 	configData, err := os.ReadFile(configPath)
 	if err != nil {
 		// If file doesn't exist, return an empty config
 		if os.IsNotExist(err) {
-			return DatabaseConfig{}, nil
+			return configFile{}, nil
+		}
+		return configFile{}, err
+	}
+
+	var file configFile
+	if err := json.Unmarshal(configData, &file); err != nil {
+		return configFile{}, err
+	}
+
+	converted := false
+	if file.DatabaseURL != "" || file.AuthToken != "" || file.EncryptedAuthToken != "" {
+		file.Profiles = append(file.Profiles, storedProfile{Name: DefaultProfileName, storedConfig: file.storedConfig})
+		if file.ActiveProfile == "" {
+			file.ActiveProfile = DefaultProfileName
+		}
+		file.storedConfig = storedConfig{}
+		converted = true
+	}
+	for i, profile := range file.Profiles {
+		if profile.AuthToken == "" {
+			continue
+		}
+		encrypted, err := encryptConfig(DatabaseConfig{DatabaseURL: profile.DatabaseURL, AuthToken: profile.AuthToken})
+		if err != nil {
+			log.Printf("Warning: Could not encrypt saved auth token: %v", err)
+			continue
+		}
+		file.Profiles[i].storedConfig = encrypted
+		converted = true
+	}
+
+	if converted {
+		if err := writeConfigFile(file); err != nil {
+			log.Printf("Warning: Could not update database config: %v", err)
+		}
+	}
+	return file, nil
+}
+
+// writeConfigFile saves the config file readable only by the current user
+func writeConfigFile(file configFile) error {
+	configPath, err := getConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	configJSON, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+
+	return writePrivateFile(configPath, configJSON)
+}
+
+func (file configFile) find(name string) int {
+	for i, profile := range file.Profiles {
+		if profile.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// ListProfiles returns the names of the saved profiles and the name of the
+// active one
+func ListProfiles() ([]string, string, error) {
+	file, err := readConfigFile()
+	if err != nil {
+		return nil, "", err
+	}
+
+	names := make([]string, len(file.Profiles))
+	for i, profile := range file.Profiles {
+		names[i] = profile.Name
+	}
+	return names, file.ActiveProfile, nil
+}
+
+// LoadProfile returns the connection of a saved profile
+func LoadProfile(name string) (DatabaseConfig, error) {
+	file, err := readConfigFile()
+	if err != nil {
+		return DatabaseConfig{}, err
+	}
+
+	i := file.find(name)
+	if i < 0 {
+		return DatabaseConfig{}, fmt.Errorf("profile %q not found", name)
+	}
+	return file.Profiles[i].decrypt()
+}
+
+// SaveProfile adds a profile or replaces the connection of an existing one.
+// The first profile saved becomes the active profile.
+func SaveProfile(profile Profile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+
+	file, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	stored, err := encryptConfig(profile.DatabaseConfig)
+	if err != nil {
+		return err
+	}
+
+	if i := file.find(profile.Name); i >= 0 {
+		file.Profiles[i].storedConfig = stored
+	} else {
+		file.Profiles = append(file.Profiles, storedProfile{Name: profile.Name, storedConfig: stored})
+	}
+	if file.ActiveProfile == "" {
+		file.ActiveProfile = profile.Name
+	}
+	return writeConfigFile(file)
+}
+
+// RenameProfile changes the name of a profile
+func RenameProfile(oldName, newName string) error {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return fmt.Errorf("profile name is required")
+	}
+
+	file, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	i := file.find(oldName)
+	if i < 0 {
+		return fmt.Errorf("profile %q not found", oldName)
+	}
+	if j := file.find(newName); j >= 0 && j != i {
+		return fmt.Errorf("profile %q already exists", newName)
+	}
+
+	file.Profiles[i].Name = newName
+	if file.ActiveProfile == oldName {
+		file.ActiveProfile = newName
+	}
+	return writeConfigFile(file)
+}
+
+// DeleteProfile removes a profile. When it was the active profile, the first
+// remaining profile becomes active.
+func DeleteProfile(name string) error {
+	file, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	i := file.find(name)
+	if i < 0 {
+		return fmt.Errorf("profile %q not found", name)
+	}
+
+	file.Profiles = append(file.Profiles[:i], file.Profiles[i+1:]...)
+	if file.ActiveProfile == name {
+		file.ActiveProfile = ""
+		if len(file.Profiles) > 0 {
+			file.ActiveProfile = file.Profiles[0].Name
 		}
+	}
+	return writeConfigFile(file)
+}
+
+// SetActiveProfile makes a profile the one LoadDbConfig, InitDB and the
+// command line use
+func SetActiveProfile(name string) error {
+	file, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	if file.find(name) < 0 {
+		return fmt.Errorf("profile %q not found", name)
+	}
+
+	file.ActiveProfile = name
+	return writeConfigFile(file)
+}
+
+// SaveDbConfig saves the connection of the active profile, creating the
+// default profile when there is none
+func SaveDbConfig(config DatabaseConfig) error {
+	_, active, err := ListProfiles()
+	if err != nil {
+		return err
+	}
+	if active == "" {
+		active = DefaultProfileName
+	}
+	return SaveProfile(Profile{Name: active, DatabaseConfig: config})
+}
+
+// LoadDbConfig loads the connection of the active profile. Without any
+// profiles an empty config is returned.
+func LoadDbConfig() (DatabaseConfig, error) {
+	file, err := readConfigFile()
+	if err != nil {
 		return DatabaseConfig{}, err
 	}
 
-	var config DatabaseConfig
-	err = json.Unmarshal(configData, &config)
-	return config, err
+	i := file.find(file.ActiveProfile)
+	if i < 0 {
+		return DatabaseConfig{}, nil
+	}
+	return file.Profiles[i].decrypt()
 }
 
-// UpdateEnvForDbConfig updates the environment variables with the saved config
+// UpdateEnvForDbConfig sets the environment variables that are not set yet
+// from the saved config, so that the environment keeps precedence
 func UpdateEnvForDbConfig() error {
 	config, err := LoadDbConfig()
 	if err != nil {
@@ -74,30 +363,22 @@ func UpdateEnvForDbConfig() error {
 	}
 
 	// Set environment variables if config exists
-	if config.DatabaseURL != "" {
-		os.Setenv("TURSO_DATABASE_URL", config.DatabaseURL)
+	if config.DatabaseURL != "" && os.Getenv(EnvDatabaseURL) == "" {
+		os.Setenv(EnvDatabaseURL, config.DatabaseURL)
 	}
-	if config.AuthToken != "" {
-		os.Setenv("TURSO_AUTH_TOKEN", config.AuthToken)
+	if config.AuthToken != "" && os.Getenv(EnvAuthToken) == "" {
+		os.Setenv(EnvAuthToken, config.AuthToken)
 	}
 
 	return nil
 }
 
-// ValidateDbConfig checks if the database configuration is complete
+// ValidateDbConfig checks if the database configuration resolved from the
+// config file and the environment is complete
 func ValidateDbConfig() error {
-	config, err := LoadDbConfig()
+	config, err := LoadConfig(Overrides{})
 	if err != nil {
 		return fmt.Errorf("error loading database config: %v", err)
 	}
-
-	if config.DatabaseURL == "" {
-		return fmt.Errorf("database URL is missing")
-	}
-
-	if config.AuthToken == "" {
-		return fmt.Errorf("authentication token is missing")
-	}
-
-	return nil
+	return config.Validate()
 }
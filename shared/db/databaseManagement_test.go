@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -234,3 +236,173 @@ func createMockConfig(t *testing.T, config DatabaseConfig) {
 		t.Fatalf("Failed to write config: %v", err)
 	}
 }
+
+// TestSaveDbConfig_Encrypted checks that the token is not stored in plain text
+// and that only the current user can read the file
+func TestSaveDbConfig_Encrypted(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	testConfig := DatabaseConfig{
+		DatabaseURL: "libsql://test-database.turso.io",
+		AuthToken:   "secret-token-12345",
+	}
+	if err := SaveDbConfig(testConfig); err != nil {
+		t.Fatalf("Failed to save database config: %v", err)
+	}
+	configPath, _ := getConfigFilePath()
+	defer os.Remove(configPath)
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if strings.Contains(string(data), testConfig.AuthToken) {
+		t.Errorf("Config file contains the plaintext token: %s", data)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(configPath)
+		if err != nil {
+			t.Fatalf("Failed to stat config: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+		}
+	}
+
+	loaded, err := LoadDbConfig()
+	if err != nil || loaded != testConfig {
+		t.Errorf("Expected %+v, got %+v (%v)", testConfig, loaded, err)
+	}
+}
+
+// TestLoadDbConfig_MigratesPlaintext checks that configs written by earlier
+// versions are encrypted on first load
+func TestLoadDbConfig_MigratesPlaintext(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	legacy := DatabaseConfig{
+		DatabaseURL: "libsql://legacy-database.turso.io",
+		AuthToken:   "legacy-token-12345",
+	}
+	createMockConfig(t, legacy)
+	configPath, _ := getConfigFilePath()
+	defer os.Remove(configPath)
+
+	loaded, err := LoadDbConfig()
+	if err != nil || loaded != legacy {
+		t.Fatalf("Expected %+v, got %+v (%v)", legacy, loaded, err)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if strings.Contains(string(data), legacy.AuthToken) {
+		t.Errorf("Expected plaintext token to be migrated, got: %s", data)
+	}
+	if loaded, err := LoadDbConfig(); err != nil || loaded != legacy {
+		t.Errorf("Expected migrated config to load, got %+v (%v)", loaded, err)
+	}
+}
+
+// TestSaveDbConfig_Passphrase checks that a passphrase-encrypted token needs
+// the same passphrase to load
+func TestSaveDbConfig_Passphrase(t *testing.T) {
+	t.Setenv(PassphraseEnv, "correct horse battery staple")
+	testConfig := DatabaseConfig{
+		DatabaseURL: "libsql://test-database.turso.io",
+		AuthToken:   "passphrase-token-12345",
+	}
+	if err := SaveDbConfig(testConfig); err != nil {
+		t.Fatalf("Failed to save database config: %v", err)
+	}
+	configPath, _ := getConfigFilePath()
+	defer os.Remove(configPath)
+
+	if loaded, err := LoadDbConfig(); err != nil || loaded != testConfig {
+		t.Errorf("Expected %+v, got %+v (%v)", testConfig, loaded, err)
+	}
+
+	t.Setenv(PassphraseEnv, "wrong passphrase")
+	if _, err := LoadDbConfig(); err == nil {
+		t.Error("Expected wrong passphrase to fail")
+	}
+
+	t.Setenv(PassphraseEnv, "")
+	if _, err := LoadDbConfig(); err == nil || !strings.Contains(err.Error(), PassphraseEnv) {
+		t.Errorf("Expected missing passphrase error, got %v", err)
+	}
+}
+
+// TestProfiles checks adding, switching, renaming and deleting profiles
+func TestProfiles(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	configPath, _ := getConfigFilePath()
+	os.Remove(configPath)
+	defer os.Remove(configPath)
+
+	shop := DatabaseConfig{DatabaseURL: "libsql://shop.turso.io", AuthToken: "shop-token"}
+	stall := DatabaseConfig{DatabaseURL: "libsql://stall.turso.io", AuthToken: "stall-token"}
+	if err := SaveProfile(Profile{Name: "Shop", DatabaseConfig: shop}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	if err := SaveProfile(Profile{Name: "Market Stall", DatabaseConfig: stall}); err != nil {
+		t.Fatalf("Failed to save profile: %v", err)
+	}
+	if err := SaveProfile(Profile{Name: " ", DatabaseConfig: stall}); err == nil {
+		t.Error("Expected profile without a name to be rejected")
+	}
+
+	names, active, err := ListProfiles()
+	if err != nil || strings.Join(names, ",") != "Shop,Market Stall" || active != "Shop" {
+		t.Fatalf("Expected Shop and Market Stall with Shop active, got %v %q (%v)", names, active, err)
+	}
+	if loaded, err := LoadDbConfig(); err != nil || loaded != shop {
+		t.Errorf("Expected active profile %+v, got %+v (%v)", shop, loaded, err)
+	}
+
+	if err := SetActiveProfile("Market Stall"); err != nil {
+		t.Fatalf("Failed to switch profile: %v", err)
+	}
+	if loaded, err := LoadDbConfig(); err != nil || loaded != stall {
+		t.Errorf("Expected active profile %+v, got %+v (%v)", stall, loaded, err)
+	}
+	if err := SetActiveProfile("Test"); err == nil {
+		t.Error("Expected unknown profile to fail")
+	}
+
+	if err := RenameProfile("Market Stall", "Shop"); err == nil {
+		t.Error("Expected rename to an existing name to fail")
+	}
+	if err := RenameProfile("Market Stall", "Stall"); err != nil {
+		t.Fatalf("Failed to rename profile: %v", err)
+	}
+	if loaded, err := LoadProfile("Stall"); err != nil || loaded != stall {
+		t.Errorf("Expected renamed profile %+v, got %+v (%v)", stall, loaded, err)
+	}
+
+	if err := DeleteProfile("Stall"); err != nil {
+		t.Fatalf("Failed to delete profile: %v", err)
+	}
+	names, active, _ = ListProfiles()
+	if len(names) != 1 || active != "Shop" {
+		t.Errorf("Expected Shop to become active, got %v %q", names, active)
+	}
+}
+
+// TestLoadDbConfig_SingleConnection checks that configs saved before profiles
+// were added become the default profile
+func TestLoadDbConfig_SingleConnection(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	legacy := DatabaseConfig{
+		DatabaseURL: "libsql://legacy-database.turso.io",
+		AuthToken:   "legacy-token-12345",
+	}
+	createMockConfig(t, legacy)
+	configPath, _ := getConfigFilePath()
+	defer os.Remove(configPath)
+
+	names, active, err := ListProfiles()
+	if err != nil || len(names) != 1 || names[0] != DefaultProfileName || active != DefaultProfileName {
+		t.Fatalf("Expected the %s profile, got %v %q (%v)", DefaultProfileName, names, active, err)
+	}
+	if loaded, err := LoadProfile(DefaultProfileName); err != nil || loaded != legacy {
+		t.Errorf("Expected %+v, got %+v (%v)", legacy, loaded, err)
+	}
+}
@@ -5,36 +5,40 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
 	"net/url"
-	"os"
 	"strings"
 	"time"
 
 	_ "github.com/tursodatabase/libsql-client-go/libsql"
 )
 
+// InitDB connects to the database configured by the config file and the
+// environment
 func InitDB() (*sql.DB, error) {
-	// First, try to update environment variables from the config file
-	if err := UpdateEnvForDbConfig(); err != nil {
-		log.Printf("Warning: Could not update config from file: %v", err)
+	config, err := LoadConfig(Overrides{})
+	if err != nil {
+		return nil, fmt.Errorf("error loading database config: %v", err)
 	}
 
 	// Validate database configuration
-	if err := ValidateDbConfig(); err != nil {
+	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("database configuration invalid: %v", err)
 	}
 
-	primaryUrl := os.Getenv("TURSO_DATABASE_URL")
-	authToken := os.Getenv("TURSO_AUTH_TOKEN")
+	return config.Open()
+}
 
-	// Log the connection details (for debugging)
-	// log.Printf("Connecting to database URL: %s", primaryUrl)
+// Connect opens and verifies a connection to the database and creates the
+// missing tables
+func Connect(config DatabaseConfig) (*sql.DB, error) {
+	return connect(config, DefaultConnectTimeout)
+}
 
+func connect(config DatabaseConfig, timeout time.Duration) (*sql.DB, error) {
 	// Construct the connection string
 	connectionString := fmt.Sprintf("%s?authToken=%s",
-		strings.TrimSpace(primaryUrl),
-		url.QueryEscape(authToken),
+		strings.TrimSpace(config.DatabaseURL),
+		url.QueryEscape(config.AuthToken),
 	)
 
 	// Open the database connection
@@ -49,7 +53,7 @@ func InitDB() (*sql.DB, error) {
 	db.SetConnMaxLifetime(5 * time.Minute)
 
 	// Verify the connection
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if err := db.PingContext(ctx); err != nil {
@@ -57,8 +61,18 @@ func InitDB() (*sql.DB, error) {
 		return nil, fmt.Errorf("error connecting to database: %v", err)
 	}
 
+	if err := CreateSchema(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// CreateSchema creates the application tables that do not exist yet
+func CreateSchema(db *sql.DB) error {
 	// Create products table
-	_, err = db.Exec(`
+	_, err := db.Exec(`
         CREATE TABLE IF NOT EXISTS products (
             id INTEGER PRIMARY KEY AUTOINCREMENT,
             name TEXT NOT NULL,
@@ -67,7 +81,7 @@ func InitDB() (*sql.DB, error) {
         )
     `)
 	if err != nil {
-		return nil, fmt.Errorf("error creating products table: %v", err)
+		return fmt.Errorf("error creating products table: %v", err)
 	}
 
 	_, err = db.Exec(`
@@ -78,7 +92,7 @@ func InitDB() (*sql.DB, error) {
     )
 `)
 	if err != nil {
-		return nil, fmt.Errorf("error creating representatives table: %v", err)
+		return fmt.Errorf("error creating representatives table: %v", err)
 	}
 
 	// Update orders table structure
@@ -95,11 +109,19 @@ func InitDB() (*sql.DB, error) {
         completed BOOLEAN,
         representative_id INTEGER,
         total_price REAL,
+        cancelled BOOLEAN DEFAULT false,
+        version INTEGER NOT NULL DEFAULT 1,
         FOREIGN KEY(representative_id) REFERENCES representatives(id)
     )
 `)
 	if err != nil {
-		return nil, fmt.Errorf("error creating orders table: %v", err)
+		return fmt.Errorf("error creating orders table: %v", err)
+	}
+	if err := addColumn(db, "orders", "cancelled", "BOOLEAN DEFAULT false"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "orders", "version", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
 	}
 
 	// Create order items table
@@ -115,8 +137,197 @@ func InitDB() (*sql.DB, error) {
     )
 `)
 	if err != nil {
-		return nil, fmt.Errorf("error creating order_items table: %v", err)
+		return fmt.Errorf("error creating order_items table: %v", err)
 	}
 
-	return db, nil
+	// Create order payments and notes tables
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS order_payments (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        order_id INTEGER NOT NULL,
+        amount REAL NOT NULL,
+        method TEXT NOT NULL DEFAULT '',
+        reference TEXT NOT NULL DEFAULT '',
+        paid_at DATETIME NOT NULL,
+        user TEXT NOT NULL,
+        FOREIGN KEY(order_id) REFERENCES orders(id)
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating order_payments table: %v", err)
+	}
+
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS order_notes (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        order_id INTEGER NOT NULL,
+        body TEXT NOT NULL,
+        user TEXT NOT NULL,
+        created_at DATETIME NOT NULL,
+        FOREIGN KEY(order_id) REFERENCES orders(id)
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating order_notes table: %v", err)
+	}
+
+	// Create webhook tables
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS webhook_endpoints (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        url TEXT NOT NULL,
+        secret TEXT NOT NULL,
+        events TEXT NOT NULL,
+        active BOOLEAN DEFAULT true,
+        created_at DATETIME
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating webhook_endpoints table: %v", err)
+	}
+
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS webhook_deliveries (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        endpoint_id INTEGER,
+        event TEXT NOT NULL,
+        payload TEXT NOT NULL,
+        status TEXT NOT NULL,
+        attempts INTEGER DEFAULT 0,
+        next_attempt_at DATETIME,
+        last_status_code INTEGER DEFAULT 0,
+        last_error TEXT DEFAULT '',
+        created_at DATETIME,
+        delivered_at DATETIME,
+        FOREIGN KEY(endpoint_id) REFERENCES webhook_endpoints(id)
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating webhook_deliveries table: %v", err)
+	}
+
+	// Create local user accounts
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS users (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        username TEXT NOT NULL UNIQUE COLLATE NOCASE,
+        password_hash TEXT NOT NULL,
+        role TEXT NOT NULL,
+        representative_id INTEGER,
+        active BOOLEAN DEFAULT true,
+        created_at DATETIME,
+        FOREIGN KEY(representative_id) REFERENCES representatives(id)
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating users table: %v", err)
+	}
+
+	// Create audit log, which only ever grows
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS audit_log (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        entity TEXT NOT NULL,
+        entity_id INTEGER NOT NULL,
+        action TEXT NOT NULL,
+        before_json TEXT,
+        after_json TEXT,
+        user TEXT NOT NULL,
+        created_at DATETIME NOT NULL
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating audit_log table: %v", err)
+	}
+
+	for _, statement := range []string{"UPDATE", "DELETE"} {
+		_, err = db.Exec(fmt.Sprintf(`
+    CREATE TRIGGER IF NOT EXISTS audit_log_no_%s
+    BEFORE %s ON audit_log
+    BEGIN
+        SELECT RAISE(ABORT, 'audit_log is append-only');
+    END
+`, strings.ToLower(statement), statement))
+		if err != nil {
+			return fmt.Errorf("error creating audit_log trigger: %v", err)
+		}
+	}
+
+	// Create settings shared by every machine using the database
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS settings (
+        key TEXT PRIMARY KEY,
+        value TEXT NOT NULL
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating settings table: %v", err)
+	}
+
+	// Count changes to the shared data, so that other machines using the
+	// same database can tell when to reload
+	_, err = db.Exec(`
+    CREATE TABLE IF NOT EXISTS data_version (
+        id INTEGER PRIMARY KEY CHECK (id = 1),
+        version INTEGER NOT NULL
+    )
+`)
+	if err != nil {
+		return fmt.Errorf("error creating data_version table: %v", err)
+	}
+	if _, err := db.Exec("INSERT OR IGNORE INTO data_version (id, version) VALUES (1, 0)"); err != nil {
+		return fmt.Errorf("error initializing data_version: %v", err)
+	}
+
+	for _, table := range VersionedTables {
+		for _, statement := range []string{"INSERT", "UPDATE", "DELETE"} {
+			_, err = db.Exec(fmt.Sprintf(`
+    CREATE TRIGGER IF NOT EXISTS %s_%s_data_version
+    AFTER %s ON %s
+    BEGIN
+        UPDATE data_version SET version = version + 1 WHERE id = 1;
+    END
+`, table, strings.ToLower(statement), statement, table))
+			if err != nil {
+				return fmt.Errorf("error creating %s data_version trigger: %v", table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// VersionedTables are the tables whose changes increase data_version
+var VersionedTables = []string{"products", "representatives", "orders", "order_items", "order_payments", "order_notes", "settings"}
+
+// addColumn adds a column to a table created before the column existed
+func addColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("error reading columns of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			defaultValue     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("error reading columns of %s: %v", table, err)
+		}
+		if strings.EqualFold(name, column) {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading columns of %s: %v", table, err)
+	}
+	rows.Close()
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("error adding %s to %s: %v", column, table, err)
+	}
+	return nil
 }
@@ -6,6 +6,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	_ "github.com/mattn/go-sqlite3" // Use SQLite for testing
 )
 
 // TestInitDB_MissingConfiguration tests that InitDB properly fails when configuration is missing
@@ -233,3 +235,91 @@ func contains(s, substr string) bool {
 //	// construction logic as a separate function that can be tested independently.
 //	// For now, this is just a placeholder to indicate what should be tested.
 // }
+
+// TestCreateSchema creates the tables in SQLite and checks that it can run twice
+func TestCreateSchema(t *testing.T) {
+	database, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer database.Close()
+	database.SetMaxOpenConns(1)
+
+	for i := 0; i < 2; i++ {
+		if err := CreateSchema(database); err != nil {
+			t.Fatalf("CreateSchema run %d failed: %v", i+1, err)
+		}
+	}
+
+	for _, table := range []string{"products", "representatives", "orders", "order_items",
+		"webhook_endpoints", "webhook_deliveries", "users", "audit_log", "data_version", "order_payments", "order_notes", "settings"} {
+		var name string
+		err := database.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&name)
+		if err != nil {
+			t.Errorf("Expected table %s to exist: %v", table, err)
+		}
+	}
+
+	// The audit log is append-only
+	_, err = database.Exec(`INSERT INTO audit_log (entity, entity_id, action, user, created_at)
+		VALUES ('product', 1, 'create', 'test', CURRENT_TIMESTAMP)`)
+	if err != nil {
+		t.Fatalf("Failed to insert audit entry: %v", err)
+	}
+	if _, err := database.Exec("UPDATE audit_log SET user = 'other'"); err == nil {
+		t.Error("Expected audit_log update to fail")
+	}
+	if _, err := database.Exec("DELETE FROM audit_log"); err == nil {
+		t.Error("Expected audit_log delete to fail")
+	}
+
+	// Every change to the shared data increases the data version
+	for _, statement := range []string{
+		"INSERT INTO products (name, price) VALUES ('Muffin', 10)",
+		"UPDATE products SET price = 12",
+		"DELETE FROM products",
+	} {
+		if _, err := database.Exec(statement); err != nil {
+			t.Fatalf("Failed to run %q: %v", statement, err)
+		}
+	}
+	var version int64
+	if err := database.QueryRow("SELECT version FROM data_version").Scan(&version); err != nil || version != 3 {
+		t.Errorf("Expected data version 3, got %d (%v)", version, err)
+	}
+}
+
+// TestCreateSchema_AddsColumns checks that tables created by earlier versions
+// get the columns added since
+func TestCreateSchema_AddsColumns(t *testing.T) {
+	database, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open test database: %v", err)
+	}
+	defer database.Close()
+	database.SetMaxOpenConns(1)
+
+	_, err = database.Exec(`
+		CREATE TABLE orders (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			client_name TEXT,
+			completed BOOLEAN
+		);
+		INSERT INTO orders (client_name, completed) VALUES ('Bob', false);
+	`)
+	if err != nil {
+		t.Fatalf("Failed to create old orders table: %v", err)
+	}
+
+	if err := CreateSchema(database); err != nil {
+		t.Fatalf("CreateSchema failed: %v", err)
+	}
+
+	var (
+		cancelled bool
+		version   int64
+	)
+	if err := database.QueryRow("SELECT cancelled, version FROM orders").Scan(&cancelled, &version); err != nil || cancelled || version != 1 {
+		t.Errorf("Expected existing order to be not cancelled at version 1, got %v, %d (%v)", cancelled, version, err)
+	}
+}